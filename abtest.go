@@ -0,0 +1,107 @@
+package fastroute
+
+import "net/http"
+
+// ABOption configures ABTest.
+type ABOption func(*abTest)
+
+type abTest struct {
+	percent float64
+	cookie  http.Cookie
+}
+
+// ABRatio sets the percentage of new visitors,
+// assigned to variant "b". Default is 50.
+func ABRatio(percent float64) ABOption {
+	return func(t *abTest) {
+		t.percent = percent
+	}
+}
+
+// ABCookie sets attributes, like Path, Domain, MaxAge,
+// Secure or SameSite, of the assignment cookie. Name
+// and Value are always set by ABTest.
+func ABCookie(cookie http.Cookie) ABOption {
+	return func(t *abTest) {
+		t.cookie = cookie
+	}
+}
+
+// ABTest splits visitors between variant "a" and "b"
+// routers and keeps them in the assigned variant by
+// cookie with the given name.
+//
+// New visitors are assigned by the hash of their IP
+// address, so the clients having cookies disabled
+// are consistently served the same variant as well.
+// The assignment cookie is set on the response before
+// the matched handler is served. Handlers may read the
+// assigned variant by ABVariant, the one of a new visitor
+// is carried by the context of request, which is served
+// as a shallow copy. Request itself is not changed, so
+// the following routers do not see the assignment.
+//
+// If the assigned variant router does not match the
+// request, the other one is tried. The variant, which
+// serves the request, is the one handlers read and the
+// one a new visitor is assigned to, while the visitors
+// already assigned keep their cookie.
+func ABTest(name string, a, b Router, options ...ABOption) Router {
+	t := &abTest{percent: 50, cookie: http.Cookie{Path: "/"}}
+	for _, option := range options {
+		option(t)
+	}
+
+	return chain{RouterFunc(func(req *http.Request) http.Handler {
+		variant := ABVariant(req, name)
+		assigned := variant != ""
+		if !assigned {
+			variant = "a"
+			if float64(mix(hash(RemoteIP(req)))%10000) < t.percent*100 {
+				variant = "b"
+			}
+		}
+
+		first, second, other := a, b, "b"
+		if variant == "b" {
+			first, second, other = b, a, "a"
+		}
+		h := first.Route(req)
+		if h == nil {
+			if h = second.Route(req); h == nil {
+				return nil
+			}
+			variant = other // fell back to serving it
+			if assigned {
+				return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+					serveVariant(w, req, name, variant, h)
+				})
+			}
+		}
+		if assigned {
+			return h
+		}
+
+		cookie := t.cookie
+		cookie.Name, cookie.Value = name, variant
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			http.SetCookie(w, &cookie)
+			serveVariant(w, req, name, variant, h)
+		})
+	}), []Router{a, b}}
+}
+
+// ABVariant returns the variant, either "a" or "b",
+// assigned to the request by ABTest with the given name.
+// Empty string is returned if variant is not assigned.
+func ABVariant(req *http.Request, name string) string {
+	if variant := assignedVariant(req, name); variant != "" {
+		return variant
+	}
+	for _, c := range req.Cookies() {
+		if c.Name == name && (c.Value == "a" || c.Value == "b") {
+			return c.Value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,120 @@
+package fastroute_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+func TestABTest(t *testing.T) {
+	t.Parallel()
+
+	variant := func(name string) fastroute.Router {
+		return fastroute.New("/landing/:id", func(w http.ResponseWriter, req *http.Request) {
+			fmt.Fprintf(w, "%s %s %s", name, fastroute.ABVariant(req, "exp"), fastroute.Parameters(req).ByName("id"))
+		})
+	}
+	router := fastroute.ABTest("exp", variant("a"), variant("b"),
+		fastroute.ABRatio(30),
+		fastroute.ABCookie(http.Cookie{Path: "/landing", MaxAge: 3600, Secure: true}),
+	)
+
+	serve := func(remoteAddr, cookie string) *httptest.ResponseRecorder {
+		req, err := http.NewRequest("GET", "/landing/5", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.RemoteAddr = remoteAddr
+		if cookie != "" {
+			req.Header.Set("Cookie", cookie)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	var b int
+	for i := 0; i < 200; i++ {
+		addr := fmt.Sprintf("192.168.%d.%d:4000", i/100, i%100)
+		w := serve(addr, "")
+
+		body := w.Body.String()
+		if body != "a a 5" && body != "b b 5" {
+			t.Fatalf("unexpected response: %s", body)
+		}
+		if body[0] == 'b' {
+			b++
+		}
+
+		set := w.Header().Get("Set-Cookie")
+		expected := fmt.Sprintf("exp=%c; Path=/landing; Max-Age=3600; Secure", body[0])
+		if set != expected {
+			t.Fatalf("expected cookie: %s, but got: %s", expected, set)
+		}
+
+		// visitor having cookies disabled stays in the same variant
+		if again := serve(addr, ""); again.Body.String() != body {
+			t.Fatalf("expected visitor without cookie to remain in variant: %s", body)
+		}
+	}
+	if b < 40 || b > 80 {
+		t.Fatalf("expected approximately 30%% of visitors to be assigned to b, but got: %d", b)
+	}
+
+	w := serve("10.0.0.1:4000", "exp=b")
+	if w.Body.String() != "b b 5" || w.Header().Get("Set-Cookie") != "" {
+		t.Fatalf("expected assigned visitor to be served variant b without cookie, but got: %s", w.Body.String())
+	}
+
+	w = serve("10.0.0.1:4000", "exp=x")
+	if !strings.HasPrefix(w.Header().Get("Set-Cookie"), "exp="+w.Body.String()[:1]) {
+		t.Fatal("expected visitor with invalid assignment to be reassigned")
+	}
+
+	// request is not changed, when neither variant matches,
+	// nor when a new visitor is served
+	for _, path := range []string{"/other", "/landing/5"} {
+		req, _ := http.NewRequest("GET", path, nil)
+		req.RemoteAddr = "10.0.0.2:4000"
+		router.ServeHTTP(httptest.NewRecorder(), req)
+		if cookies := req.Header["Cookie"]; len(cookies) != 0 {
+			t.Fatalf("expected request of %s not to carry assignment cookie, but got: %v", path, cookies)
+		}
+	}
+}
+
+func TestABTestFallback(t *testing.T) {
+	t.Parallel()
+
+	variant := func(name, path string) fastroute.Router {
+		return fastroute.New(path, func(w http.ResponseWriter, req *http.Request) {
+			fmt.Fprintf(w, "%s %s", name, fastroute.ABVariant(req, "exp"))
+		})
+	}
+	router := fastroute.ABTest("exp", variant("a", "/only-a"), variant("b", "/only-b"))
+
+	for _, c := range []struct{ path, cookie, body, set string }{
+		{"/only-a", "exp=b", "a a", ""},
+		{"/only-b", "exp=a", "b b", ""},
+		{"/only-a", "", "a a", "exp=a; Path=/"},
+		{"/only-b", "", "b b", "exp=b; Path=/"},
+	} {
+		for i := 0; i < 10; i++ { // new visitors of either variant
+			req, _ := http.NewRequest("GET", c.path, nil)
+			req.RemoteAddr = fmt.Sprintf("10.0.0.%d:4000", i)
+			if c.cookie != "" {
+				req.Header.Set("Cookie", c.cookie)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			if w.Body.String() != c.body || w.Header().Get("Set-Cookie") != c.set {
+				t.Fatalf("expected %s to be attributed to the served variant: %q %q, but got: %q %q",
+					c.path, c.body, c.set, w.Body.String(), w.Header().Get("Set-Cookie"))
+			}
+		}
+	}
+}
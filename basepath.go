@@ -0,0 +1,190 @@
+package fastroute
+
+import (
+	"net/http"
+	"path"
+	"strings"
+)
+
+// BasePathOption configures BasePath.
+type BasePathOption func(*basePath)
+
+type basePath struct {
+	base      string
+	required  bool
+	forwarded map[string]bool // allowed forwarded prefixes
+}
+
+// BaseRequired matches only requests, which path has
+// the base path, rather than the ones stripped of it too.
+func BaseRequired() BasePathOption {
+	return func(b *basePath) {
+		b.required = true
+	}
+}
+
+// BaseForwardedPrefix takes the base path from
+// X-Forwarded-Prefix header, set by a proxy, which
+// stripped it, for deployments under several prefixes.
+// Only the allowed prefixes, like "/eu" or "/us", and the
+// base path itself are taken, compared once cleaned, other
+// values are ignored, so clients cannot choose patterns,
+// which statistics and logs are kept by.
+func BaseForwardedPrefix(allowed ...string) BasePathOption {
+	return func(b *basePath) {
+		b.forwarded = make(map[string]bool, len(allowed))
+		for _, prefix := range allowed {
+			b.forwarded[normalizeBase(prefix)] = true
+		}
+	}
+}
+
+// BasePath wraps router in order to serve it under base
+// path, like "/app", behind a proxy, which may or may not
+// strip the prefix:
+//
+//	fastroute.BasePath("/app", routes)
+//
+// Requests are matched by path with base removed, both
+// "/app/users/5" and "/users/5" match "/users/:id", unless
+// BaseRequired is given. Base is removed by whole segments,
+// "/app" does not match "/apples".
+//
+// Pattern of the matched request includes the base path,
+// like "/app/users/:id", the externally visible one, so are
+// the patterns listed by Patterns and Inspect. When base is
+// taken from X-Forwarded-Prefix header, by BaseForwardedPrefix
+// option, Pattern includes the forwarded one, if allowed.
+//
+// Handler is served with the path base is removed from,
+// which is restored once it is served. Parameters are bound
+// for every matched request, even by a static route.
+func BasePath(base string, router Router, options ...BasePathOption) Router {
+	b := &basePath{base: normalizeBase(base)}
+	for _, option := range options {
+		option(b)
+	}
+	pool := newParamsPool(maxParams(router))
+
+	return based{wrapper{RouterFunc(func(req *http.Request) http.Handler {
+		base := b.of(req)
+
+		path, rawPath := req.URL.Path, req.URL.RawPath
+		p, rp, ok := trimBase(path, rawPath, base)
+		if !ok && b.required {
+			return nil
+		}
+
+		ps := boundParams(req)
+		bound := ps != nil
+		if !bound {
+			ps = pool.get()
+			ps.ReadCloser = req.Body
+			req.Body = ps
+			ps.binding = true
+		}
+		n, pattern := len(ps.params), ps.pattern
+		ps.pattern = p // matched by static route
+
+		req.URL.Path, req.URL.RawPath = p, rp
+		h := router.Route(req)
+		req.URL.Path, req.URL.RawPath = path, rawPath
+		ps.binding = bound
+		if h == nil {
+			if bound {
+				ps.params, ps.pattern = ps.params[:n], pattern
+			} else {
+				ps.reset(req)
+			}
+			return nil
+		}
+		ps.pattern = base + ps.pattern
+
+		if p != path || rp != rawPath {
+			next := h
+			h = http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				path, rawPath := req.URL.Path, req.URL.RawPath
+				req.URL.Path, req.URL.RawPath = p, rp
+				defer func() {
+					req.URL.Path, req.URL.RawPath = path, rawPath
+				}()
+				next.ServeHTTP(w, req)
+			})
+		}
+		if bound {
+			return h
+		}
+		return ps.releasing(req, h)
+	}), router}, b.base}
+}
+
+// base path of request, the forwarded one if allowed
+func (b *basePath) of(req *http.Request) string {
+	if b.forwarded == nil {
+		return b.base
+	}
+	prefix := req.Header.Get("X-Forwarded-Prefix")
+	if prefix == "" {
+		return b.base
+	}
+	if prefix = normalizeBase(path.Clean("/" + prefix)); b.forwarded[prefix] {
+		return prefix
+	}
+	return b.base
+}
+
+// base path having a leading slash, but not a trailing
+// one, so it may be joined with a pattern, empty for root
+func normalizeBase(base string) string {
+	if base = strings.Trim(base, "/"); base == "" {
+		return ""
+	}
+	return "/" + base
+}
+
+// removes base from path and raw path, if both have it
+// as whole segments, otherwise they are left as they are
+func trimBase(path, rawPath, base string) (string, string, bool) {
+	p, ok := trimSegments(path, base)
+	if !ok {
+		return path, rawPath, false
+	}
+	if rawPath == "" {
+		return p, "", true
+	}
+	rp, ok := trimSegments(rawPath, base)
+	if !ok {
+		return path, rawPath, false
+	}
+	return p, rp, true
+}
+
+func trimSegments(path, base string) (string, bool) {
+	switch {
+	case base == "":
+		return path, true
+	case path == base:
+		return "/", true
+	case strings.HasPrefix(path, base) && path[len(base)] == '/':
+		return path[len(base):], true
+	}
+	return path, false
+}
+
+type based struct {
+	wrapper
+	base string
+}
+
+func (b based) Patterns() []string {
+	patterns, _ := Patterns(b)
+	return patterns
+}
+
+func (b based) inspect() ([]RouteInfo, bool) {
+	routes, ok := b.wrapper.inspect()
+	for i := range routes {
+		routes[i].Pattern = b.base + routes[i].Pattern
+	}
+	return routes, ok
+}
@@ -0,0 +1,63 @@
+package fastroute_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+func TestBasePath(t *testing.T) {
+	t.Parallel()
+
+	handler := func(w http.ResponseWriter, req *http.Request) {
+		fmt.Fprintf(w, "%s %s %s", req.URL.Path, fastroute.Pattern(req), fastroute.Parameters(req))
+	}
+	routes := fastroute.Chain(
+		fastroute.New("/", handler),
+		fastroute.New("/status", handler),
+		fastroute.New("/users/:id", handler),
+	)
+
+	cases := []struct {
+		router       fastroute.Router
+		path, prefix string
+		body         string
+	}{
+		{fastroute.BasePath("/app/", routes), "/app/users/5", "", `/users/5 /app/users/:id [id="5"]`},
+		{fastroute.BasePath("/app/", routes), "/users/5", "", `/users/5 /app/users/:id [id="5"]`},
+		{fastroute.BasePath("/app/", routes), "/app/status", "", `/status /app/status []`},
+		{fastroute.BasePath("/app/", routes), "/status", "", `/status /app/status []`},
+		{fastroute.BasePath("/app/", routes), "/app", "", `/ /app/ []`},
+		{fastroute.BasePath("/app/", routes), "/apples", "", "404 page not found\n"},
+		{fastroute.BasePath("/app", routes, fastroute.BaseRequired()), "/status", "", "404 page not found\n"},
+		{fastroute.BasePath("/app", routes, fastroute.BaseRequired()), "/app/status", "", `/status /app/status []`},
+		{fastroute.BasePath("/app", routes, fastroute.BaseForwardedPrefix("/tenant")), "/status", "/tenant/", `/status /tenant/status []`},
+		{fastroute.BasePath("/app", routes, fastroute.BaseForwardedPrefix("/tenant")), "/status", "/x/../tenant", `/status /tenant/status []`},
+		{fastroute.BasePath("/app", routes, fastroute.BaseForwardedPrefix("/tenant")), "/status", "/evil", `/status /app/status []`},
+		{fastroute.BasePath("/app", routes, fastroute.BaseForwardedPrefix("/tenant")), "/app/users/7", "", `/users/7 /app/users/:id [id="7"]`},
+		{fastroute.Host(":tenant.example.com", fastroute.BasePath("/app", routes)), "/app/users/9", "", `/users/9 /app/users/:id [tenant="acme" id="9"]`},
+	}
+	for i, c := range cases {
+		req, _ := http.NewRequest("GET", c.path, nil)
+		req.Host = "acme.example.com"
+		if c.prefix != "" {
+			req.Header.Set("X-Forwarded-Prefix", c.prefix)
+		}
+		w := httptest.NewRecorder()
+		c.router.ServeHTTP(w, req)
+		if actual := w.Body.String(); actual != c.body {
+			t.Fatalf("expected response %q, but got %q, at case %d", c.body, actual, i)
+		}
+		if req.URL.Path != c.path || len(fastroute.Parameters(req)) != 0 {
+			t.Fatalf("expected request to be restored and recycled, at case %d", i)
+		}
+	}
+
+	patterns, _ := fastroute.Patterns(fastroute.BasePath("/app", routes))
+	if fmt.Sprint(patterns) != "[/app/ /app/status /app/users/:id]" {
+		t.Fatalf("expected patterns to include base path, but got: %v", patterns)
+	}
+}
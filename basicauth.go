@@ -0,0 +1,65 @@
+package fastroute
+
+import (
+	"encoding/base64"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// BasicAuth wraps router in order to serve the matched
+// request only, if it has Basic credentials accepted by
+// check, for example to gate admin endpoints:
+//
+//	fastroute.BasicAuth(admin, func(user, pass string) bool {
+//		return user == "admin" && pass == password
+//	}, "admin")
+//
+// Requests not matched by router fall through. If the
+// Authorization header is missing, malformed, or check
+// rejects the credentials, the route is claimed and the
+// request is denied with 401 Unauthorized, asking for
+// credentials of the realm by WWW-Authenticate header.
+// Parameters of denied requests are recycled before the
+// 401 handler is returned.
+//
+// Credentials are compared by check, it should do so in
+// constant time, see crypto/subtle.
+func BasicAuth(router Router, check func(user, pass string) bool, realm string) Router {
+	challenge := "Basic realm=" + strconv.Quote(realm)
+	unauthorized := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("WWW-Authenticate", challenge)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+	})
+
+	return wrapper{RouterFunc(func(req *http.Request) http.Handler {
+		h := router.Route(req)
+		if h == nil {
+			return nil
+		}
+		if user, pass, ok := basicAuth(req.Header.Get("Authorization")); ok && check(user, pass) {
+			return h
+		}
+		Recycle(req)
+		return unauthorized
+	}), router}
+}
+
+// parses credentials of Basic authorization header,
+// like http.Request.BasicAuth does in later releases
+func basicAuth(header string) (user, pass string, ok bool) {
+	const prefix = "Basic "
+	if len(header) < len(prefix) || !strings.EqualFold(header[:len(prefix)], prefix) {
+		return
+	}
+	decoded, err := base64.StdEncoding.DecodeString(header[len(prefix):])
+	if err != nil {
+		return
+	}
+	credentials := string(decoded)
+	pos := strings.IndexByte(credentials, ':')
+	if pos == -1 {
+		return
+	}
+	return credentials[:pos], credentials[pos+1:], true
+}
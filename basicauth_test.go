@@ -0,0 +1,59 @@
+package fastroute_test
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+func TestBasicAuth(t *testing.T) {
+	t.Parallel()
+	handler := func(w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte("admin " + fastroute.Parameters(req).ByName("page")))
+	}
+	check := func(user, pass string) bool {
+		return user == "admin" && pass == "s3:cret"
+	}
+	router := fastroute.BasicAuth(fastroute.New("/admin/:page", handler), check, "admin")
+
+	basic := func(credentials string) string {
+		return "Basic " + base64.StdEncoding.EncodeToString([]byte(credentials))
+	}
+	cases := []struct {
+		path, authorization string
+		code                int
+		body                string
+	}{
+		{"/admin/users", basic("admin:s3:cret"), 200, "admin users"},
+		{"/admin/users", "basic " + base64.StdEncoding.EncodeToString([]byte("admin:s3:cret")), 200, "admin users"},
+		{"/admin/users", "", 401, "Unauthorized\n"},
+		{"/admin/users", basic("admin:wrong"), 401, "Unauthorized\n"},
+		{"/admin/users", basic("admin"), 401, "Unauthorized\n"},
+		{"/admin/users", "Basic !not-base64", 401, "Unauthorized\n"},
+		{"/admin/users", "Bearer token", 401, "Unauthorized\n"},
+		{"/public", basic("admin:s3:cret"), 404, "404 page not found\n"},
+	}
+	for i, c := range cases {
+		req, _ := http.NewRequest("GET", c.path, nil)
+		if c.authorization != "" {
+			req.Header.Set("Authorization", c.authorization)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != c.code || w.Body.String() != c.body {
+			t.Fatalf("expected response: %d %q, but got: %d %q, case: %d", c.code, c.body, w.Code, w.Body.String(), i)
+		}
+		if challenge := w.Header().Get("WWW-Authenticate"); c.code == 401 && challenge != `Basic realm="admin"` {
+			t.Fatalf("expected WWW-Authenticate challenge, but got: %q, case: %d", challenge, i)
+		}
+	}
+
+	req, _ := http.NewRequest("GET", "/admin/users", nil)
+	if h := router.Route(req); h == nil || len(fastroute.Parameters(req)) != 0 {
+		t.Fatalf("expected parameters of unauthorized request to be recycled, but got: %v", fastroute.Parameters(req))
+	}
+}
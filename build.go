@@ -0,0 +1,136 @@
+package fastroute
+
+import (
+	"fmt"
+	"strings"
+)
+
+// BuildErrors lists all the errors of route defs
+// given to Build, each naming the index of def.
+type BuildErrors []error
+
+func (errs BuildErrors) Error() string {
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Unwrap returns the errors, so they can be
+// inspected by errors.Is and errors.As.
+func (errs BuildErrors) Unwrap() []error {
+	return errs
+}
+
+// Build creates Router for route defs, which usually come
+// from configuration, the same way Bulk does. All the defs
+// are validated first and every mistake is reported at once
+// by BuildErrors, instead of failing on the first one:
+//
+//   - invalid method, path pattern or handler
+//   - name already given to another def
+//   - conflict with a preceding def, which has the same
+//     pattern shape, differing only in parameter names,
+//     and accepts any of the def methods, so the def
+//     would never be matched. A def having query section
+//     may not match, so it does not conflict with the
+//     following ones
+func Build(defs []RouteDef) (Router, error) {
+	var errs BuildErrors
+	fail := func(i int, format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf("route %d: "+format, append([]interface{}{i}, args...)...))
+	}
+
+	names := map[string]int{}
+	shapes := map[string][]int{} // indexes of defs by pattern shape
+	methods := make([][]string, len(defs))
+	for i, def := range defs {
+		if def.Method != "" {
+			methods[i] = strings.Split(def.Method, "|")
+			for _, m := range methods[i] {
+				if !validMethod(m) {
+					fail(i, "not a valid request method: %s", def.Method)
+					break
+				}
+			}
+		}
+		if _, err := handlerOf(def.Handler); err != nil {
+			fail(i, "%s", err)
+		}
+		if def.Name != "" {
+			if j, ok := names[def.Name]; ok {
+				fail(i, "name %q is already given to route %d", def.Name, j)
+			} else {
+				names[def.Name] = i
+			}
+		}
+
+		pattern, path := rooted(def.Path), rooted(def.Path)
+		q := queryStart(pattern)
+		if q != -1 {
+			path = pattern[:q]
+			if _, err := parseQuery(pattern[q+1:]); err != nil {
+				fail(i, "%s: %s", err, pattern)
+				continue
+			}
+		}
+		segments, err := parse(path)
+		if err != nil {
+			fail(i, "%s", err)
+			continue
+		}
+		shape := patternShape(path, segments)
+		for _, j := range shapes[shape] {
+			if overlap(methods[j], methods[i]) {
+				fail(i, "%s conflicts with %s of route %d, which is matched first", pattern, rooted(defs[j].Path), j)
+				break
+			}
+		}
+		if q == -1 {
+			shapes[shape] = append(shapes[shape], i) // others may not match
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return Bulk(defs)
+}
+
+// pattern with parameter names left out, patterns
+// of the same shape match the same paths
+func patternShape(pattern string, segments []string) string {
+	if strings.IndexAny(pattern, ":*\\") == -1 {
+		return pattern
+	}
+	shape := make([]string, len(segments))
+	for i, segment := range segments {
+		if segment[1] == ':' || segment[1] == '*' {
+			if pos := strings.IndexByte(segment, '\\'); pos != -1 {
+				segment = segment[:2] + segment[pos:] // keep literal suffix
+			} else {
+				segment = segment[:2]
+			}
+		}
+		shape[i] = segment
+	}
+	if pattern[len(pattern)-1] == '/' {
+		shape = append(shape, "/")
+	}
+	return strings.Join(shape, "")
+}
+
+// whether any method is accepted by both
+// scopes, nil scope accepts all methods
+func overlap(a, b []string) bool {
+	if a == nil || b == nil {
+		return true
+	}
+	for _, m := range b {
+		if hasMethod(a, m) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,59 @@
+package fastroute_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+func TestBuild(t *testing.T) {
+	t.Parallel()
+	handler := func(w http.ResponseWriter, req *http.Request) {}
+
+	router, err := fastroute.Build([]fastroute.RouteDef{
+		{Method: "GET", Path: "/users/:id", Handler: handler, Name: "user"},
+		{Method: "PUT|DELETE", Path: "/users/:name", Handler: handler},
+		{Path: "/users/me", Handler: handler, Name: "me"},
+		{Path: "/users/:id/", Handler: handler},
+		{Method: "GET", Path: "/search?q=:query", Handler: handler},
+		{Method: "GET", Path: "/search", Handler: handler}, // when query is missing
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual := routedBy(router, "DELETE", "/users/5"); actual != "/users/:name name=5" {
+		t.Fatalf("expected built router to match, but got: %q", actual)
+	}
+
+	_, err = fastroute.Build([]fastroute.RouteDef{
+		{Method: "GET", Path: "/users/:id", Handler: handler, Name: "user"},
+		{Method: "GET,POST", Path: "/users", Handler: handler},
+		{Path: "/files/*path/:name", Handler: handler},
+		{Path: "/status", Handler: "status"},
+		{Method: "POST|GET", Path: "users/:name", Handler: handler, Name: "user"},
+		{Path: "/:any/:id", Handler: nil},
+		{Path: "/search?q", Handler: handler},
+	})
+	errs, ok := err.(fastroute.BuildErrors)
+	if !ok {
+		t.Fatalf("expected build errors, but got: %v", err)
+	}
+
+	expected := []string{
+		"route 1: not a valid request method: GET,POST",
+		"route 2: match all, may only be followed by static segments in pattern: /files/*path/:name",
+		"route 3: not a handler given: string - status",
+		`route 4: name "user" is already given to route 0`,
+		"route 4: /users/:name conflicts with /users/:id of route 0, which is matched first",
+		"route 5: given handler cannot be: nil",
+		"route 6: query parameter must be given as key=:name: /search?q",
+	}
+	if err.Error() != strings.Join(expected, "\n") {
+		t.Fatalf("expected all errors to be reported, but got:\n%s", err)
+	}
+	if len(errs.Unwrap()) != len(expected) {
+		t.Fatalf("expected %d errors, but got: %d", len(expected), len(errs.Unwrap()))
+	}
+}
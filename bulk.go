@@ -0,0 +1,203 @@
+package fastroute
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// RouteDef defines a route, as registered by Bulk and Build.
+type RouteDef struct {
+	// Method scopes route to requests of the given
+	// method, several may be delimited by pipe, like
+	// "GET|HEAD". Route matches any method if empty.
+	Method string
+
+	// Path is the path pattern, as given to New.
+	Path string
+
+	// Handler is the handler, in any of the
+	// formats New accepts.
+	Handler interface{}
+
+	// Name optionally identifies the route, it
+	// must be unique among defs given to Build.
+	Name string
+}
+
+// Bulk creates Router for many routes at once, which
+// behaves as the Chain of routes created by New, each
+// scoped by MethodGroup if its method is given.
+//
+// It is meant for tens of thousands of generated routes.
+// Routes are kept in a single slice walked by one router,
+// instead of a router with its own closures per route.
+// Patterns are split to segments sliced from the pattern
+// itself, method scopes are shared and all dynamic routes
+// draw parameters from a single pool, sized for the route
+// having most of them. So registration allocates a small
+// fraction of what New does.
+//
+// It returns an error for the first invalid def,
+// naming its index.
+func Bulk(defs []RouteDef) (Router, error) {
+	routes := make([]bulkRoute, len(defs))
+	methods := map[string][]string{} // shared by routes of the same method
+	var num int
+	for i, def := range defs {
+		r := &routes[i]
+		h, err := handlerOf(def.Handler)
+		if err != nil {
+			return nil, fmt.Errorf("route %d: %s", i, err)
+		}
+		r.handler = h
+		r.pattern = rooted(def.Path)
+		if r.methods = methods[def.Method]; r.methods == nil && def.Method != "" {
+			r.methods = strings.Split(def.Method, "|")
+			for _, m := range r.methods {
+				if !validMethod(m) {
+					return nil, fmt.Errorf("route %d: not a valid request method: %s", i, def.Method)
+				}
+			}
+			methods[def.Method] = r.methods
+		}
+		r.path = r.pattern
+		if q := queryStart(r.pattern); q != -1 {
+			if r.query, err = parseQuery(r.pattern[q+1:]); err != nil {
+				return nil, fmt.Errorf("route %d: %s: %s", i, err, r.pattern)
+			}
+			r.path = r.pattern[:q]
+		}
+		if strings.IndexAny(r.path, ":*\\") == -1 {
+			if r.query != nil && len(r.query) > num {
+				num = len(r.query)
+			}
+			continue // static path
+		}
+
+		segments, err := parse(r.path)
+		if err != nil {
+			return nil, fmt.Errorf("route %d: %s", i, err)
+		}
+		r.segments, r.tail = splitTail(segments, r.path[len(r.path)-1] == '/')
+		r.keys = paramKeys(r.segments)
+		r.ts = r.tail == "" && r.path[len(r.path)-1] == '/'
+
+		n := len(r.query)
+		for _, segment := range segments {
+			if segment[1] == ':' || segment[1] == '*' {
+				n++
+			}
+		}
+		if n > num {
+			num = n
+		}
+	}
+
+	pool := newParamsPool(num)
+	return bulk{RouterFunc(func(req *http.Request) http.Handler {
+		path := req.URL.Path
+		ps := boundParams(req)
+		bound := ps != nil
+		var n int
+		if bound {
+			n = len(ps.params)
+		}
+		for i := range routes {
+			r := &routes[i]
+			if r.methods != nil && !hasMethod(r.methods, req.Method) {
+				continue
+			}
+			if r.segments == nil && r.query == nil {
+				if r.path != path {
+					continue
+				}
+				if ps != nil && !bound {
+					ps.alloc.Put((*ParamSet)(ps))
+				}
+				return r.handler
+			}
+
+			if ps == nil {
+				ps = pool.get()
+			}
+			if !r.match(path, &ps.params) || r.query != nil && !matchQuery(r.query, req.URL.RawQuery, num, &ps.params) {
+				ps.params = ps.params[:n]
+				continue
+			}
+			ps.pattern = r.pattern
+			if bound {
+				return r.handler // outer router recycles parameters
+			}
+			ps.ReadCloser = req.Body
+			req.Body = ps
+			return ps.releasing(req, r.handler)
+		}
+		if ps != nil && !bound {
+			ps.alloc.Put((*ParamSet)(ps))
+		}
+		return nil
+	}), routes}, nil
+}
+
+type bulkRoute struct {
+	pattern  string
+	path     string // pattern without query section
+	query    []queryParam
+	methods  []string
+	segments []string // nil for static paths
+	keys     []string // parameter keys of segments
+	tail     string   // static segments following catch-all
+	ts       bool
+	handler  http.Handler
+}
+
+func (r *bulkRoute) match(path string, ps *Params) bool {
+	if r.segments == nil {
+		return path == r.path
+	}
+	if r.tail != "" {
+		return matchTail(r.segments, r.keys, r.tail, path, ps, false)
+	}
+	return match(r.segments, r.keys, path, ps, r.ts, false)
+}
+
+type bulk struct {
+	RouterFunc
+	routes []bulkRoute
+}
+
+func (b bulk) Patterns() []string {
+	patterns, _ := Patterns(b)
+	return patterns
+}
+
+func (b bulk) inspect() ([]RouteInfo, bool) {
+	routes := make([]RouteInfo, len(b.routes))
+	for i, r := range b.routes {
+		routes[i] = describe(r.pattern)
+		if r.methods != nil {
+			routes[i].Methods = append([]string{}, r.methods...)
+		}
+		routes[i].Handler = handlerName(r.handler)
+	}
+	return routes, true
+}
+
+func hasMethod(methods []string, method string) bool {
+	for _, m := range methods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+// path with a single leading slash, as patterns are
+// registered, it is only copied if not rooted already
+func rooted(path string) string {
+	if len(path) > 0 && path[0] == '/' && (len(path) == 1 || path[1] != '/') {
+		return path
+	}
+	return "/" + strings.TrimLeft(path, "/")
+}
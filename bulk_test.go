@@ -0,0 +1,182 @@
+package fastroute_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+func TestBulk(t *testing.T) {
+	t.Parallel()
+	handler := func(w http.ResponseWriter, req *http.Request) {}
+
+	var defs []fastroute.RouteDef
+	var routes []fastroute.Router
+	for _, def := range []fastroute.RouteDef{
+		{Method: "GET", Path: "/users/:id", Handler: handler},
+		{Method: "POST|PUT", Path: "/users/:id", Handler: handler},
+		{Path: "/status", Handler: handler},
+		{Path: "users/:id/posts/:post/", Handler: handler},
+		{Method: "GET", Path: "/files/*path", Handler: handler},
+		{Path: "/proxy/*middle/status", Handler: handler},
+		{Path: "/:lang/docs", Handler: handler},
+		{Path: "/", Handler: handler},
+	} {
+		defs = append(defs, def)
+		route := fastroute.New(def.Path, def.Handler)
+		if def.Method != "" {
+			route = fastroute.MethodGroup(def.Method, route)
+		}
+		routes = append(routes, route)
+	}
+
+	bulk, err := fastroute.Bulk(defs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain := fastroute.Chain(routes...)
+
+	paths := []string{
+		"/users/5", "/users/5/", "/users/", "/users/5/posts/1/", "/users/5/posts/1",
+		"/status", "/status/", "/files/", "/files/a/b", "/files",
+		"/proxy/a/b/status", "/proxy/status", "/en/docs", "/status/docs", "/", "//",
+	}
+	for _, method := range []string{"GET", "POST", "DELETE"} {
+		for _, path := range paths {
+			expected, actual := routedBy(chain, method, path), routedBy(bulk, method, path)
+			if expected != actual {
+				t.Fatalf("expected %s %s to be routed as %q, but bulk router gave %q", method, path, expected, actual)
+			}
+		}
+	}
+
+	infos := fastroute.Inspect(bulk)
+	if len(infos) != len(defs) || infos[1].Pattern != "/users/:id" || len(infos[1].Methods) != 2 || infos[3].Pattern != "/users/:id/posts/:post/" {
+		t.Fatalf("expected bulk routes to be described, but got: %+v", infos)
+	}
+
+	// parameters bound by an outer router
+	hosted := fastroute.Host(":tenant.example.com", bulk)
+	req, _ := http.NewRequest("GET", "http://acme.example.com/users/5/posts/1/", nil)
+	w := httptest.NewRecorder()
+	fastroute.Chain(hosted).ServeHTTP(w, req)
+	if w.Code != http.StatusOK || fastroute.Parameters(req) != nil {
+		t.Fatalf("expected hosted bulk route to be served and recycled, but got: %d", w.Code)
+	}
+}
+
+func TestBulkQuery(t *testing.T) {
+	t.Parallel()
+	handler := func(w http.ResponseWriter, req *http.Request) {}
+
+	var defs []fastroute.RouteDef
+	var routes []fastroute.Router
+	for _, path := range []string{"/search?q=:query&page=:page?", "/users/:id?fields=:fields", "/search", "/faq\\?"} {
+		defs = append(defs, fastroute.RouteDef{Path: path, Handler: handler})
+		routes = append(routes, fastroute.New(path, handler))
+	}
+	bulk, err := fastroute.Bulk(defs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain := fastroute.Chain(routes...)
+
+	for _, target := range []string{
+		"/search?q=go&page=2", "/search?q=go&q=rust", "/search?page=2", "/search",
+		"/users/5?fields=name", "/users/5", "/faq%3F", "/faq?q=go",
+	} {
+		expected, actual := queriedBy(chain, target), queriedBy(bulk, target)
+		if expected != actual {
+			t.Fatalf("expected %s to be routed as %q, but bulk router gave %q", target, expected, actual)
+		}
+	}
+
+	if _, err := fastroute.Bulk([]fastroute.RouteDef{{Path: "/search?q", Handler: handler}}); err == nil {
+		t.Fatal("expected an error for invalid query section")
+	}
+}
+
+// describes how router matches the given request target
+func queriedBy(router fastroute.Router, target string) string {
+	req, _ := http.NewRequest("GET", target, nil)
+	if router.Route(req) == nil {
+		return "no match"
+	}
+	res := fastroute.Pattern(req)
+	for _, p := range fastroute.Parameters(req) {
+		res += " " + p.Key + "=" + p.Value
+	}
+	fastroute.Recycle(req)
+	return res
+}
+
+func TestBulkErrors(t *testing.T) {
+	t.Parallel()
+	handler := func(w http.ResponseWriter, req *http.Request) {}
+
+	cases := []struct {
+		def fastroute.RouteDef
+		err string
+	}{
+		{fastroute.RouteDef{Path: "/users/:", Handler: handler}, "route 1: param must be named after sign: /users/:"},
+		{fastroute.RouteDef{Method: "GET,POST", Path: "/users", Handler: handler}, "route 1: not a valid request method: GET,POST"},
+		{fastroute.RouteDef{Path: "/users", Handler: nil}, "route 1: given handler cannot be: nil"},
+	}
+	for _, c := range cases {
+		router, err := fastroute.Bulk([]fastroute.RouteDef{{Path: "/status", Handler: handler}, c.def})
+		if err == nil || err.Error() != c.err || router != nil {
+			t.Fatalf("expected error: %q, but got: %v", c.err, err)
+		}
+	}
+}
+
+// describes how router matches the given request
+func routedBy(router fastroute.Router, method, path string) string {
+	req, _ := http.NewRequest(method, "http://localhost", nil)
+	req.URL.Path = path
+	if router.Route(req) == nil {
+		return "no match"
+	}
+	res := fastroute.Pattern(req)
+	for _, p := range fastroute.Parameters(req) {
+		res += " " + p.Key + "=" + p.Value
+	}
+	fastroute.Recycle(req)
+	return res
+}
+
+func vanityRoutes(n int) []fastroute.RouteDef {
+	handler := func(w http.ResponseWriter, req *http.Request) {}
+	defs := make([]fastroute.RouteDef, n)
+	for i := range defs {
+		defs[i] = fastroute.RouteDef{Method: "GET", Path: fmt.Sprintf("/vanity%d/:user/posts/:post", i), Handler: handler}
+	}
+	return defs
+}
+
+func Benchmark_50kRoutes_New(b *testing.B) {
+	defs := vanityRoutes(50000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		routes := make([]fastroute.Router, len(defs))
+		for j, def := range defs {
+			routes[j] = fastroute.MethodGroup(def.Method, fastroute.New(def.Path, def.Handler))
+		}
+		fastroute.Chain(routes...)
+	}
+}
+
+func Benchmark_50kRoutes_Bulk(b *testing.B) {
+	defs := vanityRoutes(50000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := fastroute.Bulk(defs); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package fastroute
+
+import (
+	"net/http"
+	"sync/atomic"
+)
+
+// RequestSummary describes a request routed by
+// the router wrapped by Capture.
+type RequestSummary struct {
+	Method string
+	Path   string
+	Host   string
+
+	// Pattern is the pattern of the matched route,
+	// empty if the request was not matched.
+	Pattern string
+
+	// Matched is true if router matched request.
+	Matched bool
+}
+
+// CaptureOption configures Capture.
+type CaptureOption func(*capturer)
+
+type capturer struct {
+	every uint64
+	count uint64 // accessed atomically
+}
+
+// CaptureEvery samples every n-th request only,
+// in order to reduce overhead on busy routes.
+func CaptureEvery(n int) CaptureOption {
+	return func(c *capturer) {
+		if n > 1 {
+			c.every = uint64(n)
+		}
+	}
+}
+
+// Capture wraps router in order to summarize the requests
+// it routes, whether matched or not, to sink. Unlike Trace,
+// which explains every attempted route, it only records the
+// outcome, so it may observe all the traffic in order to
+// learn its distribution, for example to order routes by
+// popularity or to size caches.
+//
+// Sink is called synchronously while routing, so it should
+// hand summaries over, rather than process them. Traffic
+// may be sampled by CaptureEvery, and captured only for a
+// window of time by composing with Schedule or During:
+//
+//	router = fastroute.During(window, fastroute.Capture(router, sink), router)
+//
+// Paths and hosts may carry personal data, like emails or
+// tokens in parameters, so summaries should be treated the
+// way access logs are. Patterns alone are usually enough to
+// learn traffic distribution.
+func Capture(router Router, sink func(RequestSummary), options ...CaptureOption) Router {
+	c := &capturer{}
+	for _, option := range options {
+		option(c)
+	}
+
+	return wrapper{RouterFunc(func(req *http.Request) http.Handler {
+		h := router.Route(req)
+		if c.every > 1 && atomic.AddUint64(&c.count, 1)%c.every != 1 {
+			return h
+		}
+		summary := RequestSummary{Method: req.Method, Path: req.URL.Path, Host: req.Host, Matched: h != nil}
+		if h != nil {
+			summary.Pattern = Pattern(req)
+		}
+		sink(summary)
+		return h
+	}), router}
+}
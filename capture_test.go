@@ -0,0 +1,50 @@
+package fastroute_test
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+func TestCapture(t *testing.T) {
+	t.Parallel()
+	handler := func(w http.ResponseWriter, req *http.Request) {}
+	routes := fastroute.Chain(
+		fastroute.New("/users/:id", handler),
+		fastroute.New("/status", handler),
+	)
+
+	var summaries []fastroute.RequestSummary
+	router := fastroute.Capture(routes, func(s fastroute.RequestSummary) {
+		summaries = append(summaries, s)
+	})
+
+	for _, path := range []string{"/users/5", "/status", "/missing"} {
+		req, _ := http.NewRequest("POST", "http://example.com"+path, nil)
+		router.Route(req)
+		fastroute.Recycle(req)
+	}
+
+	expected := []fastroute.RequestSummary{
+		{"POST", "/users/5", "example.com", "/users/:id", true},
+		{"POST", "/status", "example.com", "/status", true},
+		{"POST", "/missing", "example.com", "", false},
+	}
+	if !reflect.DeepEqual(summaries, expected) {
+		t.Fatalf("expected summaries: %+v, but got: %+v", expected, summaries)
+	}
+
+	var sampled int
+	router = fastroute.Capture(routes, func(s fastroute.RequestSummary) {
+		sampled++
+	}, fastroute.CaptureEvery(3))
+	for i := 0; i < 10; i++ {
+		req, _ := http.NewRequest("GET", "/status", nil)
+		router.Route(req)
+	}
+	if sampled != 4 {
+		t.Fatalf("expected every third request to be captured, but got: %d", sampled)
+	}
+}
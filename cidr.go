@@ -0,0 +1,147 @@
+//go:build go1.18
+// +build go1.18
+
+package fastroute
+
+import (
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// IPFilter configures how CIDR resolves client IP
+// address and treats requests from other addresses.
+type IPFilter struct {
+	// TrustedProxies is the number of reverse proxies in
+	// front of the server. When zero, client address is
+	// taken from RemoteAddr only, and X-Forwarded-For or
+	// X-Real-IP headers are ignored.
+	//
+	// Otherwise the client is the address the outermost
+	// trusted proxy was connected from: X-Forwarded-For
+	// addresses are followed from the right, skipping
+	// TrustedProxies entries, RemoteAddr being the last
+	// one. If X-Forwarded-For is absent, X-Real-IP is
+	// used instead. Only set it when all requests pass
+	// through that many proxies, since clients are free
+	// to send any of these headers themselves.
+	//
+	// For example, behind a single load balancer, set it
+	// to 1. A request arriving with
+	//
+	//	X-Forwarded-For: 6.6.6.6, 203.0.113.7
+	//	RemoteAddr: 10.0.0.2:41234
+	//
+	// is resolved to 203.0.113.7, the address appended by
+	// the load balancer, while 6.6.6.6 is ignored, since
+	// it was sent by the client. Setting it higher than
+	// the actual number of proxies lets clients choose
+	// their address, while setting it lower resolves all
+	// requests to the address of a proxy.
+	TrustedProxies int
+
+	// Reject, if set, serves requests matched by router
+	// but coming from not allowed address. Otherwise
+	// such requests fall through to the following routes.
+	Reject http.Handler
+}
+
+// CIDR scopes router to requests from client addresses
+// within any of the allowed networks, given in CIDR
+// notation like "10.0.0.0/8" or "fd00::/8", or as single
+// addresses. Client address is taken from RemoteAddr,
+// requests from other addresses fall through. See
+// IPFilter in order to trust proxies or reject requests.
+//
+// It panics if any of allowed networks is malformed.
+func CIDR(router Router, allow ...string) Router {
+	return IPFilter{}.CIDR(router, allow...)
+}
+
+// CIDR scopes router to requests from client addresses
+// within any of the allowed networks, as configured.
+func (f IPFilter) CIDR(router Router, allow ...string) Router {
+	prefixes := make([]netip.Prefix, len(allow))
+	for i, cidr := range allow {
+		prefix, err := netip.ParsePrefix(cidr)
+		if err != nil {
+			addr, aerr := netip.ParseAddr(cidr)
+			if aerr != nil {
+				panic("not a valid network: " + cidr)
+			}
+			prefix = netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen())
+		}
+		if prefix.Addr().Is4In6() {
+			prefix = netip.PrefixFrom(prefix.Addr().Unmap(), prefix.Bits()-96)
+		}
+		prefixes[i] = prefix.Masked()
+	}
+
+	allowed := func(req *http.Request) bool {
+		addr, ok := f.clientIP(req)
+		if !ok {
+			return false
+		}
+		for _, prefix := range prefixes {
+			if prefix.Contains(addr) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return wrapper{RouterFunc(func(req *http.Request) http.Handler {
+		if f.Reject == nil {
+			if !allowed(req) {
+				return nil
+			}
+			return router.Route(req)
+		}
+
+		h := router.Route(req)
+		if h == nil || allowed(req) {
+			return h
+		}
+		Recycle(req)
+		return f.Reject
+	}), router}
+}
+
+// resolves client address, as configured
+func (f IPFilter) clientIP(req *http.Request) (netip.Addr, bool) {
+	remote := req.RemoteAddr
+	if host, _, err := net.SplitHostPort(remote); err == nil {
+		remote = host
+	}
+	if f.TrustedProxies <= 0 {
+		return parseIP(remote)
+	}
+
+	var chain []string
+	for _, header := range req.Header["X-Forwarded-For"] {
+		for _, addr := range strings.Split(header, ",") {
+			chain = append(chain, strings.TrimSpace(addr))
+		}
+	}
+	if len(chain) == 0 {
+		if real := req.Header.Get("X-Real-IP"); real != "" {
+			chain = append(chain, strings.TrimSpace(real))
+		}
+	}
+	chain = append(chain, remote)
+
+	pos := len(chain) - 1 - f.TrustedProxies
+	if pos < 0 {
+		pos = 0
+	}
+	return parseIP(chain[pos])
+}
+
+func parseIP(s string) (netip.Addr, bool) {
+	addr, err := netip.ParseAddr(strings.Trim(s, "[]"))
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	return addr.Unmap().WithZone(""), true
+}
@@ -0,0 +1,154 @@
+//go:build go1.18
+// +build go1.18
+
+package fastroute_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+func TestCIDR(t *testing.T) {
+	t.Parallel()
+
+	internal := fastroute.CIDR(
+		fastroute.New("/metrics", func(w http.ResponseWriter, req *http.Request) {
+			w.Write([]byte("metrics"))
+		}),
+		"10.0.0.0/8", "fd00::/8", "192.168.1.10",
+	)
+	router := fastroute.Chain(internal, fastroute.New("/metrics", func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+
+	cases := map[string]int{
+		"10.1.2.3:5000":            200,
+		"10.1.2.3":                 200,
+		"[fd12::1]:5000":           200,
+		"[fd12::1%eth0]:5000":      200,
+		"[::ffff:10.1.2.3]:5000":   200,
+		"192.168.1.10:80":          200,
+		"192.168.1.11:80":          403,
+		"11.0.0.1:5000":            403,
+		"[fe80::1]:5000":           403,
+		"":                         403,
+		"not-an-address":           403,
+		"10.1.2.3:5000:extra:port": 403,
+	}
+
+	for addr, code := range cases {
+		req, err := http.NewRequest("GET", "/metrics", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.RemoteAddr = addr
+		req.Header.Set("X-Forwarded-For", "10.0.0.1")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != code {
+			t.Fatalf("expected response code: %d for address: %q, but got: %d", code, addr, w.Code)
+		}
+	}
+
+	if patterns, _ := fastroute.Patterns(internal); len(patterns) != 1 {
+		t.Fatalf("expected router to remain enumerable, but got: %v", patterns)
+	}
+}
+
+func TestCIDRTrustedProxies(t *testing.T) {
+	t.Parallel()
+
+	router := fastroute.IPFilter{
+		TrustedProxies: 2,
+		Reject:         http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) { w.WriteHeader(403) }),
+	}.CIDR(fastroute.New("/debug/:name", func(w http.ResponseWriter, req *http.Request) {
+		fmt.Fprint(w, fastroute.Parameters(req).ByName("name"))
+	}), "10.0.0.0/8", "::ffff:172.16.0.0/108")
+
+	cases := []struct {
+		remote, forwarded, real string
+		code                    int
+	}{
+		{"192.168.0.1:1", "10.0.0.5, 192.168.0.2", "", 200},
+		{"192.168.0.1:1", "11.0.0.5, 10.0.0.5, 192.168.0.2", "", 200},
+		{"192.168.0.1:1", "10.0.0.5, 11.0.0.5, 192.168.0.2", "", 403},
+		{"192.168.0.1:1", "10.0.0.5", "", 200},
+		{"192.168.0.1:1", "", "10.0.0.5", 200},
+		{"192.168.0.1:1", "", "11.0.0.5", 403},
+		{"10.0.0.1:1", "", "", 200},
+		{"192.168.0.1:1", "garbage, 192.168.0.2", "", 403},
+		{"192.168.0.1:1", "172.16.3.4, 192.168.0.2", "", 200},
+	}
+
+	for i, c := range cases {
+		req, err := http.NewRequest("GET", "/debug/vars", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.RemoteAddr = c.remote
+		if c.forwarded != "" {
+			req.Header.Set("X-Forwarded-For", c.forwarded)
+		}
+		if c.real != "" {
+			req.Header.Set("X-Real-IP", c.real)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != c.code {
+			t.Fatalf("expected response code: %d, but got: %d, case: %d", c.code, w.Code, i)
+		}
+		if c.code == 200 && w.Body.String() != "vars" {
+			t.Fatalf("expected parameters to be bound, but got: %s, case: %d", w.Body.String(), i)
+		}
+		if fastroute.Parameters(req) != nil {
+			t.Fatalf("expected parameters to be recycled, case: %d", i)
+		}
+	}
+}
+
+func TestCIDRValidation(t *testing.T) {
+	t.Parallel()
+
+	defer func() {
+		if err := recover(); fmt.Sprint(err) != "not a valid network: 10.0.0.0/33" {
+			t.Fatalf("unexpected panic: %v", err)
+		}
+	}()
+	fastroute.CIDR(fastroute.New("/", http.NotFoundHandler()), "10.0.0.0/33")
+}
+
+func ExampleIPFilter_CIDR() {
+	admin := fastroute.New("/admin/*path", func(w http.ResponseWriter, req *http.Request) {
+		fmt.Fprint(w, "admin")
+	})
+
+	// behind a single load balancer, admin routes are
+	// only served to the internal network, others fall
+	// through to forbidden
+	forbidden := fastroute.New("/admin/*path", func(w http.ResponseWriter, req *http.Request) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+	})
+	router := fastroute.Chain(
+		fastroute.IPFilter{TrustedProxies: 1}.CIDR(admin, "10.0.0.0/8"),
+		forbidden,
+	)
+
+	for _, forwarded := range []string{"10.1.2.3", "10.1.2.3, 203.0.113.7"} {
+		req, _ := http.NewRequest("GET", "/admin/users", nil)
+		req.RemoteAddr = "10.0.0.2:41234" // load balancer
+		req.Header.Set("X-Forwarded-For", forwarded)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		fmt.Println(forwarded, "-", w.Code)
+	}
+
+	// Output:
+	// 10.1.2.3 - 200
+	// 10.1.2.3, 203.0.113.7 - 403
+}
@@ -0,0 +1,193 @@
+//go:build go1.7
+// +build go1.7
+
+package fastroute
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+)
+
+type contextKey struct{}
+
+// ContextCarrier wraps router in order to carry parameters
+// of the matched request in its context, instead of the
+// request body, while the handler is served.
+//
+// By default parameters are bound by wrapping request
+// Body, which is then visible to the handler. Handlers,
+// which replace or assert the type of Body, should be
+// served through ContextCarrier. The handler receives
+// a shallow copy of request, having the original Body
+// and the parameters attached to its context, where
+// Parameters and Pattern find them as usual.
+//
+// Parameters are still pooled and recycled once the
+// handler returns, so are the context and the request
+// copy, which then must not be retained by the handler.
+func ContextCarrier(router Router) Router {
+	return carry(router, false)
+}
+
+// WithContextValue wraps router in order to serve the
+// matched handler with value bound to key in request
+// context, for example a configuration resolved for
+// routes, rather than for a request. The handler
+// receives a shallow copy of request, which costs a
+// few allocations per request.
+func WithContextValue(router Router, key, value interface{}) Router {
+	return wrapper{RouterFunc(func(req *http.Request) http.Handler {
+		h := router.Route(req)
+		if h == nil {
+			return nil
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			r := req.WithContext(context.WithValue(req.Context(), key, value))
+			h.ServeHTTP(w, r)
+			req.Body = r.Body // parameters may be recycled
+		})
+	}), router}
+}
+
+// RecycleOnDone wraps router in order to recycle
+// parameters of the matched request only once its
+// context is done, rather than as soon as the handler
+// returns. Middleware, which reads Parameters(req) or
+// Pattern(req) after serving, for example in deferred
+// audit or error reporting, then observes stable values
+// for the whole lifetime of request.
+//
+// The handler is served as by ContextCarrier, while the
+// parameters stay bound to the original request. It
+// costs a goroutine waiting for the context per request,
+// so it is a trade-off against the default, immediate
+// recycling. Requests, which context is never done,
+// are recycled when the handler returns. Request must
+// not be used to read parameters after its context
+// is done.
+func RecycleOnDone(router Router) Router {
+	return carry(router, true)
+}
+
+var carriers = sync.Pool{New: func() interface{} {
+	return new(carrier)
+}}
+
+func carry(router Router, onDone bool) Router {
+	return wrapper{RouterFunc(func(req *http.Request) http.Handler {
+		h := router.Route(req)
+		if h == nil {
+			return nil
+		}
+		p, _ := req.Body.(*parameters)
+		if p == nil {
+			return h
+		}
+		// parameters matched along with h are recycled by carrier,
+		// others, like attached by WithParams, are only carried
+		owned := p.next != nil && p.req == req
+		if r, ok := h.(*release); ok && (*parameters)(r) == p {
+			h = p.next // carrier recycles parameters instead
+		}
+		c := carriers.Get().(*carrier)
+		c.p, c.next, c.owned, c.onDone = p, h, owned, onDone
+		return c
+	}), router}
+}
+
+// carries parameters in the context of request copy,
+// both pooled along with it. The handler is kept apart
+// from the one of parameters, since it may wrap their
+// release, which claims it on its own. Parameters are
+// recycled by carrier only if owned
+type carrier struct {
+	context.Context
+	p      *parameters
+	next   http.Handler
+	owned  bool
+	onDone bool
+	req    http.Request
+}
+
+func (c *carrier) Value(key interface{}) interface{} {
+	if key == (contextKey{}) {
+		return c.p
+	}
+	return c.Context.Value(key)
+}
+
+func (c *carrier) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	// body may be replaced by middleware since matched
+	p, h := c.p, c.next
+	bound := req.Body == io.ReadCloser(p)
+	if h == nil || !bound && (!c.owned || p.req == nil || p.req.URL != req.URL) {
+		unclaimed(w, req)
+		return
+	}
+	c.next = nil
+
+	ctx := req.Context()
+	c.Context = ctx
+	c.req = *req.WithContext(c)
+	if bound {
+		c.req.Body = p.ReadCloser
+	}
+	h.ServeHTTP(w, &c.req)
+
+	if !c.owned {
+		c.recycle() // the ones bound by outer router
+		return
+	}
+	if !c.onDone || ctx.Done() == nil {
+		if bound {
+			p.reset(req)
+		} else {
+			p.recycle()
+		}
+		c.recycle()
+		return
+	}
+	go func() {
+		<-ctx.Done()
+		p.recycle()
+		c.recycle()
+	}()
+}
+
+func (c *carrier) recycle() {
+	c.Context, c.p, c.owned = nil, nil, false
+	c.req = http.Request{}
+	carriers.Put(c)
+}
+
+type abVariantKey string
+
+// serves h with variant of a new visitor, assigned by
+// ABTest of name, in the context of request copy
+func serveVariant(w http.ResponseWriter, req *http.Request, name, variant string, h http.Handler) {
+	r := req.WithContext(context.WithValue(req.Context(), abVariantKey(name), variant))
+	h.ServeHTTP(w, r)
+	req.Body = r.Body // parameters may be recycled
+}
+
+// variant assigned to a new visitor by ABTest of name
+func assignedVariant(req *http.Request, name string) string {
+	variant, _ := req.Context().Value(abVariantKey(name)).(string)
+	return variant
+}
+
+// closed when request is canceled
+func requestDone(req *http.Request) <-chan struct{} {
+	return req.Context().Done()
+}
+
+// finds parameters bound to request
+func carried(req *http.Request) *parameters {
+	if p, _ := req.Body.(*parameters); p != nil {
+		return p
+	}
+	p, _ := req.Context().Value(contextKey{}).(*parameters)
+	return p
+}
@@ -0,0 +1,28 @@
+//go:build !go1.7
+// +build !go1.7
+
+package fastroute
+
+import "net/http"
+
+// request cannot be canceled without context
+func requestDone(req *http.Request) <-chan struct{} {
+	return nil
+}
+
+// finds parameters bound to request
+func carried(req *http.Request) *parameters {
+	p, _ := req.Body.(*parameters)
+	return p
+}
+
+// serves h, variant of a new visitor cannot be carried
+// without context, ABVariant reports it once the
+// assignment cookie is sent back
+func serveVariant(w http.ResponseWriter, req *http.Request, name, variant string, h http.Handler) {
+	h.ServeHTTP(w, req)
+}
+
+func assignedVariant(req *http.Request, name string) string {
+	return ""
+}
@@ -0,0 +1,297 @@
+//go:build go1.7
+// +build go1.7
+
+package fastroute_test
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+func TestContextCarrier(t *testing.T) {
+	t.Parallel()
+
+	handler := func(w http.ResponseWriter, req *http.Request) {
+		if _, ok := req.Body.(payload); !ok {
+			t.Fatalf("expected pristine request body, but got: %T", req.Body)
+		}
+		body, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(fastroute.Pattern(req) + " " + fastroute.Parameters(req).ByName("id") + " " + string(body)))
+	}
+
+	router := fastroute.ContextCarrier(fastroute.Chain(
+		fastroute.New("/users/:id", handler),
+		fastroute.Host("tenant-:id.example.com", fastroute.New("/", handler)),
+		fastroute.New("/static", handler),
+	))
+
+	cases := map[string]string{
+		"/users/5":                     "/users/:id 5 payload",
+		"tenant-7.example.com/":        "/ 7 payload",
+		"/static":                      "/static  payload",
+		"example.com/static":           "/static  payload",
+		"tenant-7.example.com/missing": "404 page not found\n",
+	}
+	for target, expected := range cases {
+		url := "http://" + target
+		if strings.HasPrefix(target, "/") {
+			url = target
+		}
+		body := payload{strings.NewReader("payload")}
+		req, err := http.NewRequest("POST", url, body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Body.String() != expected {
+			t.Fatalf("expected response: %q, but got: %q", expected, w.Body.String())
+		}
+		if req.Body != io.ReadCloser(body) || fastroute.Parameters(req) != nil {
+			t.Fatalf("expected request body to be restored and parameters recycled for: %s", target)
+		}
+	}
+}
+
+func TestContextCarrierWrappingRelease(t *testing.T) {
+	t.Parallel()
+
+	router := fastroute.ContextCarrier(fastroute.Languages(map[string]fastroute.Router{
+		"en": fastroute.New("/users/:id", func(w http.ResponseWriter, req *http.Request) {
+			if _, ok := req.Body.(payload); !ok {
+				t.Fatalf("expected pristine request body, but got: %T", req.Body)
+			}
+			ps := fastroute.Parameters(req)
+			w.Write([]byte(ps.ByName("language") + " " + ps.ByName("id")))
+		}),
+	}, "en"))
+
+	body := payload{strings.NewReader("payload")}
+	req, err := http.NewRequest("POST", "/users/5", body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Body.String() != "en 5" || w.Header().Get("Vary") != "Accept-Language" {
+		t.Fatalf("expected handler wrapped by Languages to be served, but got: %q", w.Body.String())
+	}
+	if req.Body != io.ReadCloser(body) || fastroute.Parameters(req) != nil {
+		t.Fatal("expected request body to be restored and parameters recycled")
+	}
+}
+
+func TestContextCarrierOfForeignParameters(t *testing.T) {
+	t.Parallel()
+
+	handler := func(w http.ResponseWriter, req *http.Request) {
+		if _, ok := req.Body.(payload); !ok {
+			t.Fatalf("expected pristine request body, but got: %T", req.Body)
+		}
+		w.Write([]byte(fastroute.Parameters(req).ByName("id") + ";"))
+	}
+
+	// parameters attached by WithParams are only carried
+	body := payload{strings.NewReader("payload")}
+	req, _ := http.NewRequest("POST", "/static", body)
+	req = fastroute.WithParams(req, fastroute.Params{{"id", "5"}})
+	w := httptest.NewRecorder()
+	fastroute.RecycleOnDone(fastroute.New("/static", handler)).ServeHTTP(w, req)
+	if w.Body.String() != "5;" || fastroute.Parameters(req).ByName("id") != "5" {
+		t.Fatalf("expected attached parameters to be served and kept, but got: %q", w.Body.String())
+	}
+
+	// so are the ones bound by outer route, which recycles them
+	inner := fastroute.ContextCarrier(fastroute.New("/users/5", handler))
+	router := fastroute.New("/users/:id", func(w http.ResponseWriter, req *http.Request) {
+		inner.ServeHTTP(w, req)
+		w.Write([]byte(fastroute.Parameters(req).ByName("id")))
+	})
+	req, _ = http.NewRequest("POST", "/users/5", body)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Body.String() != "5;5" {
+		t.Fatalf("expected outer parameters to outlive the inner route, but got: %q", w.Body.String())
+	}
+	if req.Body != io.ReadCloser(body) || fastroute.Parameters(req) != nil {
+		t.Fatal("expected request body to be restored and parameters recycled")
+	}
+}
+
+// not parallel, since it counts allocations
+func TestContextCarrierZeroAllocations(t *testing.T) {
+	if poisoning {
+		t.Skip("recycled parameters are poisoned in debug builds")
+	}
+	var id string
+	router := fastroute.ContextCarrier(fastroute.New("/v1/users/:id", func(w http.ResponseWriter, r *http.Request) {
+		id = fastroute.Parameters(r).ByName("id")
+	}))
+
+	req, err := http.NewRequest("GET", "/v1/users/5", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := new(discardWriter)
+	router.ServeHTTP(w, req) // warm up the pools
+
+	if allocs := testing.AllocsPerRun(100, func() {
+		router.ServeHTTP(w, req)
+	}); allocs != 0 {
+		t.Fatalf("expected serving to perform no allocations, but got: %v", allocs)
+	}
+	if id != "5" || fastroute.Parameters(req) != nil {
+		t.Fatalf("expected parameter to be served and recycled, but got: %q", id)
+	}
+}
+
+func Benchmark_1Param_Context(b *testing.B) {
+	router := fastroute.ContextCarrier(fastroute.New("/v1/users/:id", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(fastroute.Parameters(r).ByName("id")))
+	}))
+
+	req, err := http.NewRequest("GET", "/v1/users/5", nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+	w := new(discardWriter)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		router.ServeHTTP(w, req)
+	}
+}
+
+type payload struct {
+	*strings.Reader
+}
+
+func (payload) Close() error {
+	return nil
+}
+
+type tenantKey struct{}
+
+func TestWithContextValue(t *testing.T) {
+	t.Parallel()
+
+	handler := func(w http.ResponseWriter, req *http.Request) {
+		tenant, _ := req.Context().Value(tenantKey{}).(string)
+		w.Write([]byte(tenant + " " + fastroute.Parameters(req).ByName("id")))
+	}
+	router := fastroute.Chain(
+		fastroute.WithContextValue(fastroute.New("/acme/users/:id", handler), tenantKey{}, "acme"),
+		fastroute.New("/users/:id", handler),
+	)
+
+	cases := map[string]string{
+		"/acme/users/1": "acme 1",
+		"/users/2":      " 2",
+	}
+	for path, body := range cases {
+		req, err := http.NewRequest("GET", path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Body.String() != body {
+			t.Fatalf("expected response: %q, but got: %q", body, w.Body.String())
+		}
+		if fastroute.Parameters(req) != nil {
+			t.Fatalf("expected parameters to be recycled for: %s", path)
+		}
+	}
+}
+
+func TestRecycleOnDone(t *testing.T) {
+	t.Parallel()
+
+	var reported []string
+	audit := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			defer func() {
+				reported = append(reported, fastroute.Pattern(req)+" "+fastroute.Parameters(req).ByName("id"))
+			}()
+			next.ServeHTTP(w, req)
+		})
+	}
+
+	router := fastroute.RecycleOnDone(fastroute.New("/users/:id", func(w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte(fastroute.Parameters(req).ByName("id")))
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req, err := http.NewRequest("GET", "/users/5", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req = req.WithContext(ctx)
+	w := httptest.NewRecorder()
+	audit(router).ServeHTTP(w, req)
+
+	if w.Body.String() != "5" {
+		t.Fatalf("expected parameters to be available in handler, but got: %q", w.Body.String())
+	}
+	if len(reported) != 1 || reported[0] != "/users/:id 5" {
+		t.Fatalf("expected parameters to be stable after serving, but got: %v", reported)
+	}
+	cancel()
+
+	// not cancelable request is recycled once served
+	req, _ = http.NewRequest("GET", "/users/6", nil)
+	audit(router).ServeHTTP(httptest.NewRecorder(), req)
+	if reported[1] != "/users/6 " || fastroute.Parameters(req) != nil {
+		t.Fatalf("expected parameters to be recycled when served, but got: %v", reported)
+	}
+}
+
+func TestLimitQueuedRequestCanceled(t *testing.T) {
+	t.Parallel()
+	entered, proceed := make(chan bool), make(chan bool)
+	metrics := &fastroute.LimitMetrics{}
+	router := fastroute.Limit(1, fastroute.New("/reports/:id", func(w http.ResponseWriter, req *http.Request) {
+		entered <- true
+		<-proceed
+	}), fastroute.LimitQueue(1, 0), fastroute.LimitReport(metrics))
+
+	go func() {
+		req, _ := http.NewRequest("GET", "/reports/1", nil)
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}()
+	<-entered
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req, _ := http.NewRequest("GET", "/reports/2", nil)
+	req = req.WithContext(ctx)
+	done := make(chan *httptest.ResponseRecorder)
+	go func() {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		done <- w
+	}()
+	for metrics.Queued() != 1 {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+
+	if w := <-done; w.Code != http.StatusServiceUnavailable || metrics.Rejected() != 1 {
+		t.Fatalf("expected canceled request to stop waiting, but got: %d", w.Code)
+	}
+	proceed <- true
+}
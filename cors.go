@@ -0,0 +1,234 @@
+package fastroute
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CORSOptions configures CORS.
+type CORSOptions struct {
+	// AllowedOrigins lists origins allowed to make
+	// cross origin requests, like "https://example.com",
+	// or "*" in order to allow any origin. Origins only
+	// allowed by "*" are answered with "*", without
+	// credentials, so they never get credentialed access.
+	AllowedOrigins []string
+
+	// AllowedMethods lists methods allowed in preflight
+	// requests. If empty, methods accepted by router for
+	// the requested path are allowed.
+	AllowedMethods []string
+
+	// AllowedHeaders lists request headers allowed in
+	// preflight requests, "*" allows any requested one.
+	AllowedHeaders []string
+
+	// ExposedHeaders lists response headers exposed
+	// to the client in actual requests.
+	ExposedHeaders []string
+
+	// AllowCredentials allows requests with credentials,
+	// like cookies, from the listed origins.
+	AllowCredentials bool
+
+	// MaxAge is how long preflight response may be
+	// cached, not set if zero.
+	MaxAge time.Duration
+}
+
+// CORS wraps router in order to handle cross origin
+// requests from allowed origins.
+//
+// Preflight OPTIONS requests are answered with 204 No
+// Content, if router has a route for the requested path,
+// otherwise they fall through. Allowed methods, unless
+// configured, are the ones router accepts for the path,
+// which are found among the routes described by Inspect,
+// or common ones for routes not scoped by method. The
+// request is not routed, so preflight is not counted as
+// a match by routers, like Stats or RateLimit, while
+// opaque routes are not found.
+//
+// Actual requests matched by router are served with
+// Access-Control-Allow-Origin header set, if origin is
+// allowed. Unless any origin is answered the same, by
+// "*", responses vary by Origin header, whether it is
+// allowed or not.
+func CORS(router Router, opts CORSOptions) Router {
+	origins := corsOrigins{listed: make(map[string]bool, len(opts.AllowedOrigins))}
+	for _, origin := range opts.AllowedOrigins {
+		if origin == "*" {
+			origins.any = true
+		} else {
+			origins.listed[origin] = true
+		}
+	}
+	allowed := origins.allowed
+
+	return wrapper{RouterFunc(func(req *http.Request) http.Handler {
+		origin := req.Header.Get("Origin")
+		if req.Method == "OPTIONS" && origin != "" && req.Header.Get("Access-Control-Request-Method") != "" {
+			methods := opts.AllowedMethods
+			if len(methods) == 0 {
+				methods = routedMethods(router, req)
+			} else if len(routedMethods(router, req)) == 0 {
+				methods = nil
+			}
+			if len(methods) == 0 {
+				return nil
+			}
+			return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				preflight(w, req, opts, origins, methods)
+			})
+		}
+
+		h := router.Route(req)
+		if h == nil {
+			return nil
+		}
+		if !allowed(origin) {
+			if len(origins.listed) == 0 {
+				return h // does not depend on origin
+			}
+			return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				w.Header().Add("Vary", "Origin")
+				h.ServeHTTP(w, req)
+			})
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			header := w.Header()
+			if len(origins.listed) > 0 {
+				header.Add("Vary", "Origin")
+			}
+			origins.allow(header, opts, origin)
+			if len(opts.ExposedHeaders) > 0 {
+				header.Set("Access-Control-Expose-Headers", strings.Join(opts.ExposedHeaders, ", "))
+			}
+			h.ServeHTTP(w, req)
+		})
+	}), router}
+}
+
+func preflight(w http.ResponseWriter, req *http.Request, opts CORSOptions, origins corsOrigins, methods []string) {
+	header := w.Header()
+	header.Add("Vary", "Origin")
+	header.Add("Vary", "Access-Control-Request-Method")
+	header.Add("Vary", "Access-Control-Request-Headers")
+	origin := req.Header.Get("Origin")
+	if !origins.allowed(origin) {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	origins.allow(header, opts, origin)
+	header.Set("Access-Control-Allow-Methods", strings.Join(methods, ", "))
+	if requested := req.Header.Get("Access-Control-Request-Headers"); requested != "" {
+		for _, h := range opts.AllowedHeaders {
+			if h == "*" {
+				header.Set("Access-Control-Allow-Headers", requested)
+				break
+			}
+		}
+		if header.Get("Access-Control-Allow-Headers") == "" && len(opts.AllowedHeaders) > 0 {
+			header.Set("Access-Control-Allow-Headers", strings.Join(opts.AllowedHeaders, ", "))
+		}
+	}
+	if opts.MaxAge > 0 {
+		header.Set("Access-Control-Max-Age", strconv.Itoa(int(opts.MaxAge/time.Second)))
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+type corsOrigins struct {
+	listed map[string]bool
+	any    bool // allowed by "*"
+}
+
+func (o corsOrigins) allowed(origin string) bool {
+	return origin != "" && (o.any || o.listed[origin])
+}
+
+// sets headers of allowed origin, a listed one is echoed
+// and may be given credentials, others are answered "*"
+func (o corsOrigins) allow(header http.Header, opts CORSOptions, origin string) {
+	if !o.listed[origin] {
+		header.Set("Access-Control-Allow-Origin", "*")
+		return
+	}
+	header.Set("Access-Control-Allow-Origin", origin)
+	if opts.AllowCredentials {
+		header.Set("Access-Control-Allow-Credentials", "true")
+	}
+}
+
+// methods, common for routes not scoped by method
+var commonMethods = []string{"GET", "HEAD", "POST", "PUT", "PATCH", "DELETE"}
+
+// methods router accepts for request host and path, found
+// among the routes described by Inspect, which match them
+func routedMethods(router Router, req *http.Request) []string {
+	var methods []string
+	seen := map[string]bool{"OPTIONS": true}
+	for _, info := range Inspect(router) {
+		if info.Disabled || !hostMatches(info.Host, req.Host) || !patternMatches(info.Pattern, req.URL.Path) {
+			continue
+		}
+		accepted := info.Methods
+		if accepted == nil {
+			accepted = commonMethods
+		}
+		for _, method := range accepted {
+			if !seen[method] {
+				seen[method] = true
+				methods = append(methods, method)
+			}
+		}
+	}
+	return methods
+}
+
+// whether host matches pattern given to Host, if any
+func hostMatches(pattern, host string) bool {
+	if pattern == "" {
+		return true
+	}
+	name, port := splitHostPort(strings.ToLower(pattern))
+	host, p := splitHostPort(strings.ToLower(host))
+	if port != "" && port != p {
+		return false
+	}
+	labels, parts := strings.Split(name, "."), strings.Split(host, ".")
+	if len(labels) != len(parts) {
+		return false
+	}
+	for i, label := range labels {
+		pos := strings.IndexByte(label, ':')
+		switch {
+		case pos == -1 && label != parts[i]:
+			return false
+		case pos != -1 && (len(parts[i]) <= pos || parts[i][:pos] != label[:pos]):
+			return false
+		}
+	}
+	return true
+}
+
+// whether path matches pattern given to New, or
+// to NewTemplate, regardless of the query section
+func patternMatches(pattern, path string) bool {
+	if q := queryStart(pattern); q != -1 {
+		pattern = pattern[:q]
+	}
+	if strings.IndexByte(pattern, '{') != -1 {
+		t, err := parseTemplate(pattern)
+		var discard Params
+		return err == nil && t.match(path, &discard)
+	}
+	if strings.IndexAny(pattern, ":*\\") == -1 {
+		return pattern == path
+	}
+	segments, err := parse(pattern)
+	return err == nil && matcher(segments, pattern[len(pattern)-1] == '/', false)(path, nil)
+}
@@ -0,0 +1,158 @@
+package fastroute_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+func TestCORS(t *testing.T) {
+	t.Parallel()
+	handler := func(w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte(req.Method + " " + fastroute.Parameters(req).ByName("id")))
+	}
+
+	router := fastroute.Chain(
+		fastroute.MethodGroup("GET|PUT", fastroute.New("/users/:id", handler)),
+		fastroute.MethodGroup("DELETE", fastroute.New("/users/:id", handler)),
+		fastroute.New("/status", handler),
+	)
+
+	cors := fastroute.CORS(router, fastroute.CORSOptions{
+		AllowedOrigins:   []string{"https://app.example.com"},
+		AllowedHeaders:   []string{"Content-Type", "Authorization"},
+		ExposedHeaders:   []string{"X-Request-Id"},
+		AllowCredentials: true,
+		MaxAge:           10 * time.Minute,
+	})
+
+	serve := func(router fastroute.Router, method, path, origin string, preflight bool) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, path, nil)
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+		if preflight {
+			req.Header.Set("Access-Control-Request-Method", "PUT")
+			req.Header.Set("Access-Control-Request-Headers", "content-type")
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if fastroute.Parameters(req) != nil {
+			t.Fatalf("expected parameters to be recycled for: %s %s", method, path)
+		}
+		return w
+	}
+
+	w := serve(cors, "OPTIONS", "/users/5", "https://app.example.com", true)
+	expected := map[string]string{
+		"Access-Control-Allow-Origin":      "https://app.example.com",
+		"Access-Control-Allow-Methods":     "GET, PUT, DELETE",
+		"Access-Control-Allow-Headers":     "Content-Type, Authorization",
+		"Access-Control-Allow-Credentials": "true",
+		"Access-Control-Max-Age":           "600",
+	}
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("expected preflight to be answered with 204, but got: %d", w.Code)
+	}
+	for key, val := range expected {
+		if w.Header().Get(key) != val {
+			t.Fatalf("expected header %s: %q, but got: %q", key, val, w.Header().Get(key))
+		}
+	}
+
+	w = serve(cors, "OPTIONS", "/status", "https://app.example.com", true)
+	if methods := w.Header().Get("Access-Control-Allow-Methods"); methods != "GET, HEAD, POST, PUT, PATCH, DELETE" {
+		t.Fatalf("expected common methods for route not scoped by method, but got: %q", methods)
+	}
+
+	if w = serve(cors, "OPTIONS", "/users/5", "https://evil.example.com", true); w.Code != 204 || w.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Fatalf("expected preflight from not allowed origin to be denied, but got: %d %v", w.Code, w.Header())
+	}
+	if w = serve(cors, "OPTIONS", "/none", "https://app.example.com", true); w.Code != 404 {
+		t.Fatalf("expected preflight for unknown path to fall through, but got: %d", w.Code)
+	}
+
+	w = serve(cors, "PUT", "/users/5", "https://app.example.com", false)
+	if w.Body.String() != "PUT 5" || w.Header().Get("Access-Control-Allow-Origin") != "https://app.example.com" || w.Header().Get("Access-Control-Expose-Headers") != "X-Request-Id" {
+		t.Fatalf("expected actual request to be served with cors headers, but got: %q %v", w.Body.String(), w.Header())
+	}
+	if w = serve(cors, "GET", "/users/5", "", false); w.Body.String() != "GET 5" || w.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Fatalf("expected same origin request to be served without cors headers, but got: %v", w.Header())
+	}
+
+	any := fastroute.CORS(router, fastroute.CORSOptions{
+		AllowedOrigins: []string{"*"},
+		AllowedMethods: []string{"GET"},
+		AllowedHeaders: []string{"*"},
+	})
+	w = serve(any, "OPTIONS", "/users/5", "https://other.example.com", true)
+	if w.Header().Get("Access-Control-Allow-Methods") != "GET" || w.Header().Get("Access-Control-Allow-Headers") != "content-type" || w.Header().Get("Access-Control-Allow-Origin") != "*" {
+		t.Fatalf("expected configured methods and requested headers to be allowed, but got: %v", w.Header())
+	}
+
+	// any origin is never given credentials, only listed ones
+	credentialed := fastroute.CORS(router, fastroute.CORSOptions{
+		AllowedOrigins:   []string{"*", "https://app.example.com"},
+		AllowCredentials: true,
+	})
+	cases := []struct {
+		router               fastroute.Router
+		origin               string
+		allowed, creds, vary string
+	}{
+		{credentialed, "https://evil.example.com", "*", "", "Origin"},
+		{credentialed, "https://app.example.com", "https://app.example.com", "true", "Origin"},
+		{credentialed, "", "", "", "Origin"},
+		{cors, "https://evil.example.com", "", "", "Origin"},
+		{cors, "", "", "", "Origin"},
+		{any, "https://other.example.com", "*", "", ""},
+	}
+	for i, c := range cases {
+		w = serve(c.router, "GET", "/users/5", c.origin, false)
+		if w.Header().Get("Access-Control-Allow-Origin") != c.allowed || w.Header().Get("Access-Control-Allow-Credentials") != c.creds || w.Header().Get("Vary") != c.vary {
+			t.Fatalf("unexpected cors headers: %v, at case %d", w.Header(), i)
+		}
+	}
+}
+
+func TestCORSPreflightIsNotMatched(t *testing.T) {
+	t.Parallel()
+	handler := func(w http.ResponseWriter, req *http.Request) {}
+
+	var consulted int
+	limited := fastroute.RateLimitByParam(fastroute.Chain(
+		fastroute.Host("api.example.com", fastroute.MethodGroup("PUT", fastroute.New("/users/:id", handler))),
+		fastroute.NewTemplate("/v1/{name=shelves/*}", handler),
+	), "id", func(key string) bool {
+		consulted++
+		return true
+	})
+	counted, stats := fastroute.Stats(limited)
+	cors := fastroute.CORS(counted, fastroute.CORSOptions{AllowedOrigins: []string{"*"}})
+
+	for _, c := range []struct{ url, methods string }{
+		{"http://api.example.com/users/5", "PUT"},
+		{"http://other.example.com/v1/shelves/s1", "GET, HEAD, POST, PUT, PATCH, DELETE"},
+	} {
+		req, _ := http.NewRequest("OPTIONS", c.url, nil)
+		req.Header.Set("Origin", "https://app.example.com")
+		req.Header.Set("Access-Control-Request-Method", "PUT")
+		w := httptest.NewRecorder()
+		cors.ServeHTTP(w, req)
+		if w.Code != http.StatusNoContent || w.Header().Get("Access-Control-Allow-Methods") != c.methods {
+			t.Fatalf("expected preflight of %s to allow: %s, but got: %d %v", c.url, c.methods, w.Code, w.Header())
+		}
+	}
+
+	for pattern, s := range stats.Snapshot() {
+		if s.Matches != 0 {
+			t.Fatalf("expected preflight not to be counted as a match of: %s", pattern)
+		}
+	}
+	if consulted != 0 {
+		t.Fatalf("expected preflight not to consume rate limit, but limiter was consulted %d times", consulted)
+	}
+}
@@ -0,0 +1,78 @@
+package fastroute
+
+import (
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// DebugHandler serves the route table of router, as
+// described by Inspect, in order to find out which
+// routes are registered in the running build.
+//
+// The table is rendered as JSON, or as HTML when the
+// client accepts text/html. Statistics are included
+// for routes counted by Stats. Large tables may be
+// paged by "offset" and "limit" query parameters,
+// while X-Total-Count header holds the number of all
+// routes.
+//
+// The table is described on every request, so it
+// reflects the live state of router, like routes taken
+// out of service by Toggle. It may be registered next
+// to the routes it describes:
+//
+//	routes := fastroute.Chain(...)
+//	app := fastroute.Chain(routes, fastroute.New("/_routes", fastroute.DebugHandler(routes)))
+//
+// Route table reveals application internals, so it
+// should be guarded, for example by CIDR.
+func DebugHandler(router Router) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		routes := Inspect(router)
+		w.Header().Set("X-Total-Count", strconv.Itoa(len(routes)))
+
+		query := req.URL.Query()
+		if offset, err := strconv.Atoi(query.Get("offset")); err == nil && offset > 0 {
+			if offset > len(routes) {
+				offset = len(routes)
+			}
+			routes = routes[offset:]
+		}
+		if limit, err := strconv.Atoi(query.Get("limit")); err == nil && limit >= 0 && limit < len(routes) {
+			routes = routes[:limit]
+		}
+
+		if strings.Contains(req.Header.Get("Accept"), "text/html") {
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			debugTemplate.Execute(w, routes)
+			return
+		}
+
+		if routes == nil {
+			routes = []RouteInfo{}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(routes)
+	})
+}
+
+var debugTemplate = template.Must(template.New("routes").Parse(`<!DOCTYPE html>
+<html>
+<head><title>Routes</title></head>
+<body>
+<table>
+<tr><th>Pattern</th><th>Methods</th><th>Host</th><th>Handler</th><th>Matches</th><th>Last matched</th></tr>
+{{range .}}<tr>
+<td>{{.Pattern}}{{if .Disabled}} (disabled){{end}}</td>
+<td>{{range $i, $m := .Methods}}{{if $i}}, {{end}}{{$m}}{{end}}</td>
+<td>{{.Host}}</td>
+<td>{{.Handler}}</td>
+{{if .Stats}}<td>{{.Stats.Matches}}</td><td>{{if not .Stats.LastMatched.IsZero}}{{.Stats.LastMatched}}{{end}}</td>{{else}}<td></td><td></td>{{end}}
+</tr>
+{{end}}</table>
+</body>
+</html>
+`))
@@ -0,0 +1,97 @@
+package fastroute_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+func TestDebugHandler(t *testing.T) {
+	t.Parallel()
+	handler := func(w http.ResponseWriter, req *http.Request) {}
+
+	router, _ := fastroute.Stats(fastroute.Chain(
+		fastroute.MethodGroup("GET", fastroute.New("/users/:id", handler)),
+		fastroute.New("/status", handler),
+		fastroute.New("/<script>", handler),
+	))
+	req, _ := http.NewRequest("GET", "/users/1", nil)
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	debug := fastroute.DebugHandler(router)
+	serve := func(query, accept string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", "/debug/routes"+query, nil)
+		req.Header.Set("Accept", accept)
+		w := httptest.NewRecorder()
+		debug.ServeHTTP(w, req)
+		return w
+	}
+
+	w := serve("", "application/json")
+	var routes []fastroute.RouteInfo
+	if err := json.Unmarshal(w.Body.Bytes(), &routes); err != nil {
+		t.Fatal(err)
+	}
+	if len(routes) != 3 || w.Header().Get("X-Total-Count") != "3" {
+		t.Fatalf("expected all routes to be listed, but got: %+v", routes)
+	}
+	if routes[0].Pattern != "/users/:id" || routes[0].Methods[0] != "GET" || routes[0].Stats == nil || routes[0].Stats.Matches != 1 {
+		t.Fatalf("expected route to be described with stats, but got: %+v", routes[0])
+	}
+
+	w = serve("?offset=1&limit=1", "")
+	routes = nil
+	if err := json.Unmarshal(w.Body.Bytes(), &routes); err != nil {
+		t.Fatal(err)
+	}
+	if len(routes) != 1 || routes[0].Pattern != "/status" {
+		t.Fatalf("expected a page of routes, but got: %+v", routes)
+	}
+
+	if w = serve("?offset=5", ""); strings.TrimSpace(w.Body.String()) != "[]" {
+		t.Fatalf("expected an empty page, but got: %s", w.Body.String())
+	}
+
+	w = serve("", "text/html,application/xhtml+xml")
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/html") {
+		t.Fatalf("expected html content type, but got: %s", ct)
+	}
+	if body := w.Body.String(); !strings.Contains(body, "<td>/users/:id</td>") || !strings.Contains(body, "&lt;script&gt;") {
+		t.Fatalf("expected escaped html table of routes, but got: %s", body)
+	}
+}
+
+func TestDebugHandlerReflectsLiveTable(t *testing.T) {
+	t.Parallel()
+	handler := func(w http.ResponseWriter, req *http.Request) {}
+
+	beta, betaRoutes := fastroute.Toggle(fastroute.New("/beta", handler))
+	routes := fastroute.Chain(fastroute.New("/status", handler), betaRoutes)
+	app := fastroute.Chain(routes, fastroute.New("/_routes", fastroute.DebugHandler(routes)))
+
+	disabled := func() bool {
+		req, _ := http.NewRequest("GET", "/_routes", nil)
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, req)
+		var routes []fastroute.RouteInfo
+		if err := json.Unmarshal(w.Body.Bytes(), &routes); err != nil {
+			t.Fatal(err)
+		}
+		if len(routes) != 2 || routes[1].Pattern != "/beta" {
+			t.Fatalf("expected routes to be listed, but got: %+v", routes)
+		}
+		return routes[1].Disabled
+	}
+
+	if disabled() {
+		t.Fatal("expected beta route to be enabled")
+	}
+	beta.Disable()
+	if !disabled() {
+		t.Fatal("expected beta route to be reported disabled")
+	}
+}
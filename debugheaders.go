@@ -0,0 +1,53 @@
+package fastroute
+
+import (
+	"net/http"
+	"net/url"
+)
+
+// DebugHeaders wraps router in order to tell which route
+// handled the request by response headers, for example
+// when trying routes by curl in development:
+//
+//	X-Matched-Route: /users/:id
+//	X-Matched-Params: id=5
+//
+// Params are encoded the way query is, in the order they
+// are bound. Headers are set before the matched handler
+// is served, so the handler may still remove them. Routes
+// and parameter values leak application internals, so it
+// should be guarded by a flag, not to reach production:
+//
+//	if *dev {
+//		router = fastroute.DebugHeaders(router)
+//	}
+func DebugHeaders(router Router) Router {
+	return wrapper{RouterFunc(func(req *http.Request) http.Handler {
+		h := router.Route(req)
+		if h == nil {
+			return nil
+		}
+
+		// parameters are recycled when served
+		pattern, params := Pattern(req), encodeParams(Parameters(req))
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			w.Header().Set("X-Matched-Route", pattern)
+			if params != "" {
+				w.Header().Set("X-Matched-Params", params)
+			}
+			h.ServeHTTP(w, req)
+		})
+	}), router}
+}
+
+// encodes params as query, keeping their order
+func encodeParams(ps Params) string {
+	var encoded string
+	for i, p := range ps {
+		if i > 0 {
+			encoded += "&"
+		}
+		encoded += url.QueryEscape(p.Key) + "=" + url.QueryEscape(p.Value)
+	}
+	return encoded
+}
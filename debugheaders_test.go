@@ -0,0 +1,38 @@
+package fastroute_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+func TestDebugHeaders(t *testing.T) {
+	t.Parallel()
+	handler := func(w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte("served"))
+	}
+	router := fastroute.DebugHeaders(fastroute.Chain(
+		fastroute.New("/status", handler),
+		fastroute.Host(":tenant.example.com", fastroute.New("/files/*path", handler)),
+	))
+
+	cases := []struct {
+		url, route, params string
+		code               int
+	}{
+		{"http://localhost/status", "/status", "", 200},
+		{"http://acme.example.com/files/a b/c.txt", "/files/*path", "tenant=acme&path=%2Fa+b%2Fc.txt", 200},
+		{"http://localhost/missing", "", "", 404},
+	}
+	for _, c := range cases {
+		req, _ := http.NewRequest("GET", c.url, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != c.code || w.Header().Get("X-Matched-Route") != c.route || w.Header().Get("X-Matched-Params") != c.params {
+			t.Fatalf("expected %s to respond %d with route %q and params %q, but got: %d %v", c.url, c.code, c.route, c.params, w.Code, w.Header())
+		}
+	}
+}
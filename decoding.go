@@ -0,0 +1,48 @@
+//go:build go1.5
+// +build go1.5
+
+package fastroute
+
+import "net/http"
+
+// PathDecoding determines which form of request
+// path is matched, see WithPathDecoding.
+type PathDecoding int
+
+const (
+	// DecodedPath matches URL.Path, as decoded by
+	// net/http. It is what routes match by default.
+	DecodedPath PathDecoding = iota
+
+	// RawPath matches URL.EscapedPath, the path as
+	// it was sent by the client.
+	RawPath
+)
+
+// WithPathDecoding wraps router in order to match the
+// given form of request path.
+//
+// With DecodedPath an encoded slash "%2F" is decoded
+// before matching, so it splits segments: a value like
+// "a%2Fb" cannot be matched by a single parameter, and
+// may match a different route than the client meant.
+//
+// With RawPath segments are only split by slashes sent
+// as is, so "/files/a%2Fb" binds file="a%2Fb". Parameter
+// values remain escaped, handler should decode them with
+// url.PathUnescape, and must not trust them
+// to be free of encoded dot segments like "%2E%2E".
+// Static patterns must be written in escaped form too.
+// Handler is served with the request path unchanged.
+func WithPathDecoding(mode PathDecoding, router Router) Router {
+	if mode != RawPath {
+		return router
+	}
+	return wrapper{RouterFunc(func(req *http.Request) http.Handler {
+		path := req.URL.Path
+		req.URL.Path = req.URL.EscapedPath()
+		h := router.Route(req)
+		req.URL.Path = path
+		return h
+	}), router}
+}
@@ -0,0 +1,45 @@
+//go:build go1.5
+// +build go1.5
+
+package fastroute_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+func TestWithPathDecoding(t *testing.T) {
+	t.Parallel()
+	handler := func(w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte(fastroute.Parameters(req).ByName("file") + " " + req.URL.Path))
+	}
+	routes := fastroute.Chain(
+		fastroute.New("/files/:file", handler),
+		fastroute.New("/files/:dir/:file", handler),
+	)
+
+	cases := []struct {
+		mode      fastroute.PathDecoding
+		url, body string
+	}{
+		{fastroute.DecodedPath, "/files/a%2Fb", "b /files/a/b"},
+		{fastroute.RawPath, "/files/a%2Fb", "a%2Fb /files/a/b"},
+		{fastroute.RawPath, "/files/a/b", "b /files/a/b"},
+		{fastroute.RawPath, "/files/a%20b", "a%20b /files/a b"},
+	}
+
+	for i, c := range cases {
+		req, err := http.NewRequest("GET", c.url, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w := httptest.NewRecorder()
+		fastroute.WithPathDecoding(c.mode, routes).ServeHTTP(w, req)
+		if w.Body.String() != c.body {
+			t.Fatalf("expected response: %q, but got: %q, case: %d", c.body, w.Body.String(), i)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package fastroute
+
+import (
+	"net/http"
+	"strings"
+)
+
+// MaxCatchAllDepth wraps router in order to limit the
+// depth of paths matched by catch-all parameters to n
+// segments, for example to guard file or proxy routes
+// against traversal abuse:
+//
+//	Pattern: /files/*path
+//	Limit: 2
+//
+//	Paths:
+//	 /files/a.txt            match: path="/a.txt"
+//	 /files/docs/a.txt       match: path="/docs/a.txt"
+//	 /files/docs/x/a.txt     no match
+//
+// Depth is the number of slashes in the catch-all
+// value. Requests over the limit are not matched, so
+// they fall through to the following routes. Routes
+// without catch-all parameter are not affected.
+func MaxCatchAllDepth(n int, router Router) Router {
+	return wrapper{RouterFunc(func(req *http.Request) http.Handler {
+		h := router.Route(req)
+		if h == nil {
+			return nil
+		}
+		pattern := Pattern(req)
+		if q := queryStart(pattern); q != -1 {
+			pattern = pattern[:q]
+		}
+		pos := strings.Index(pattern, "/*")
+		if pos == -1 {
+			return h
+		}
+		var depth int
+		if name := catchAllName(pattern[pos+2:]); name == "" {
+			// anonymous catch-all binds nothing, but takes
+			// the segments left after the ones of pattern
+			depth = strings.Count(req.URL.Path, "/") - strings.Count(pattern, "/") + 1
+		} else {
+			depth = strings.Count(Parameters(req).ByName(name), "/")
+		}
+		if depth > n {
+			Recycle(req)
+			return nil
+		}
+		return h
+	}), router}
+}
+
+// name of catch-all parameter, which pattern rest
+// following its sign starts with
+func catchAllName(rest string) string {
+	if pos := strings.IndexAny(rest, "/\\"); pos != -1 {
+		return rest[:pos]
+	}
+	return rest
+}
@@ -0,0 +1,54 @@
+package fastroute_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+func TestMaxCatchAllDepth(t *testing.T) {
+	t.Parallel()
+	handler := func(w http.ResponseWriter, req *http.Request) {}
+
+	router := fastroute.MaxCatchAllDepth(2, fastroute.Chain(
+		fastroute.New("/files/*path", handler),
+		fastroute.New("/a/b/c/d", handler),
+		fastroute.New("/assets/:version/*", handler),
+		fastroute.New("/proxy/*target/status?verbose=:verbose?", handler),
+		fastroute.New("/search/*scope?q=:query", handler),
+	))
+
+	cases := map[string]bool{
+		"/assets/v1/a.css":                true,
+		"/assets/v1/css/a.css":            true,
+		"/assets/v1/css/x/a.css":          false,
+		"/files/":                         true,
+		"/files/a.txt":                    true,
+		"/files/docs/a.txt":               true,
+		"/files/docs/a/":                  false,
+		"/files/docs/x/a.txt":             false,
+		"/files/docs/x/y/a.txt":           false,
+		"/a/b/c/d":                        true,
+		"/proxy/a/b/status":               true,
+		"/proxy/a/b/c/status":             false,
+		"/proxy/a/status?verbose=x/y/z/w": true,
+		"/search/a/b?q=x/y/z/w":           true, // query value is not the catch-all
+		"/search/a/b/c?q=x":               false,
+	}
+
+	for path, match := range cases {
+		req, err := http.NewRequest("GET", path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		h := router.Route(req)
+		if match != (h != nil) {
+			t.Fatalf("expected match to be %t for: %s", match, path)
+		}
+		if !match && fastroute.Parameters(req) != nil {
+			t.Fatalf("expected parameters to be recycled for: %s", path)
+		}
+		fastroute.Recycle(req)
+	}
+}
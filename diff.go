@@ -0,0 +1,186 @@
+package fastroute
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ErrIncomparable is returned by Diff and Fingerprint,
+// when a router cannot be enumerated completely.
+var ErrIncomparable = errors.New("fastroute: route table is not enumerable, opaque routers cannot be compared")
+
+// Diff compares route tables of the old and new router, as
+// described by Inspect, for example to check a candidate
+// build before it is deployed. Routes are identified by
+// host and path pattern:
+//
+//	api.example.com/users/:id
+//
+// Added and removed list routes only found in the new or
+// the old table. Changed lists routes found in both, which
+// accept different methods, like:
+//
+//	/users/:id (GET -> GET, DELETE)
+//
+// where "*" stands for any method. All the lists are sorted.
+//
+// Opaque routers are not guessed about. If either table
+// cannot be enumerated completely, the routes which could
+// be are compared and ErrIncomparable is returned.
+func Diff(old, new Router) (added, removed, changed []string, err error) {
+	oldRoutes, oldComplete := inspect(old)
+	newRoutes, newComplete := inspect(new)
+	if !oldComplete || !newComplete {
+		err = ErrIncomparable
+	}
+
+	before, after := routeMethods(oldRoutes), routeMethods(newRoutes)
+	for key, methods := range after {
+		if prev, ok := before[key]; !ok {
+			added = append(added, key)
+		} else if prev != methods {
+			changed = append(changed, key+" ("+prev+" -> "+methods+")")
+		}
+	}
+	for key := range before {
+		if _, ok := after[key]; !ok {
+			removed = append(removed, key)
+		}
+	}
+
+	sort.Strings(added)
+	sort.Strings(removed)
+	sort.Strings(changed)
+	return added, removed, changed, err
+}
+
+// methods accepted by each route, identified by host and
+// pattern, routes scoped by method are merged
+func routeMethods(routes []RouteInfo) map[string]string {
+	accepted := map[string]map[string]bool{}
+	for _, info := range routes {
+		key := info.Host + info.Pattern
+		methods, ok := accepted[key]
+		if !ok {
+			methods = map[string]bool{}
+			accepted[key] = methods
+		}
+		if info.Methods == nil {
+			methods["*"] = true
+		}
+		for _, m := range info.Methods {
+			methods[m] = true
+		}
+	}
+
+	described := make(map[string]string, len(accepted))
+	for key, methods := range accepted {
+		var list []string
+		if methods["*"] {
+			list = []string{"*"}
+		} else {
+			for m := range methods {
+				list = append(list, m)
+			}
+			sort.Strings(list)
+		}
+		described[key] = strings.Join(list, ", ")
+	}
+	return described
+}
+
+// Fingerprint hashes the route table of router, as described
+// by Inspect, so route tables of two builds can be compared by
+// a single string, for example logged or served along with
+// the version.
+//
+// Each route is identified by host and pattern, along with the
+// methods it is constrained by. Parameter names and trailing
+// slashes matter, as they are a part of the pattern, while
+// handlers are not taken into account. Routes are sorted, so
+// the order of registration does not matter, unless routes may
+// match the same request, like "/users/me" and "/users/:id"
+// both accepting GET, since the first one tried serves it.
+// Such routes keep their order.
+//
+// It returns ErrIncomparable, if router cannot be enumerated
+// completely.
+func Fingerprint(router Router) (string, error) {
+	routes, complete := inspect(router)
+	if !complete {
+		return "", ErrIncomparable
+	}
+
+	// number of preceding routes, which may match
+	// the same request, so must be hashed first
+	keys := make([]string, len(routes))
+	segments := make([][]string, len(routes))
+	preceding := make([]int, len(routes))
+	for i, info := range routes {
+		methods := "*"
+		if info.Methods != nil {
+			list := append([]string(nil), info.Methods...)
+			sort.Strings(list)
+			methods = strings.Join(list, ", ")
+		}
+		keys[i] = info.Host + info.Pattern + " (" + methods + ")"
+		path := info.Pattern
+		if q := queryStart(path); q != -1 {
+			path = path[:q]
+		}
+		segments[i] = strings.Split(path, "/")
+		for j := 0; j < i; j++ {
+			if routesOverlap(routes[j], routes[i], segments[j], segments[i]) {
+				preceding[i]++
+			}
+		}
+	}
+
+	// the least route of the ones having no preceding
+	// route left is hashed next
+	hash := sha256.New()
+	done := make([]bool, len(routes))
+	for range routes {
+		next := -1
+		for i := range routes {
+			if !done[i] && preceding[i] == 0 && (next == -1 || keys[i] < keys[next]) {
+				next = i
+			}
+		}
+		done[next] = true
+		fmt.Fprintln(hash, keys[next])
+		for i := next + 1; i < len(routes); i++ {
+			if routesOverlap(routes[next], routes[i], segments[next], segments[i]) {
+				preceding[i]--
+			}
+		}
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// whether routes may match the same request, told by host,
+// methods and pattern segments. Parameters are taken to match
+// any segment, catch-all and template ones any rest of path
+func routesOverlap(a, b RouteInfo, as, bs []string) bool {
+	if !strings.EqualFold(a.Host, b.Host) && a.Host != "" && b.Host != "" &&
+		strings.IndexByte(a.Host, ':') == -1 && strings.IndexByte(b.Host, ':') == -1 {
+		return false
+	}
+	if !overlap(a.Methods, b.Methods) {
+		return false
+	}
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		x, y := as[i], bs[i]
+		if strings.HasPrefix(x, "*") || strings.HasPrefix(y, "*") || strings.ContainsAny(x+y, "{}") {
+			return true
+		}
+		if x != y && !strings.ContainsAny(x, ":*\\") && !strings.ContainsAny(y, ":*\\") {
+			return false
+		}
+	}
+	return len(as) == len(bs)
+}
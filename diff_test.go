@@ -0,0 +1,124 @@
+package fastroute_test
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+func TestDiff(t *testing.T) {
+	t.Parallel()
+	handler := func(w http.ResponseWriter, req *http.Request) {}
+
+	old := fastroute.Chain(
+		fastroute.MethodGroup("GET", fastroute.New("/users/:id", handler)),
+		fastroute.New("/status", handler),
+		fastroute.New("/legacy", handler),
+		fastroute.Host("api.example.com", fastroute.New("/orders", handler)),
+	)
+	candidate := fastroute.Chain(
+		fastroute.New("/metrics", handler),
+		fastroute.MethodGroup("DELETE", fastroute.New("/users/:id", handler)),
+		fastroute.MethodGroup("GET", fastroute.New("/users/:id", handler)),
+		fastroute.MethodGroup("GET", fastroute.New("/status", handler)),
+		fastroute.Host("api.example.com", fastroute.New("/orders", handler)),
+		fastroute.New("/orders", handler),
+	)
+
+	added, removed, changed, err := fastroute.Diff(old, candidate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"/metrics", "/orders"}; !reflect.DeepEqual(added, expected) {
+		t.Fatalf("expected added routes: %v, but got: %v", expected, added)
+	}
+	if expected := []string{"/legacy"}; !reflect.DeepEqual(removed, expected) {
+		t.Fatalf("expected removed routes: %v, but got: %v", expected, removed)
+	}
+	expected := []string{"/status (* -> GET)", "/users/:id (GET -> DELETE, GET)"}
+	if !reflect.DeepEqual(changed, expected) {
+		t.Fatalf("expected changed routes: %v, but got: %v", expected, changed)
+	}
+
+	if added, removed, changed, err = fastroute.Diff(old, old); err != nil || added != nil || removed != nil || changed != nil {
+		t.Fatalf("expected no difference, but got: %v %v %v %v", added, removed, changed, err)
+	}
+
+	opaque := fastroute.Chain(candidate, fastroute.RouterFunc(func(req *http.Request) http.Handler {
+		return nil
+	}))
+	added, _, _, err = fastroute.Diff(old, opaque)
+	if err != fastroute.ErrIncomparable || len(added) != 2 {
+		t.Fatalf("expected enumerable routes to be compared with an error, but got: %v %v", added, err)
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	t.Parallel()
+	handler := func(w http.ResponseWriter, req *http.Request) {}
+	other := func(w http.ResponseWriter, req *http.Request) {}
+
+	fingerprint := func(router fastroute.Router) string {
+		fp, err := fastroute.Fingerprint(router)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return fp
+	}
+
+	table := fingerprint(fastroute.Chain(
+		fastroute.MethodGroup("GET", fastroute.New("/users/:id", handler)),
+		fastroute.MethodGroup("DELETE", fastroute.New("/users/:id", handler)),
+		fastroute.New("/status", handler),
+	))
+	if len(table) != 64 {
+		t.Fatalf("expected hex encoded sha256 fingerprint, but got: %s", table)
+	}
+
+	if fingerprint(fastroute.Chain(
+		fastroute.MethodGroup("GET", fastroute.New("/users/:id", other)),
+		fastroute.MethodGroup("DELETE", fastroute.New("/users/:id", other)),
+		fastroute.New("/status", other),
+	)) != table {
+		t.Fatal("expected fingerprint not to depend on handlers")
+	}
+
+	for _, router := range []fastroute.Router{
+		fastroute.Chain(fastroute.New("/status", handler), fastroute.MethodGroup("GET", fastroute.New("/users/:id", handler)), fastroute.MethodGroup("DELETE", fastroute.New("/users/:id", handler))),
+		fastroute.Chain(fastroute.MethodGroup("DELETE", fastroute.New("/users/:id", handler)), fastroute.New("/status", handler), fastroute.MethodGroup("GET", fastroute.New("/users/:id", handler))),
+	} {
+		if fingerprint(router) != table {
+			t.Fatalf("expected fingerprint not to depend on registration order of: %v", fastroute.Inspect(router))
+		}
+	}
+
+	for _, router := range []fastroute.Router{
+		fastroute.Chain(fastroute.MethodGroup("GET", fastroute.New("/users/:name", handler)), fastroute.MethodGroup("DELETE", fastroute.New("/users/:id", handler)), fastroute.New("/status", handler)),
+		fastroute.Chain(fastroute.MethodGroup("GET", fastroute.New("/users/:id/", handler)), fastroute.MethodGroup("DELETE", fastroute.New("/users/:id", handler)), fastroute.New("/status", handler)),
+		fastroute.Chain(fastroute.MethodGroup("GET", fastroute.New("/users/:id", handler)), fastroute.MethodGroup("PUT", fastroute.New("/users/:id", handler)), fastroute.New("/status", handler)),
+		fastroute.Chain(fastroute.MethodGroup("GET", fastroute.New("/users/:id", handler)), fastroute.MethodGroup("DELETE", fastroute.New("/users/:id", handler))),
+		fastroute.Chain(fastroute.MethodGroup("GET", fastroute.New("/users/:id", handler)), fastroute.MethodGroup("DELETE", fastroute.Host("api.example.com", fastroute.New("/users/:id", handler))), fastroute.New("/status", handler)),
+	} {
+		if fingerprint(router) == table {
+			t.Fatalf("expected fingerprint to differ from: %s", table)
+		}
+	}
+
+	// first match wins, so the order of routes matters
+	me := fastroute.New("/users/me", handler)
+	byID := fastroute.New("/users/:id", handler)
+	if fingerprint(fastroute.Chain(me, byID)) == fingerprint(fastroute.Chain(byID, me)) {
+		t.Fatal("expected fingerprint to depend on the order routes are tried")
+	}
+	feed := fastroute.MethodGroup("POST", fastroute.New("/users/me", handler))
+	user := fastroute.MethodGroup("GET", byID)
+	if fingerprint(fastroute.Chain(user, feed)) != fingerprint(fastroute.Chain(feed, user)) {
+		t.Fatal("expected fingerprint not to depend on the order of routes accepting other methods")
+	}
+
+	if _, err := fastroute.Fingerprint(fastroute.RouterFunc(func(req *http.Request) http.Handler { return nil })); err != fastroute.ErrIncomparable {
+		t.Fatalf("expected opaque router not to be fingerprinted, but got: %v", err)
+	}
+}
@@ -0,0 +1,62 @@
+package fastroute
+
+import (
+	"io"
+	"net/http"
+)
+
+// MaxDispatchDepth limits how many times a request
+// may be dispatched by handlers it was dispatched to.
+const MaxDispatchDepth = 10
+
+// Dispatch serves request internally, as if it was made
+// for the given path, by the handler router matches. It
+// is meant for handlers doing internal redirects, or
+// composing responses of other routes.
+//
+// Path of the request is rewritten, parameters bound
+// by the matched route are available to the dispatched
+// handler instead of the current ones, and all of it is
+// restored once served. If router does not match the
+// path, http.NotFound is served.
+//
+// Dispatching from a dispatched handler is allowed up to
+// MaxDispatchDepth levels, deeper requests are considered
+// a loop and are answered with 508 Loop Detected.
+func Dispatch(router Router, w http.ResponseWriter, req *http.Request, newPath string) {
+	body := req.Body
+	original := body
+	if p, _ := body.(*parameters); p != nil {
+		original = p.ReadCloser // current parameters are not visible to dispatched handler
+	}
+
+	depth := 1
+	if d, _ := original.(*dispatched); d != nil {
+		depth = d.depth + 1
+	}
+	if depth > MaxDispatchDepth {
+		http.Error(w, "Loop Detected", 508)
+		return
+	}
+
+	path, rawPath := req.URL.Path, req.URL.RawPath
+	req.URL.Path, req.URL.RawPath = newPath, ""
+	req.Body = &dispatched{original, depth}
+
+	defer func() {
+		req.URL.Path, req.URL.RawPath = path, rawPath
+		req.Body = body
+	}()
+
+	if h := router.Route(req); h != nil {
+		h.ServeHTTP(w, req)
+	} else {
+		http.NotFound(w, req)
+	}
+}
+
+// marks request body of dispatched request
+type dispatched struct {
+	io.ReadCloser
+	depth int
+}
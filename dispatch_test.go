@@ -0,0 +1,56 @@
+package fastroute_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+func TestDispatch(t *testing.T) {
+	t.Parallel()
+
+	var router fastroute.Router
+	router = fastroute.Chain(
+		fastroute.New("/users/:id", func(w http.ResponseWriter, req *http.Request) {
+			fmt.Fprintf(w, "user %s at %s;", fastroute.Parameters(req).ByName("id"), req.URL.Path)
+		}),
+		fastroute.New("/profiles/:name/:id", func(w http.ResponseWriter, req *http.Request) {
+			params := fastroute.Parameters(req)
+			fastroute.Dispatch(router, w, req, "/users/"+params.ByName("id"))
+			fastroute.Dispatch(router, w, req, "/missing")
+			fmt.Fprintf(w, "profile %s %s at %s;", params.ByName("name"), fastroute.Parameters(req).ByName("id"), req.URL.Path)
+		}),
+		fastroute.New("/loop/:id", func(w http.ResponseWriter, req *http.Request) {
+			fastroute.Dispatch(router, w, req, req.URL.Path)
+		}),
+	)
+
+	req, err := http.NewRequest("GET", "/profiles/john/5", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := req.Body
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	expected := "user 5 at /users/5;404 page not found\nprofile john 5 at /profiles/john/5;"
+	if w.Body.String() != expected {
+		t.Fatalf("expected response: %q, but got: %q", expected, w.Body.String())
+	}
+	if req.Body != body || fastroute.Parameters(req) != nil || req.URL.Path != "/profiles/john/5" {
+		t.Fatal("expected request to be restored and parameters recycled")
+	}
+
+	req, _ = http.NewRequest("GET", "/loop/1", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != 508 {
+		t.Fatalf("expected dispatch loop to be detected, but got: %d", w.Code)
+	}
+	if req.Body != nil || fastroute.Parameters(req) != nil {
+		t.Fatal("expected request to be restored after dispatch loop")
+	}
+}
@@ -0,0 +1,75 @@
+package fastroute
+
+import "net/http"
+
+// ErrorOption configures HandleErrors.
+type ErrorOption func(*errorHandler)
+
+// ErrorHandler responds to errors returned by the handler,
+// instead of DefaultErrorHandler, in order to map errors
+// to responses. Parameters of the request are recycled
+// before it is invoked, so Parameters(req) and Pattern(req)
+// are no longer available.
+func ErrorHandler(respond func(http.ResponseWriter, *http.Request, error)) ErrorOption {
+	return func(h *errorHandler) {
+		h.respond = respond
+	}
+}
+
+// DefaultErrorHandler responds with the code of StatusError,
+// even if it is wrapped, or 500 Internal Server Error for
+// any other error.
+func DefaultErrorHandler(w http.ResponseWriter, req *http.Request, err error) {
+	code, ok := statusCode(err)
+	if !ok {
+		code = http.StatusInternalServerError
+	}
+	http.Error(w, http.StatusText(code), code)
+}
+
+// StatusError is an error, which should be responded
+// with the status code, like 400 Bad Request for a body,
+// which cannot be decoded by JSON. DefaultErrorHandler
+// tells it apart, also when it is wrapped, like by
+// fmt.Errorf with %w verb.
+type StatusError struct {
+	Code int
+	Err  error
+}
+
+func (e StatusError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e StatusError) Unwrap() error {
+	return e.Err
+}
+
+// HandleErrors creates http.Handler, which serves request
+// by handler and responds to the error it returns, if any,
+// by DefaultErrorHandler, unless ErrorHandler option is
+// given. Parameters are recycled before the error is
+// responded.
+//
+// New accepts handlers returning error as they are, which
+// is the same as HandleErrors without options.
+func HandleErrors(handler func(http.ResponseWriter, *http.Request) error, options ...ErrorOption) http.Handler {
+	h := &errorHandler{handler: handler, respond: DefaultErrorHandler}
+	for _, option := range options {
+		option(h)
+	}
+	return h
+}
+
+type errorHandler struct {
+	handler func(http.ResponseWriter, *http.Request) error
+	respond func(http.ResponseWriter, *http.Request, error)
+}
+
+func (h *errorHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if err := h.handler(w, req); err != nil {
+		Recycle(req)
+		h.respond(w, req, err)
+	}
+}
@@ -0,0 +1,68 @@
+package fastroute_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+func TestErrorReturningHandler(t *testing.T) {
+	t.Parallel()
+
+	errNotFound := errors.New("no such user")
+	handler := func(w http.ResponseWriter, req *http.Request) error {
+		switch fastroute.Parameters(req).ByName("id") {
+		case "1":
+			w.Write([]byte("user 1"))
+			return nil
+		case "2":
+			return errNotFound
+		}
+		return errors.New("database is down")
+	}
+
+	serve := func(router fastroute.Router, path string) *httptest.ResponseRecorder {
+		req, err := http.NewRequest("GET", path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if params := fastroute.Parameters(req); params != nil {
+			t.Fatalf("expected parameters to be recycled for: %s", path)
+		}
+		return w
+	}
+
+	router := fastroute.New("/users/:id", handler)
+	if w := serve(router, "/users/1"); w.Code != 200 || w.Body.String() != "user 1" {
+		t.Fatalf("expected user to be served, but got: %d - %s", w.Code, w.Body.String())
+	}
+	if w := serve(router, "/users/2"); w.Code != 500 {
+		t.Fatalf("expected default error response, but got: %d", w.Code)
+	}
+
+	mapped := fastroute.New("/users/:id", fastroute.HandleErrors(handler, fastroute.ErrorHandler(func(w http.ResponseWriter, req *http.Request, err error) {
+		if fastroute.Parameters(req) != nil {
+			t.Fatal("expected parameters to be recycled before error handler is invoked")
+		}
+		if err == errNotFound {
+			http.Error(w, err.Error(), http.StatusNotFound)
+		} else {
+			http.Error(w, "Service Unavailable", http.StatusServiceUnavailable)
+		}
+	})))
+
+	if w := serve(mapped, "/users/2"); w.Code != 404 || w.Body.String() != "no such user\n" {
+		t.Fatalf("expected mapped error response, but got: %d - %s", w.Code, w.Body.String())
+	}
+	if w := serve(mapped, "/users/3"); w.Code != 503 {
+		t.Fatalf("expected mapped error response, but got: %d", w.Code)
+	}
+	if w := serve(router, "/users/2"); w.Code != 500 {
+		t.Fatalf("expected default error response of the other router, but got: %d", w.Code)
+	}
+}
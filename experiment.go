@@ -0,0 +1,65 @@
+package fastroute
+
+import (
+	"net/http"
+	"sort"
+)
+
+// Experiment wraps router in order to serve one of variant
+// handlers, instead of the matched one, chosen by the value
+// of named path parameter, for experiments bucketed by path:
+//
+//	fastroute.Experiment(fastroute.New("/users/:id/feed", feed), "id",
+//		map[string]http.Handler{"control": feed, "ranked": rankedFeed},
+//		map[string]int{"control": 90, "ranked": 10},
+//	)
+//
+// The value is hashed by FNV-1a, spread by splitmix64 and
+// taken modulo the sum of weights. Variants take ranges of
+// their weight in the order of their names, so the same
+// value is always assigned the same variant, across
+// processes and restarts, as long as variants and weights
+// are the same. Changing weights reassigns some values.
+//
+// Parameters are available to the variant handler and are
+// recycled once it is served. It panics if there is no
+// handler for a weighted variant, a weight is negative, or
+// weights sum to zero.
+func Experiment(router Router, param string, variants map[string]http.Handler, weights map[string]int) Router {
+	var names []string
+	var total uint64
+	for name, weight := range weights {
+		if variants[name] == nil {
+			panic("there is no handler for experiment variant: " + name)
+		}
+		if weight < 0 {
+			panic("experiment variant weight must not be negative: " + name)
+		}
+		names = append(names, name)
+		total += uint64(weight)
+	}
+	if total == 0 {
+		panic("experiment variant weights must not sum to zero")
+	}
+	sort.Strings(names)
+
+	return wrapper{RouterFunc(func(req *http.Request) http.Handler {
+		if router.Route(req) == nil {
+			return nil
+		}
+		bucket := mix(hash(Parameters(req).ByName(param))) % total
+		var variant http.Handler
+		for _, name := range names {
+			if w := uint64(weights[name]); bucket < w {
+				variant = variants[name]
+				break
+			} else {
+				bucket -= w
+			}
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			variant.ServeHTTP(w, req)
+			Recycle(req)
+		})
+	}), router}
+}
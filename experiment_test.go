@@ -0,0 +1,68 @@
+package fastroute_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+func TestExperiment(t *testing.T) {
+	t.Parallel()
+	variant := func(name string) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			w.Write([]byte(name + " " + fastroute.Parameters(req).ByName("id")))
+		})
+	}
+	feed := func(w http.ResponseWriter, req *http.Request) {
+		t.Error("expected matched handler not to be served")
+	}
+	router := fastroute.Experiment(fastroute.New("/users/:id/feed", feed), "id",
+		map[string]http.Handler{"control": variant("control"), "ranked": variant("ranked"), "off": variant("off")},
+		map[string]int{"control": 75, "ranked": 25, "off": 0},
+	)
+
+	serve := func(path string) string {
+		req, _ := http.NewRequest("GET", path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if fastroute.Parameters(req) != nil {
+			t.Fatalf("expected parameters to be recycled for: %s", path)
+		}
+		return w.Body.String()
+	}
+
+	counts := map[string]int{}
+	for id := 0; id < 4000; id++ {
+		path := "/users/" + strconv.Itoa(id) + "/feed"
+		body := serve(path)
+		if body != serve(path) {
+			t.Fatalf("expected %s to be assigned the same variant", path)
+		}
+		counts[body[:len(body)-len(strconv.Itoa(id))-1]]++
+	}
+	if counts["off"] != 0 || counts["ranked"] < 800 || counts["ranked"] > 1200 || counts["control"]+counts["ranked"] != 4000 {
+		t.Fatalf("expected variants to be assigned by weight, but got: %v", counts)
+	}
+	if body := serve("/other"); body != "404 page not found\n" {
+		t.Fatalf("expected unmatched request to fall through, but got: %q", body)
+	}
+
+	for expected, weights := range map[string]map[string]int{
+		"there is no handler for experiment variant: missing": {"missing": 1},
+		"experiment variant weight must not be negative: a":   {"a": -1},
+		"experiment variant weights must not sum to zero":     {"a": 0},
+	} {
+		func() {
+			defer func() {
+				if err := recover(); fmt.Sprint(err) != expected {
+					t.Fatalf(`expected panic: "%s", but got: "%v"`, expected, err)
+				}
+			}()
+			fastroute.Experiment(router, "id", map[string]http.Handler{"a": variant("a")}, weights)
+		}()
+	}
+}
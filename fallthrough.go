@@ -0,0 +1,22 @@
+package fastroute
+
+import "net/http"
+
+// Fallthrough creates handler, which serves requests
+// matched by router, and passes the rest to next, instead
+// of responding with 404 Not Found as ServeHTTP of router
+// does, so routes may be nested in another routing layer:
+//
+//	http.ListenAndServe(":8080", fastroute.Fallthrough(routes, legacyMux))
+//
+// If next is nil, nothing is written on a miss, leaving
+// the response to the handler wrapping it.
+func Fallthrough(router Router, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if h := router.Route(req); h != nil {
+			h.ServeHTTP(w, req)
+		} else if next != nil {
+			next.ServeHTTP(w, req)
+		}
+	})
+}
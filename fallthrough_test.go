@@ -0,0 +1,41 @@
+package fastroute_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+func TestFallthrough(t *testing.T) {
+	t.Parallel()
+	router := fastroute.New("/users/:id", func(w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte("user " + fastroute.Parameters(req).ByName("id")))
+	})
+	legacy := http.NewServeMux()
+	legacy.HandleFunc("/legacy", func(w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte("legacy"))
+	})
+
+	cases := []struct {
+		next       http.Handler
+		path, body string
+		code       int
+	}{
+		{legacy, "/users/5", "user 5", 200},
+		{legacy, "/legacy", "legacy", 200},
+		{legacy, "/missing", "404 page not found\n", 404},
+		{nil, "/users/5", "user 5", 200},
+		{nil, "/missing", "", 200},
+	}
+	for i, c := range cases {
+		req, _ := http.NewRequest("GET", c.path, nil)
+		w := httptest.NewRecorder()
+		fastroute.Fallthrough(router, c.next).ServeHTTP(w, req)
+
+		if w.Code != c.code || w.Body.String() != c.body {
+			t.Fatalf("expected response: %d %q, but got: %d %q, case: %d", c.code, c.body, w.Code, w.Body.String(), i)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package fastroute
+
+import (
+	"net/http"
+	"net/url"
+)
+
+// FastPath wraps router in order to route requests of the
+// given exact paths, which take most of the traffic, without
+// scanning routes which cannot match them:
+//
+//	router := fastroute.FastPath(routes, "/", "/api/status", "/api/feed")
+//
+// Routes are resolved for each path once, as they are
+// chained. Routes preceding the first one matching the path
+// are skipped, the rest are tried in order as usual, so the
+// result is the same as router gives. Routes of composed
+// routers, like MethodGroup or Host, cannot be told to skip
+// the path in advance, scanning proceeds from such router.
+// Other paths are routed by router, at the cost of comparing
+// the path to the listed ones.
+func FastPath(router Router, paths ...string) Router {
+	flat := flatten(router)
+	candidates := make([][]Router, len(paths))
+	for i, path := range paths {
+		candidates[i] = flat[firstCandidate(flat, path):]
+	}
+
+	return wrapper{RouterFunc(func(req *http.Request) http.Handler {
+		path := req.URL.Path
+		for i := range paths {
+			if paths[i] != path {
+				continue
+			}
+			for _, r := range candidates[i] {
+				if h := r.Route(req); h != nil {
+					return h
+				}
+			}
+			return nil
+		}
+		return router.Route(req)
+	}), router}
+}
+
+// Flatten creates Router, which tries the routes of router
+// in the same order, but walks them in a single loop, instead
+// of descending through nested chains:
+//
+//	router := fastroute.Flatten(fastroute.Chain(users, orders, admin))
+//
+// Chains, like the ones composed by groups of routes, are
+// hoisted, routes are invoked without the indirection of
+// Router interface. Routers wrapping routes, like MethodGroup
+// or Host, routers choosing the route to try, like ABTest
+// or ChainParallel, and opaque routers are kept as they are,
+// their routes are tried by them as usual. The result is enumerable
+// the same way router is. Routes must not be changed after it
+// is flattened, like chains reordered by ChainByPriority.
+func Flatten(router Router) Router {
+	flat := flatten(router)
+	routes := make([]func(*http.Request) http.Handler, len(flat))
+	for i, r := range flat {
+		if rt, ok := r.(route); ok {
+			routes[i] = rt.RouterFunc // never returns Router
+		} else {
+			routes[i] = r.Route
+		}
+	}
+
+	return firstMatch{chain{RouterFunc(func(req *http.Request) http.Handler {
+		for _, r := range routes {
+			if h := r(req); h != nil {
+				return h
+			}
+		}
+		return nil
+	}), flat}}
+}
+
+// routers chained by router in order, chains trying
+// routes in order are flattened, as routes are tried
+// the same, other chains, like ABTest, are kept
+func flatten(router Router) []Router {
+	c, ok := router.(firstMatch)
+	if !ok {
+		return []Router{router}
+	}
+	var flat []Router
+	for _, r := range c.routes {
+		flat = append(flat, flatten(r)...)
+	}
+	return flat
+}
+
+// index of the first router, which may match path, only
+// plain routes matching the path alone can be skipped
+func firstCandidate(routers []Router, path string) int {
+	req := &http.Request{Method: "GET", URL: &url.URL{Path: path}, Header: http.Header{}}
+	for i, router := range routers {
+		r, ok := router.(route)
+		if !ok || queryStart(r.pattern) != -1 {
+			return i // opaque or matches query too
+		}
+		if r.Route(req) != nil {
+			Recycle(req)
+			return i
+		}
+	}
+	return len(routers)
+}
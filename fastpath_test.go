@@ -0,0 +1,155 @@
+package fastroute_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+func TestFastPath(t *testing.T) {
+	t.Parallel()
+	handler := func(w http.ResponseWriter, req *http.Request) {}
+	routes := fastroute.Chain(
+		fastroute.New("/users/:id", handler),
+		fastroute.MethodGroup("POST", fastroute.New("/status", handler)),
+		fastroute.Chain(
+			fastroute.New("/status", handler),
+			fastroute.New("/:any", handler),
+		),
+		fastroute.New("/feed", handler),
+		fastroute.New("/*all", handler),
+	)
+	router := fastroute.FastPath(routes, "/status", "/feed", "/users/5", "/missing/path")
+
+	for _, method := range []string{"GET", "POST"} {
+		for _, path := range []string{"/status", "/feed", "/users/5", "/missing/path", "/users/6", "/other"} {
+			if expected, actual := routedBy(routes, method, path), routedBy(router, method, path); actual != expected {
+				t.Fatalf("expected %s %s to be routed as %q, but got %q", method, path, expected, actual)
+			}
+		}
+	}
+
+	if patterns, _ := fastroute.Patterns(router); len(patterns) != 6 {
+		t.Fatalf("expected fast path router to be enumerable, but got: %v", patterns)
+	}
+}
+
+func hotPathRoutes() fastroute.Router {
+	handler := func(w http.ResponseWriter, req *http.Request) {}
+	routes := make([]fastroute.Router, 0, 101)
+	for i := 0; i < 100; i++ {
+		routes = append(routes, fastroute.New(fmt.Sprintf("/route%d/:id", i), handler))
+	}
+	return fastroute.Chain(append(routes, fastroute.New("/api/status", handler))...)
+}
+
+func Benchmark_HotPath_Chain(b *testing.B) {
+	req, err := http.NewRequest("GET", "/api/status", nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	benchmark(b, hotPathRoutes(), req)
+}
+
+func Benchmark_HotPath_FastPath(b *testing.B) {
+	req, err := http.NewRequest("GET", "/api/status", nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	benchmark(b, fastroute.FastPath(hotPathRoutes(), "/", "/api/feed", "/api/status"), req)
+}
+
+func TestFlatten(t *testing.T) {
+	t.Parallel()
+	handler := func(w http.ResponseWriter, req *http.Request) {}
+	routes := fastroute.Chain(
+		fastroute.Chain(
+			fastroute.New("/users/me", handler),
+			fastroute.Chain(fastroute.New("/users/:id", handler)),
+		),
+		fastroute.MethodGroup("POST", fastroute.New("/status", handler)),
+		fastroute.RouterFunc(func(req *http.Request) http.Handler {
+			if req.URL.Path == "/opaque" {
+				return http.HandlerFunc(handler)
+			}
+			return nil
+		}),
+		fastroute.Chain(
+			fastroute.New("/status", handler),
+			fastroute.New("/*all", handler),
+		),
+	)
+	router := fastroute.Flatten(routes)
+
+	for _, method := range []string{"GET", "POST"} {
+		for _, path := range []string{"/users/me", "/users/5", "/status", "/opaque", "/other"} {
+			if expected, actual := routedBy(routes, method, path), routedBy(router, method, path); actual != expected {
+				t.Fatalf("expected %s %s to be routed as %q, but got %q", method, path, expected, actual)
+			}
+		}
+	}
+
+	expected, _ := fastroute.Patterns(routes)
+	if patterns, complete := fastroute.Patterns(router); fmt.Sprint(patterns) != fmt.Sprint(expected) || complete {
+		t.Fatalf("expected flattened router to enumerate %v, but got: %v %v", expected, patterns, complete)
+	}
+
+	// chains choosing the route to try first are kept
+	experiment := fastroute.ABTest("exp",
+		fastroute.New("/page", handler),
+		fastroute.New("/:page", handler),
+	)
+	for _, variant := range []string{"a", "b"} {
+		for _, r := range []fastroute.Router{experiment, fastroute.Flatten(experiment)} {
+			req, _ := http.NewRequest("GET", "/page", nil)
+			req.AddCookie(&http.Cookie{Name: "exp", Value: variant})
+			if r.Route(req) == nil {
+				t.Fatalf("expected variant %s to match", variant)
+			}
+			if pattern := fastroute.Pattern(req); (pattern == "/:page") != (variant == "b") {
+				t.Fatalf("expected variant %s to be routed, but got %s", variant, pattern)
+			}
+			fastroute.Recycle(req)
+		}
+	}
+}
+
+// 200 routes in groups nested 3 levels deep
+func nestedRoutes() fastroute.Router {
+	handler := func(w http.ResponseWriter, req *http.Request) {}
+	var groups []fastroute.Router
+	for i := 0; i < 4; i++ {
+		var subgroups []fastroute.Router
+		for j := 0; j < 5; j++ {
+			var routes []fastroute.Router
+			for k := 0; k < 10; k++ {
+				routes = append(routes, fastroute.New(fmt.Sprintf("/g%d/s%d/r%d/:id", i, j, k), handler))
+			}
+			subgroups = append(subgroups, fastroute.Chain(routes...))
+		}
+		groups = append(groups, fastroute.Chain(subgroups...))
+	}
+	return fastroute.Chain(groups...)
+}
+
+func Benchmark_200NestedRoutes_Chain(b *testing.B) {
+	req, err := http.NewRequest("GET", "/g3/s4/r9/5", nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	benchmark(b, nestedRoutes(), req)
+}
+
+func Benchmark_200NestedRoutes_Flatten(b *testing.B) {
+	req, err := http.NewRequest("GET", "/g3/s4/r9/5", nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	benchmark(b, fastroute.Flatten(nestedRoutes()), req)
+}
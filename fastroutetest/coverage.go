@@ -0,0 +1,166 @@
+// Package fastroutetest provides utilities for testing
+// applications routed by fastroute.
+package fastroutetest
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+// Coverage is a Router recording which routes of the
+// router it wraps were matched, see NewCoverage.
+type Coverage struct {
+	router fastroute.Router
+	routes []fastroute.RouteInfo // as described by Inspect
+
+	mu  sync.Mutex
+	hit []bool
+}
+
+// NewCoverage wraps router in order to record routes
+// matched while tests are run, so that routes no test
+// exercises are reported:
+//
+//	var coverage = fastroutetest.NewCoverage(app.Router())
+//
+//	func TestMain(m *testing.M) {
+//		code := m.Run()
+//		... // fail when coverage.Report() is not empty
+//		os.Exit(code)
+//	}
+//
+// Routes are enumerated by fastroute.Inspect, so it sees
+// through compositions like MethodGroup and Host, while
+// routes tagged "generated" or "external" by Tagged are
+// not covered. A matched request marks the route found
+// by fastroute.MatchIndex, so routes of the same pattern
+// under different hosts or languages are covered apart.
+// Opaque routes, which cannot be told by index, mark the
+// first route having the matched pattern and accepting
+// the method.
+func NewCoverage(router fastroute.Router) *Coverage {
+	routes := fastroute.Inspect(router)
+	return &Coverage{router: router, routes: routes, hit: make([]bool, len(routes))}
+}
+
+func ignored(info fastroute.RouteInfo) bool {
+	for _, tag := range info.Tags {
+		if tag == "generated" || tag == "external" {
+			return true
+		}
+	}
+	return false
+}
+
+// Route routes request by the wrapped router and
+// marks the matched route.
+func (c *Coverage) Route(req *http.Request) http.Handler {
+	h, i := fastroute.MatchIndex(c.router, req)
+	if h != nil {
+		c.mark(i, req.Method, fastroute.Pattern(req))
+	}
+	return h
+}
+
+// ServeHTTP routes and serves request, or serves
+// http.NotFound if the wrapped router does not match.
+func (c *Coverage) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if h := c.Route(req); h != nil {
+		h.ServeHTTP(w, req)
+	} else {
+		http.NotFound(w, req)
+	}
+}
+
+// Patterns lists patterns of the wrapped router.
+func (c *Coverage) Patterns() []string {
+	patterns, _ := fastroute.Patterns(c.router)
+	return patterns
+}
+
+// Opaque reports whether the wrapped router
+// cannot be enumerated completely.
+func (c *Coverage) Opaque() bool {
+	_, complete := fastroute.Patterns(c.router)
+	return !complete
+}
+
+func (c *Coverage) mark(index int, method, pattern string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if index >= 0 && index < len(c.hit) {
+		c.hit[index] = true
+		return
+	}
+	for i, info := range c.routes {
+		if info.Pattern == pattern && accepts(info.Methods, method) {
+			c.hit[i] = true
+			return
+		}
+	}
+}
+
+func accepts(methods []string, method string) bool {
+	for _, m := range methods {
+		if m == method {
+			return true
+		}
+	}
+	return methods == nil
+}
+
+// Report lists routes, which were never matched, in
+// the order they are tried, each as its methods and
+// pattern, like "GET|HEAD /users/:id", or "* /status"
+// if it accepts any method.
+func (c *Coverage) Report() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var missed []string
+	for i, info := range c.routes {
+		if c.hit[i] || ignored(info) {
+			continue
+		}
+		methods := "*"
+		if info.Methods != nil {
+			methods = strings.Join(info.Methods, "|")
+		}
+		missed = append(missed, methods+" "+info.Pattern)
+	}
+	return missed
+}
+
+// Percent is the percentage of matched routes,
+// 100 if there are no routes to cover.
+func (c *Coverage) Percent() float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var covered, matched int
+	for i, info := range c.routes {
+		if ignored(info) {
+			continue
+		}
+		covered++
+		if c.hit[i] {
+			matched++
+		}
+	}
+	if covered == 0 {
+		return 100
+	}
+	return float64(matched) * 100 / float64(covered)
+}
+
+// Assert fails the test, listing routes never matched,
+// if the percentage of matched routes is below threshold.
+func (c *Coverage) Assert(t testing.TB, threshold float64) {
+	t.Helper()
+	if percent := c.Percent(); percent < threshold {
+		t.Errorf("route coverage %.1f%% is below %.1f%%, routes never matched:\n\t%s",
+			percent, threshold, strings.Join(c.Report(), "\n\t"))
+	}
+}
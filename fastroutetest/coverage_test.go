@@ -0,0 +1,102 @@
+package fastroutetest_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/fastroute"
+	"github.com/DATA-DOG/fastroute/fastroutetest"
+)
+
+// records failures instead of failing the test
+type failures struct {
+	testing.TB
+	errors []string
+}
+
+func (f *failures) Helper() {}
+
+func (f *failures) Errorf(format string, args ...interface{}) {
+	f.errors = append(f.errors, format)
+}
+
+func TestCoverage(t *testing.T) {
+	t.Parallel()
+	handler := func(w http.ResponseWriter, req *http.Request) {}
+	router := fastroute.Chain(
+		fastroute.MethodGroup("GET|HEAD",
+			fastroute.New("/users/:id", handler),
+			fastroute.New("/users", handler),
+		),
+		fastroute.MethodGroup("DELETE", fastroute.New("/users/:id", handler)),
+		fastroute.Host("admin.example.com", fastroute.New("/stats", handler)),
+		fastroute.Tagged(fastroute.New("/generated/:id", handler), "generated"),
+		fastroute.Tagged(fastroute.New("/webhooks/github", handler), "external"),
+		fastroute.New("/status", handler),
+	)
+	coverage := fastroutetest.NewCoverage(router)
+
+	for _, target := range []string{"/users/5", "/generated/1", "/missing", "http://admin.example.com/stats"} {
+		req, _ := http.NewRequest("GET", target, nil)
+		coverage.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	expected := []string{"GET|HEAD /users", "DELETE /users/:id", "* /status"}
+	if report := coverage.Report(); !reflect.DeepEqual(report, expected) {
+		t.Fatalf("expected never matched routes %q, but got: %q", expected, report)
+	}
+	if percent := coverage.Percent(); percent != 40 {
+		t.Fatalf("expected 40%% of routes to be matched, but got: %v", percent)
+	}
+
+	f := &failures{TB: t}
+	coverage.Assert(f, 40)
+	if len(f.errors) != 0 {
+		t.Fatalf("expected coverage to meet threshold, but got: %v", f.errors)
+	}
+	coverage.Assert(f, 50)
+	if len(f.errors) != 1 || !strings.Contains(f.errors[0], "below") {
+		t.Fatalf("expected coverage below threshold to fail, but got: %v", f.errors)
+	}
+
+	if patterns, ok := fastroute.Patterns(coverage); !ok || len(patterns) != 7 {
+		t.Fatalf("expected coverage to be enumerable, but got: %v %v", patterns, ok)
+	}
+}
+
+func TestCoverageOfSamePatterns(t *testing.T) {
+	t.Parallel()
+	handler := func(w http.ResponseWriter, req *http.Request) {}
+	router := fastroute.Chain(
+		fastroute.Host("a.example.com", fastroute.New("/users/:id", handler)),
+		fastroute.Host("b.example.com", fastroute.New("/users/:id", handler)),
+		fastroute.Languages(map[string]fastroute.Router{
+			"en": fastroute.New("/about", handler),
+			"de": fastroute.New("/about", handler),
+		}, "en"),
+	)
+	coverage := fastroutetest.NewCoverage(router)
+
+	for _, target := range []string{"http://b.example.com/users/1", "http://c.example.com/about"} {
+		req, _ := http.NewRequest("GET", target, nil)
+		coverage.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	// languages are tried in the order of their tags
+	expected := []string{"* /users/:id", "* /about"}
+	if report := coverage.Report(); !reflect.DeepEqual(report, expected) {
+		t.Fatalf("expected never matched routes %q, but got: %q", expected, report)
+	}
+
+	req, _ := http.NewRequest("GET", "http://a.example.com/users/1", nil)
+	coverage.ServeHTTP(httptest.NewRecorder(), req)
+	req, _ = http.NewRequest("GET", "/about", nil)
+	req.Header.Set("Accept-Language", "de")
+	coverage.ServeHTTP(httptest.NewRecorder(), req)
+	if report := coverage.Report(); len(report) != 0 {
+		t.Fatalf("expected all routes to be matched, but got: %q", report)
+	}
+}
@@ -0,0 +1,88 @@
+package fastroute
+
+import (
+	"net/http"
+	"strings"
+)
+
+// WithFormatSuffix wraps router in order to match paths
+// having a format suffix, like Rails does, as if it was
+// not there. The format is bound as "format" parameter:
+//
+//	fastroute.WithFormatSuffix(routes, "json", "xml")
+//
+//	Pattern: /users/:id
+//
+//	Requests:
+//	 /users/5.json     match: id="5", format="json"
+//	 /users/5          match: id="5"
+//	 /users/5.csv      match: id="5.csv"
+//
+// Only the listed formats are stripped from the last
+// segment. If router does not match the stripped path,
+// the path is matched as is, without format. Format is
+// bound before path parameters, the way Host binds its
+// parameters. Handler is served with the request path
+// unchanged.
+func WithFormatSuffix(router Router, formats ...string) Router {
+	num := maxParams(router) + 1
+	pool := newParamsPool(num)
+
+	return wrapper{RouterFunc(func(req *http.Request) http.Handler {
+		path := req.URL.Path
+		format := formatSuffix(path, formats)
+		if format == "" {
+			return router.Route(req)
+		}
+
+		ps := boundParams(req)
+		bound := ps != nil
+		if !bound {
+			ps = pool.get()
+		}
+		n := len(ps.params)
+		ps.params.Append("format", format)
+		req.URL.Path = path[:len(path)-len(format)-1]
+
+		var h http.Handler
+		if bound {
+			h = router.Route(req)
+		} else {
+			ps.pattern = req.URL.Path
+			ps.ReadCloser = req.Body
+			req.Body = ps
+			ps.binding, ps.kept = true, len(ps.params)
+			h = router.Route(req)
+			ps.binding = false
+			if h != nil {
+				h = ps.releasing(req, h)
+			}
+		}
+		req.URL.Path = path
+		if h != nil {
+			return h
+		}
+
+		if bound {
+			ps.params = ps.params[:n]
+		} else {
+			ps.reset(req)
+		}
+		return router.Route(req)
+	}), router}
+}
+
+// the listed format path ends with, following
+// a dot in the last segment, or empty string
+func formatSuffix(path string, formats []string) string {
+	dot := strings.LastIndex(path, ".")
+	if dot == -1 || dot == 0 || path[dot-1] == '/' || strings.IndexByte(path[dot:], '/') != -1 {
+		return ""
+	}
+	for _, format := range formats {
+		if path[dot+1:] == format {
+			return format
+		}
+	}
+	return ""
+}
@@ -0,0 +1,49 @@
+package fastroute_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+func TestWithFormatSuffix(t *testing.T) {
+	t.Parallel()
+	handler := func(w http.ResponseWriter, req *http.Request) {}
+	router := fastroute.WithFormatSuffix(fastroute.Chain(
+		fastroute.New("/users/:id", handler),
+		fastroute.New("/status", handler),
+		fastroute.New("/robots.txt", handler),
+	), "json", "xml")
+
+	cases := map[string]string{
+		"/users/5.json":   "/users/:id format=json id=5",
+		"/users/5.xml":    "/users/:id format=xml id=5",
+		"/users/5":        "/users/:id id=5",
+		"/users/5.csv":    "/users/:id id=5.csv",
+		"/users/.json":    "/users/:id id=.json",
+		"/status.json":    "/status format=json",
+		"/status":         "/status",
+		"/status.txt":     "no match",
+		"/robots.txt":     "/robots.txt",
+		"/users/5.json/x": "no match",
+	}
+	for path, expected := range cases {
+		if actual := routed(router, path); actual != expected {
+			t.Fatalf("expected path: %s to be routed as %q, but got %q", path, expected, actual)
+		}
+	}
+
+	var format, path string
+	router = fastroute.WithFormatSuffix(fastroute.New("/users/:id", func(w http.ResponseWriter, req *http.Request) {
+		format, path = fastroute.Parameters(req).ByName("format"), req.URL.Path
+	}), "json")
+	req, _ := http.NewRequest("GET", "/users/5.json", nil)
+	router.ServeHTTP(nil, req)
+	if format != "json" || path != "/users/5.json" {
+		t.Fatalf("expected handler to be served format with path unchanged, but got: %q %q", format, path)
+	}
+	if fastroute.Parameters(req) != nil {
+		t.Fatal("expected parameters to be recycled")
+	}
+}
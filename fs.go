@@ -0,0 +1,169 @@
+//go:build go1.16
+// +build go1.16
+
+package fastroute
+
+import (
+	"fmt"
+	"io/fs"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+)
+
+// FSOption configures FromFS.
+type FSOption func(*fsRoutes)
+
+type fsRoutes struct {
+	extensions map[string]string
+	brackets   bool
+}
+
+// FSExtension maps file extension, like ".json", to the
+// suffix of route path, which is empty for all the files
+// by default, so "feed.json" is routed as "/feed", unless
+// it is mapped to ".json" itself.
+func FSExtension(ext, suffix string) FSOption {
+	return func(r *fsRoutes) {
+		r.extensions[ext] = suffix
+	}
+}
+
+// FSLiteralBrackets routes bracketed names as they are,
+// rather than as parameters.
+func FSLiteralBrackets() FSOption {
+	return func(r *fsRoutes) {
+		r.brackets = false
+	}
+}
+
+// FromFS creates Router, having a route for each file of
+// fsys, which mirrors the URL structure, like a directory
+// of markdown pages:
+//
+//	index.md              becomes   "/"
+//	blog/index.md         becomes   "/blog"
+//	blog/post.md          becomes   "/blog/post"
+//	users/[id]/posts.md   becomes   "/users/:id/posts"
+//	docs/[...path].md     becomes   "/docs/*path"
+//
+// File extensions are dropped, see FSExtension in order to
+// map them otherwise. Bracketed directory or file names are
+// parameters, and bracketed names with leading dots are
+// catch-all ones, unless FSLiteralBrackets is given. Hidden
+// files and directories, starting with a dot, are skipped.
+//
+// Matched requests are served by handler with the slash
+// separated path of the file in fsys. Routes are chained so,
+// that static segments go before parameters and catch-all
+// ones go last, the same way regardless of file names.
+//
+// The tree is walked once, so changes of a live directory
+// are not seen. FromFS may be called again and the new
+// router swapped in, like in a development server.
+//
+// It returns an error, if fsys cannot be walked, or two
+// files are routed by the same path.
+func FromFS(fsys fs.FS, handler func(path string, w http.ResponseWriter, req *http.Request), options ...FSOption) (Router, error) {
+	r := &fsRoutes{extensions: make(map[string]string), brackets: true}
+	for _, option := range options {
+		option(r)
+	}
+
+	files := make(map[string]string) // by pattern
+	var patterns []string
+	err := fs.WalkDir(fsys, ".", func(file string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if file != "." && strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+		pattern, err := r.pattern(file)
+		if err != nil {
+			return err
+		}
+		if other, found := files[pattern]; found {
+			return fmt.Errorf("files %q and %q are both routed as %q", other, file, pattern)
+		}
+		files[pattern] = file
+		patterns = append(patterns, pattern)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(patterns, func(i, j int) bool {
+		return fsOrder(patterns[i], patterns[j])
+	})
+	routes := make([]Router, len(patterns))
+	for i, pattern := range patterns {
+		file := files[pattern]
+		if routes[i], err = TryNew(pattern, func(w http.ResponseWriter, req *http.Request) {
+			handler(file, w, req)
+		}); err != nil {
+			return nil, fmt.Errorf("file %q cannot be routed: %v", file, err)
+		}
+	}
+	return Chain(routes...), nil
+}
+
+// converts file path to route pattern
+func (r *fsRoutes) pattern(file string) (string, error) {
+	ext := path.Ext(file)
+	name := strings.TrimSuffix(file, ext)
+	suffix := r.extensions[ext]
+
+	segments := strings.Split(name, "/")
+	if last := len(segments) - 1; segments[last] == "index" && suffix == "" {
+		segments = segments[:last]
+	}
+	last := len(segments) - 1
+	for i, seg := range segments {
+		bracketed := r.brackets && len(seg) > 2 && seg[0] == '[' && seg[len(seg)-1] == ']'
+		switch {
+		case !bracketed:
+			segments[i] = escapeSigns(seg)
+		case strings.HasPrefix(seg, "[..."):
+			if i != last {
+				return "", fmt.Errorf("catch-all %s must be the last segment of %q", seg, file)
+			}
+			segments[i] = "*" + seg[4:len(seg)-1]
+		default:
+			segments[i] = ":" + seg[1:len(seg)-1]
+		}
+	}
+	return "/" + strings.Join(segments, "/") + suffix, nil
+}
+
+// whether pattern a goes before b, static segments
+// first, parameters next and catch-all ones last
+func fsOrder(a, b string) bool {
+	rank := func(seg string) int {
+		switch {
+		case strings.HasPrefix(seg, ":"):
+			return 1
+		case strings.HasPrefix(seg, "*"):
+			return 2
+		}
+		return 0
+	}
+	as, bs := strings.Split(a, "/"), strings.Split(b, "/")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		if ra, rb := rank(as[i]), rank(bs[i]); ra != rb {
+			return ra < rb
+		}
+		if as[i] != bs[i] {
+			return as[i] < bs[i]
+		}
+	}
+	return len(as) < len(bs)
+}
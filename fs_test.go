@@ -0,0 +1,89 @@
+//go:build go1.16
+// +build go1.16
+
+package fastroute_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+func TestFromFS(t *testing.T) {
+	t.Parallel()
+
+	fsys := fstest.MapFS{
+		"index.md":             {},
+		"about.md":             {},
+		"blog/index.md":        {},
+		"blog/new.md":          {},
+		"blog/[slug].md":       {},
+		"users/[id]/posts.md":  {},
+		"docs/[...path].md":    {},
+		"docs/intro.md":        {},
+		"feed.json":            {},
+		".git/config":          {},
+		"blog/.draft.md":       {},
+		"notes/a:b.md":         {},
+		"static/[literal].txt": {},
+	}
+	serve := func(file string, w http.ResponseWriter, req *http.Request) {
+		fmt.Fprintf(w, "%s %s", file, fastroute.Parameters(req))
+	}
+	router, err := fastroute.FromFS(fsys, serve, fastroute.FSExtension(".json", ".json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := map[string]string{
+		"/":                   `index.md []`,
+		"/about":              `about.md []`,
+		"/blog":               `blog/index.md []`,
+		"/blog/new":           `blog/new.md []`,
+		"/blog/hello":         `blog/[slug].md [slug="hello"]`,
+		"/users/5/posts":      `users/[id]/posts.md [id="5"]`,
+		"/docs/intro":         `docs/intro.md []`,
+		"/docs/guide/install": `docs/[...path].md [path="/guide/install"]`,
+		"/feed.json":          `feed.json []`,
+		"/notes/a:b":          `notes/a:b.md []`,
+		"/static/:literal":    `static/[literal].txt [literal=":literal"]`,
+		"/feed":               "404 page not found\n",
+		"/blog/.draft":        `blog/[slug].md [slug=".draft"]`,
+		"/.git/config":        "404 page not found\n",
+	}
+	for path, expected := range cases {
+		req, _ := http.NewRequest("GET", path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if actual := w.Body.String(); actual != expected {
+			t.Fatalf("expected %s to be served as %q, but got %q", path, expected, actual)
+		}
+	}
+
+	patterns, complete := fastroute.Patterns(router)
+	if !complete || strings.Join(patterns, " ") != "/ /about /blog /blog/new /blog/:slug /docs/intro /docs/*path /feed.json /notes/a\\:b /static/:literal /users/:id/posts" {
+		t.Fatalf("unexpected patterns: %v %v", patterns, complete)
+	}
+
+	literal, err := fastroute.FromFS(fsys, serve, fastroute.FSLiteralBrackets())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual := routed(literal, "/blog/[slug]"); actual != "/blog/[slug]" {
+		t.Fatalf("expected brackets to be routed as they are, but got %q", actual)
+	}
+
+	conflicting := fstest.MapFS{"blog.md": {}, "blog/index.html": {}}
+	if _, err := fastroute.FromFS(conflicting, serve); err == nil || !strings.Contains(err.Error(), `routed as "/blog"`) {
+		t.Fatalf("expected conflicting files to fail, but got: %v", err)
+	}
+	catchAll := fstest.MapFS{"[...path]/page.md": {}}
+	if _, err := fastroute.FromFS(catchAll, serve); err == nil {
+		t.Fatal("expected catch-all directory to fail")
+	}
+}
@@ -0,0 +1,174 @@
+package fastroute
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"strings"
+)
+
+// GenerateMatcher generates Go source of package pkg,
+// declaring function name, which creates a router for
+// the given patterns. Each pattern is matched by code
+// specialized for it, so nothing is parsed at runtime,
+// and the first path segment is switched on, in order
+// to try only the patterns which may match.
+//
+// Generated function accepts handlers, one for each
+// pattern in the same order. The router behaves as the
+// Chain of routes created by New and implements Patterner,
+// but it cannot be changed without generating it again.
+//
+// Invalid patterns are reported as an error, with the
+// same message New would panic with. Patterns having a
+// query section are not supported, since the generated
+// code matches path only, they are reported as well.
+func GenerateMatcher(pkg, name string, patterns []string) ([]byte, error) {
+	if name == "" {
+		return nil, fmt.Errorf("name of generated function must be given")
+	}
+
+	firsts := make([]string, len(patterns)) // first segment of pattern
+	params := make([]bool, len(patterns))   // whether first segment is a param
+	var cases []string                      // distinct literal first segments in order
+	seen := map[string]bool{}
+	patterns = append([]string{}, patterns...)
+	for i, pattern := range patterns {
+		patterns[i] = "/" + strings.TrimLeft(pattern, "/")
+		if queryStart(patterns[i]) != -1 {
+			return nil, fmt.Errorf("query section is not supported by generated matcher: %s", patterns[i])
+		}
+		segments, err := parse(patterns[i])
+		if err != nil {
+			return nil, err
+		}
+		firsts[i] = literal(segments[0])[1:]
+		if params[i] = strings.IndexAny(segments[0], ":*") == 1; !params[i] && !seen[firsts[i]] {
+			seen[firsts[i]] = true
+			cases = append(cases, firsts[i])
+		}
+	}
+
+	prefix := strings.ToLower(name[:1]) + name[1:]
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, "// Code generated by fastroute.GenerateMatcher. DO NOT EDIT.\n\n")
+	fmt.Fprintf(buf, "package %s\n\n", pkg)
+	fmt.Fprintf(buf, "import (\n\"net/http\"\n\n\"github.com/DATA-DOG/fastroute\"\n)\n\n")
+
+	fmt.Fprintf(buf, "// %s creates router for patterns, handlers must be given in the same order:\n//\n", name)
+	for _, pattern := range patterns {
+		fmt.Fprintf(buf, "//\t%s\n", pattern)
+	}
+	fmt.Fprintf(buf, "func %s(handlers ...interface{}) fastroute.Router {\n", name)
+	fmt.Fprintf(buf, "if len(handlers) != %d {\n", len(patterns))
+	fmt.Fprintf(buf, "panic(\"expected %d handlers, one for each pattern\")\n}\n", len(patterns))
+	fmt.Fprintf(buf, "routes := [...]fastroute.Router{\n")
+	for i, pattern := range patterns {
+		fmt.Fprintf(buf, "fastroute.NewCompiled(%q, handlers[%d], %sMatch%d),\n", pattern, i, prefix, i)
+	}
+	fmt.Fprintf(buf, "}\n")
+	fmt.Fprintf(buf, "return %sRouter{func(req *http.Request) http.Handler {\n", prefix)
+	fmt.Fprintf(buf, "path := req.URL.Path\n")
+	fmt.Fprintf(buf, "if len(path) > 0 && path[0] == '/' {\npath = path[1:]\n}\n")
+	fmt.Fprintf(buf, "for i := 0; i < len(path); i++ {\nif path[i] == '/' {\npath = path[:i]\nbreak\n}\n}\n")
+	fmt.Fprintf(buf, "switch path {\n")
+	for _, c := range cases {
+		fmt.Fprintf(buf, "case %q:\n", c)
+		for i, first := range firsts {
+			if first == c || params[i] {
+				fmt.Fprintf(buf, "if h := routes[%d].Route(req); h != nil {\nreturn h\n}\n", i)
+			}
+		}
+	}
+	fmt.Fprintf(buf, "default:\n")
+	for i := range patterns {
+		if params[i] {
+			fmt.Fprintf(buf, "if h := routes[%d].Route(req); h != nil {\nreturn h\n}\n", i)
+		}
+	}
+	fmt.Fprintf(buf, "}\nreturn nil\n}}\n}\n\n")
+
+	fmt.Fprintf(buf, "type %sRouter struct {\nfastroute.RouterFunc\n}\n\n", prefix)
+	fmt.Fprintf(buf, "func (%sRouter) Patterns() []string {\nreturn []string{\n", prefix)
+	for _, pattern := range patterns {
+		fmt.Fprintf(buf, "%q,\n", pattern)
+	}
+	fmt.Fprintf(buf, "}\n}\n")
+
+	for i, pattern := range patterns {
+		fmt.Fprintf(buf, "\nfunc %sMatch%d(path string, ps *fastroute.Params) bool {\n", prefix, i)
+		generateMatch(buf, pattern)
+		fmt.Fprintf(buf, "}\n")
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated matcher: %s", err)
+	}
+	return src, nil
+}
+
+// generates matcher body, which mirrors match for
+// the given pattern segments
+func generateMatch(buf *bytes.Buffer, pattern string) {
+	if strings.IndexAny(pattern, ":*\\") == -1 {
+		fmt.Fprintf(buf, "return path == %q\n", pattern)
+		return
+	}
+
+	segments, _ := parse(pattern)
+	var static string
+	flush := func() {
+		if static == "" {
+			return
+		}
+		fmt.Fprintf(buf, "if len(path) < %d || path[:%d] != %q {\nreturn false\n}\n", len(static), len(static), static)
+		fmt.Fprintf(buf, "path = path[%d:]\n", len(static))
+		static = ""
+	}
+
+	var declared bool
+	for _, segment := range segments {
+		switch segment[1] {
+		case ':':
+			flush()
+			fmt.Fprintf(buf, "if len(path) < 2 || path[0] != '/' {\nreturn false\n}\n")
+			if !declared {
+				fmt.Fprintf(buf, "end := 1\n")
+				declared = true
+			} else {
+				fmt.Fprintf(buf, "end = 1\n")
+			}
+			fmt.Fprintf(buf, "for end < len(path) && path[end] != '/' {\nend++\n}\n")
+			if pos := strings.IndexByte(segment, '\\'); pos != -1 {
+				suffix := segment[pos+1:]
+				fmt.Fprintf(buf, "if end <= %d || path[end-%d:end] != %q {\nreturn false\n}\n", len(suffix)+1, len(suffix), suffix)
+				fmt.Fprintf(buf, "*ps = append(*ps, struct{ Key, Value string }{%q, path[1 : end-%d]})\n", segment[2:pos], len(suffix))
+			} else {
+				fmt.Fprintf(buf, "*ps = append(*ps, struct{ Key, Value string }{%q, path[1:end]})\n", segment[2:])
+			}
+			fmt.Fprintf(buf, "path = path[end:]\n")
+		case '*':
+			flush()
+			if _, tail := splitTail(segments, pattern[len(pattern)-1] == '/'); tail != "" {
+				fmt.Fprintf(buf, "if len(path) <= %d || path[len(path)-%d:] != %q {\nreturn false\n}\n", len(tail), len(tail), tail)
+				fmt.Fprintf(buf, "path = path[:len(path)-%d]\n", len(tail))
+			}
+			fmt.Fprintf(buf, "if len(path) == 0 || path[0] != '/' {\nreturn false\n}\n")
+			if segment != "/*" {
+				fmt.Fprintf(buf, "*ps = append(*ps, struct{ Key, Value string }{%q, path})\n", segment[2:])
+			}
+			fmt.Fprintf(buf, "return true\n")
+			return
+		default:
+			static += literal(segment)
+		}
+	}
+	flush()
+
+	if pattern[len(pattern)-1] == '/' {
+		fmt.Fprintf(buf, "return path == \"/\"\n")
+	} else {
+		fmt.Fprintf(buf, "return path == \"\"\n")
+	}
+}
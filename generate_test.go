@@ -0,0 +1,99 @@
+package fastroute_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+// patterns of generated_test.go, regenerated and
+// compared by TestGenerateMatcher
+var generatedPatterns = []string{
+	"/a/:b/c",
+	"/category/:cid/product/*rest",
+	"/users/:id/:bid/",
+	"/applications/:client_id/tokens",
+	"/repos/:owner/:repo/issues/:number/labels/:name",
+	"/files/*filepath",
+	"/hello/:name",
+	"/search/:query",
+	"/search/",
+	"/ünìcodé.html",
+	"/:lang/docs",
+	"/",
+	"/proxy/:host/*middle/status/",
+	`/v1/jobs/:id\:cancel`,
+	`/search/\*`,
+	"/assets/*",
+}
+
+func TestGenerateMatcher(t *testing.T) {
+	t.Parallel()
+
+	src, err := fastroute.GenerateMatcher("fastroute_test", "generatedRoutes", generatedPatterns)
+	if err != nil {
+		t.Fatal(err)
+	}
+	golden, err := ioutil.ReadFile("generated_test.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(src, golden) {
+		t.Fatalf("expected generated source to match generated_test.go, but got:\n%s", src)
+	}
+
+	if _, err := fastroute.GenerateMatcher("routes", "routes", []string{"/users/:"}); err == nil {
+		t.Fatal("expected an error for invalid pattern")
+	}
+	_, err = fastroute.GenerateMatcher("routes", "routes", []string{"/search?q=:query"})
+	if exp := "query section is not supported by generated matcher: /search?q=:query"; err == nil || err.Error() != exp {
+		t.Fatalf("expected error: %q, but got: %v", exp, err)
+	}
+}
+
+func TestGeneratedMatcherConformance(t *testing.T) {
+	t.Parallel()
+
+	handler := func(w http.ResponseWriter, req *http.Request) {}
+	handlers := make([]interface{}, len(generatedPatterns))
+	routes := make([]fastroute.Router, len(generatedPatterns))
+	for i, pattern := range generatedPatterns {
+		handlers[i], routes[i] = handler, fastroute.New(pattern, handler)
+	}
+	dynamic, generated := fastroute.Chain(routes...), generatedRoutes(handlers...)
+
+	if patterns, ok := fastroute.Patterns(generated); !ok || len(patterns) != len(generatedPatterns) {
+		t.Fatalf("expected generated router to be enumerable, but got: %v", patterns)
+	}
+
+	paths := []string{
+		"/", "", "//", "/a", "/a/", "/a//c", "/a/b/c", "/a/b/c/", "/a/c",
+		"/category/5/product/x/a/bc", "/category/5/product", "/category/5/product/", "/category//product/x",
+		"/users/a/b/", "/users/a/b", "/users/a/b/be/", "/users//b/",
+		"/applications/:client_id/tokens", "/applications/1/tokens/",
+		"/repos/o/r/issues/1/labels/bug", "/repos/o/r/issues/1/labels", "/repos/o/r/issues/1/labels/bug/",
+		"/files", "/files/", "/files/LICENSE", "/files/css/style.css", "/filesx/a",
+		"/hello/john", "/hellowe", "/hello/", "/hello/john/",
+		"/search", "/search/", "/search/someth!ng+in+ünìcodé", "/search/someth!ng+in+ünìcodé/",
+		"/ünìcodé.html", "/ünìcodé.htm",
+		"/en/docs", "/a/docs", "/search/docs", "//docs", "/en/docs/", "/en",
+		"/proxy/h/a/b/status/", "/proxy/h/status/status/", "/proxy/h/status/", "/proxy/h/a/status", "/proxy/h//status/",
+		"/v1/jobs/42:cancel", "/v1/jobs/:cancel", "/v1/jobs/42", "/v1/jobs/42:cancel/", "/search/*", "/search/**",
+		"/assets", "/assets/", "/assets/css/site.css",
+	}
+
+	for _, path := range paths {
+		expected, actual := routed(dynamic, path), routed(generated, path)
+		if expected != actual {
+			t.Fatalf("expected path: %q to be routed as %q, but generated router gave %q", path, expected, actual)
+		}
+	}
+}
+
+// describes how router matches the given path
+func routed(router fastroute.Router, path string) string {
+	return routedBy(router, "GET", path)
+}
@@ -0,0 +1,476 @@
+// Code generated by fastroute.GenerateMatcher. DO NOT EDIT.
+
+package fastroute_test
+
+import (
+	"net/http"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+// generatedRoutes creates router for patterns, handlers must be given in the same order:
+//
+//	/a/:b/c
+//	/category/:cid/product/*rest
+//	/users/:id/:bid/
+//	/applications/:client_id/tokens
+//	/repos/:owner/:repo/issues/:number/labels/:name
+//	/files/*filepath
+//	/hello/:name
+//	/search/:query
+//	/search/
+//	/ünìcodé.html
+//	/:lang/docs
+//	/
+//	/proxy/:host/*middle/status/
+//	/v1/jobs/:id\:cancel
+//	/search/\*
+//	/assets/*
+func generatedRoutes(handlers ...interface{}) fastroute.Router {
+	if len(handlers) != 16 {
+		panic("expected 16 handlers, one for each pattern")
+	}
+	routes := [...]fastroute.Router{
+		fastroute.NewCompiled("/a/:b/c", handlers[0], generatedRoutesMatch0),
+		fastroute.NewCompiled("/category/:cid/product/*rest", handlers[1], generatedRoutesMatch1),
+		fastroute.NewCompiled("/users/:id/:bid/", handlers[2], generatedRoutesMatch2),
+		fastroute.NewCompiled("/applications/:client_id/tokens", handlers[3], generatedRoutesMatch3),
+		fastroute.NewCompiled("/repos/:owner/:repo/issues/:number/labels/:name", handlers[4], generatedRoutesMatch4),
+		fastroute.NewCompiled("/files/*filepath", handlers[5], generatedRoutesMatch5),
+		fastroute.NewCompiled("/hello/:name", handlers[6], generatedRoutesMatch6),
+		fastroute.NewCompiled("/search/:query", handlers[7], generatedRoutesMatch7),
+		fastroute.NewCompiled("/search/", handlers[8], generatedRoutesMatch8),
+		fastroute.NewCompiled("/ünìcodé.html", handlers[9], generatedRoutesMatch9),
+		fastroute.NewCompiled("/:lang/docs", handlers[10], generatedRoutesMatch10),
+		fastroute.NewCompiled("/", handlers[11], generatedRoutesMatch11),
+		fastroute.NewCompiled("/proxy/:host/*middle/status/", handlers[12], generatedRoutesMatch12),
+		fastroute.NewCompiled("/v1/jobs/:id\\:cancel", handlers[13], generatedRoutesMatch13),
+		fastroute.NewCompiled("/search/\\*", handlers[14], generatedRoutesMatch14),
+		fastroute.NewCompiled("/assets/*", handlers[15], generatedRoutesMatch15),
+	}
+	return generatedRoutesRouter{func(req *http.Request) http.Handler {
+		path := req.URL.Path
+		if len(path) > 0 && path[0] == '/' {
+			path = path[1:]
+		}
+		for i := 0; i < len(path); i++ {
+			if path[i] == '/' {
+				path = path[:i]
+				break
+			}
+		}
+		switch path {
+		case "a":
+			if h := routes[0].Route(req); h != nil {
+				return h
+			}
+			if h := routes[10].Route(req); h != nil {
+				return h
+			}
+		case "category":
+			if h := routes[1].Route(req); h != nil {
+				return h
+			}
+			if h := routes[10].Route(req); h != nil {
+				return h
+			}
+		case "users":
+			if h := routes[2].Route(req); h != nil {
+				return h
+			}
+			if h := routes[10].Route(req); h != nil {
+				return h
+			}
+		case "applications":
+			if h := routes[3].Route(req); h != nil {
+				return h
+			}
+			if h := routes[10].Route(req); h != nil {
+				return h
+			}
+		case "repos":
+			if h := routes[4].Route(req); h != nil {
+				return h
+			}
+			if h := routes[10].Route(req); h != nil {
+				return h
+			}
+		case "files":
+			if h := routes[5].Route(req); h != nil {
+				return h
+			}
+			if h := routes[10].Route(req); h != nil {
+				return h
+			}
+		case "hello":
+			if h := routes[6].Route(req); h != nil {
+				return h
+			}
+			if h := routes[10].Route(req); h != nil {
+				return h
+			}
+		case "search":
+			if h := routes[7].Route(req); h != nil {
+				return h
+			}
+			if h := routes[8].Route(req); h != nil {
+				return h
+			}
+			if h := routes[10].Route(req); h != nil {
+				return h
+			}
+			if h := routes[14].Route(req); h != nil {
+				return h
+			}
+		case "ünìcodé.html":
+			if h := routes[9].Route(req); h != nil {
+				return h
+			}
+			if h := routes[10].Route(req); h != nil {
+				return h
+			}
+		case "":
+			if h := routes[10].Route(req); h != nil {
+				return h
+			}
+			if h := routes[11].Route(req); h != nil {
+				return h
+			}
+		case "proxy":
+			if h := routes[10].Route(req); h != nil {
+				return h
+			}
+			if h := routes[12].Route(req); h != nil {
+				return h
+			}
+		case "v1":
+			if h := routes[10].Route(req); h != nil {
+				return h
+			}
+			if h := routes[13].Route(req); h != nil {
+				return h
+			}
+		case "assets":
+			if h := routes[10].Route(req); h != nil {
+				return h
+			}
+			if h := routes[15].Route(req); h != nil {
+				return h
+			}
+		default:
+			if h := routes[10].Route(req); h != nil {
+				return h
+			}
+		}
+		return nil
+	}}
+}
+
+type generatedRoutesRouter struct {
+	fastroute.RouterFunc
+}
+
+func (generatedRoutesRouter) Patterns() []string {
+	return []string{
+		"/a/:b/c",
+		"/category/:cid/product/*rest",
+		"/users/:id/:bid/",
+		"/applications/:client_id/tokens",
+		"/repos/:owner/:repo/issues/:number/labels/:name",
+		"/files/*filepath",
+		"/hello/:name",
+		"/search/:query",
+		"/search/",
+		"/ünìcodé.html",
+		"/:lang/docs",
+		"/",
+		"/proxy/:host/*middle/status/",
+		"/v1/jobs/:id\\:cancel",
+		"/search/\\*",
+		"/assets/*",
+	}
+}
+
+func generatedRoutesMatch0(path string, ps *fastroute.Params) bool {
+	if len(path) < 2 || path[:2] != "/a" {
+		return false
+	}
+	path = path[2:]
+	if len(path) < 2 || path[0] != '/' {
+		return false
+	}
+	end := 1
+	for end < len(path) && path[end] != '/' {
+		end++
+	}
+	*ps = append(*ps, struct{ Key, Value string }{"b", path[1:end]})
+	path = path[end:]
+	if len(path) < 2 || path[:2] != "/c" {
+		return false
+	}
+	path = path[2:]
+	return path == ""
+}
+
+func generatedRoutesMatch1(path string, ps *fastroute.Params) bool {
+	if len(path) < 9 || path[:9] != "/category" {
+		return false
+	}
+	path = path[9:]
+	if len(path) < 2 || path[0] != '/' {
+		return false
+	}
+	end := 1
+	for end < len(path) && path[end] != '/' {
+		end++
+	}
+	*ps = append(*ps, struct{ Key, Value string }{"cid", path[1:end]})
+	path = path[end:]
+	if len(path) < 8 || path[:8] != "/product" {
+		return false
+	}
+	path = path[8:]
+	if len(path) == 0 || path[0] != '/' {
+		return false
+	}
+	*ps = append(*ps, struct{ Key, Value string }{"rest", path})
+	return true
+}
+
+func generatedRoutesMatch2(path string, ps *fastroute.Params) bool {
+	if len(path) < 6 || path[:6] != "/users" {
+		return false
+	}
+	path = path[6:]
+	if len(path) < 2 || path[0] != '/' {
+		return false
+	}
+	end := 1
+	for end < len(path) && path[end] != '/' {
+		end++
+	}
+	*ps = append(*ps, struct{ Key, Value string }{"id", path[1:end]})
+	path = path[end:]
+	if len(path) < 2 || path[0] != '/' {
+		return false
+	}
+	end = 1
+	for end < len(path) && path[end] != '/' {
+		end++
+	}
+	*ps = append(*ps, struct{ Key, Value string }{"bid", path[1:end]})
+	path = path[end:]
+	return path == "/"
+}
+
+func generatedRoutesMatch3(path string, ps *fastroute.Params) bool {
+	if len(path) < 13 || path[:13] != "/applications" {
+		return false
+	}
+	path = path[13:]
+	if len(path) < 2 || path[0] != '/' {
+		return false
+	}
+	end := 1
+	for end < len(path) && path[end] != '/' {
+		end++
+	}
+	*ps = append(*ps, struct{ Key, Value string }{"client_id", path[1:end]})
+	path = path[end:]
+	if len(path) < 7 || path[:7] != "/tokens" {
+		return false
+	}
+	path = path[7:]
+	return path == ""
+}
+
+func generatedRoutesMatch4(path string, ps *fastroute.Params) bool {
+	if len(path) < 6 || path[:6] != "/repos" {
+		return false
+	}
+	path = path[6:]
+	if len(path) < 2 || path[0] != '/' {
+		return false
+	}
+	end := 1
+	for end < len(path) && path[end] != '/' {
+		end++
+	}
+	*ps = append(*ps, struct{ Key, Value string }{"owner", path[1:end]})
+	path = path[end:]
+	if len(path) < 2 || path[0] != '/' {
+		return false
+	}
+	end = 1
+	for end < len(path) && path[end] != '/' {
+		end++
+	}
+	*ps = append(*ps, struct{ Key, Value string }{"repo", path[1:end]})
+	path = path[end:]
+	if len(path) < 7 || path[:7] != "/issues" {
+		return false
+	}
+	path = path[7:]
+	if len(path) < 2 || path[0] != '/' {
+		return false
+	}
+	end = 1
+	for end < len(path) && path[end] != '/' {
+		end++
+	}
+	*ps = append(*ps, struct{ Key, Value string }{"number", path[1:end]})
+	path = path[end:]
+	if len(path) < 7 || path[:7] != "/labels" {
+		return false
+	}
+	path = path[7:]
+	if len(path) < 2 || path[0] != '/' {
+		return false
+	}
+	end = 1
+	for end < len(path) && path[end] != '/' {
+		end++
+	}
+	*ps = append(*ps, struct{ Key, Value string }{"name", path[1:end]})
+	path = path[end:]
+	return path == ""
+}
+
+func generatedRoutesMatch5(path string, ps *fastroute.Params) bool {
+	if len(path) < 6 || path[:6] != "/files" {
+		return false
+	}
+	path = path[6:]
+	if len(path) == 0 || path[0] != '/' {
+		return false
+	}
+	*ps = append(*ps, struct{ Key, Value string }{"filepath", path})
+	return true
+}
+
+func generatedRoutesMatch6(path string, ps *fastroute.Params) bool {
+	if len(path) < 6 || path[:6] != "/hello" {
+		return false
+	}
+	path = path[6:]
+	if len(path) < 2 || path[0] != '/' {
+		return false
+	}
+	end := 1
+	for end < len(path) && path[end] != '/' {
+		end++
+	}
+	*ps = append(*ps, struct{ Key, Value string }{"name", path[1:end]})
+	path = path[end:]
+	return path == ""
+}
+
+func generatedRoutesMatch7(path string, ps *fastroute.Params) bool {
+	if len(path) < 7 || path[:7] != "/search" {
+		return false
+	}
+	path = path[7:]
+	if len(path) < 2 || path[0] != '/' {
+		return false
+	}
+	end := 1
+	for end < len(path) && path[end] != '/' {
+		end++
+	}
+	*ps = append(*ps, struct{ Key, Value string }{"query", path[1:end]})
+	path = path[end:]
+	return path == ""
+}
+
+func generatedRoutesMatch8(path string, ps *fastroute.Params) bool {
+	return path == "/search/"
+}
+
+func generatedRoutesMatch9(path string, ps *fastroute.Params) bool {
+	return path == "/ünìcodé.html"
+}
+
+func generatedRoutesMatch10(path string, ps *fastroute.Params) bool {
+	if len(path) < 2 || path[0] != '/' {
+		return false
+	}
+	end := 1
+	for end < len(path) && path[end] != '/' {
+		end++
+	}
+	*ps = append(*ps, struct{ Key, Value string }{"lang", path[1:end]})
+	path = path[end:]
+	if len(path) < 5 || path[:5] != "/docs" {
+		return false
+	}
+	path = path[5:]
+	return path == ""
+}
+
+func generatedRoutesMatch11(path string, ps *fastroute.Params) bool {
+	return path == "/"
+}
+
+func generatedRoutesMatch12(path string, ps *fastroute.Params) bool {
+	if len(path) < 6 || path[:6] != "/proxy" {
+		return false
+	}
+	path = path[6:]
+	if len(path) < 2 || path[0] != '/' {
+		return false
+	}
+	end := 1
+	for end < len(path) && path[end] != '/' {
+		end++
+	}
+	*ps = append(*ps, struct{ Key, Value string }{"host", path[1:end]})
+	path = path[end:]
+	if len(path) <= 8 || path[len(path)-8:] != "/status/" {
+		return false
+	}
+	path = path[:len(path)-8]
+	if len(path) == 0 || path[0] != '/' {
+		return false
+	}
+	*ps = append(*ps, struct{ Key, Value string }{"middle", path})
+	return true
+}
+
+func generatedRoutesMatch13(path string, ps *fastroute.Params) bool {
+	if len(path) < 8 || path[:8] != "/v1/jobs" {
+		return false
+	}
+	path = path[8:]
+	if len(path) < 2 || path[0] != '/' {
+		return false
+	}
+	end := 1
+	for end < len(path) && path[end] != '/' {
+		end++
+	}
+	if end <= 8 || path[end-7:end] != ":cancel" {
+		return false
+	}
+	*ps = append(*ps, struct{ Key, Value string }{"id", path[1 : end-7]})
+	path = path[end:]
+	return path == ""
+}
+
+func generatedRoutesMatch14(path string, ps *fastroute.Params) bool {
+	if len(path) < 9 || path[:9] != "/search/*" {
+		return false
+	}
+	path = path[9:]
+	return path == ""
+}
+
+func generatedRoutesMatch15(path string, ps *fastroute.Params) bool {
+	if len(path) < 7 || path[:7] != "/assets" {
+		return false
+	}
+	path = path[7:]
+	if len(path) == 0 || path[0] != '/' {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,75 @@
+package fastroute
+
+import (
+	"errors"
+	"net/http"
+)
+
+var (
+	// ErrUnauthorized may be returned by Guard check, in
+	// order to reject request with 401 Unauthorized.
+	ErrUnauthorized = errors.New("unauthorized")
+
+	// ErrForbidden may be returned by Guard check, in
+	// order to reject request with 403 Forbidden.
+	ErrForbidden = errors.New("forbidden")
+)
+
+// GuardOption configures Guard.
+type GuardOption func(*guard)
+
+type guard struct {
+	respond func(http.ResponseWriter, *http.Request, error)
+}
+
+// GuardResponder sets the function, responding to
+// requests rejected by Guard check with the error.
+func GuardResponder(respond func(w http.ResponseWriter, req *http.Request, err error)) GuardOption {
+	return func(g *guard) {
+		g.respond = respond
+	}
+}
+
+// Guard wraps router in order to check whether the
+// matched request is allowed to be served.
+//
+// The check runs after router matches the request, so
+// it may use Parameters(req) for object level checks,
+// like whether :id is owned by the user. If it returns
+// nil, the matched handler is served. Otherwise, the
+// route is claimed and the request is rejected with
+// 401 Unauthorized if the error is ErrUnauthorized,
+// or 403 Forbidden for any other error, unless the
+// GuardResponder option is given.
+//
+// Parameters of rejected requests are recycled before
+// the responder is served.
+func Guard(router Router, check func(*http.Request) error, options ...GuardOption) Router {
+	g := &guard{respond: rejectGuarded}
+	for _, option := range options {
+		option(g)
+	}
+
+	return wrapper{RouterFunc(func(req *http.Request) http.Handler {
+		h := router.Route(req)
+		if h == nil {
+			return nil
+		}
+		err := check(req)
+		if err == nil {
+			return h
+		}
+		Recycle(req)
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			g.respond(w, req, err)
+		})
+	}), router}
+}
+
+func rejectGuarded(w http.ResponseWriter, req *http.Request, err error) {
+	if err == ErrUnauthorized {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+	} else {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+	}
+}
@@ -0,0 +1,65 @@
+package fastroute_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+func TestGuard(t *testing.T) {
+	t.Parallel()
+
+	owned := func(req *http.Request) error {
+		switch {
+		case req.Header.Get("User") == "":
+			return fastroute.ErrUnauthorized
+		case req.Header.Get("User") != fastroute.Parameters(req).ByName("id"):
+			return fastroute.ErrForbidden
+		}
+		return nil
+	}
+
+	handler := func(w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte("owner " + fastroute.Parameters(req).ByName("id")))
+	}
+
+	guarded := fastroute.Guard(fastroute.New("/users/:id", handler), owned)
+	custom := fastroute.Guard(fastroute.New("/orders/:id", handler), func(req *http.Request) error {
+		return errors.New("teapot")
+	}, fastroute.GuardResponder(func(w http.ResponseWriter, req *http.Request, err error) {
+		http.Error(w, err.Error(), http.StatusTeapot)
+	}))
+	router := fastroute.Chain(guarded, custom)
+
+	cases := []struct {
+		path, user string
+		code       int
+		body       string
+	}{
+		{"/users/5", "5", 200, "owner 5"},
+		{"/users/5", "", 401, "Unauthorized\n"},
+		{"/users/5", "6", 403, "Forbidden\n"},
+		{"/orders/5", "5", 418, "teapot\n"},
+		{"/other", "5", 404, "404 page not found\n"},
+	}
+
+	for i, c := range cases {
+		req, err := http.NewRequest("GET", c.path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("User", c.user)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != c.code || w.Body.String() != c.body {
+			t.Fatalf("expected response: %d %q, but got: %d %q, case: %d", c.code, c.body, w.Code, w.Body.String(), i)
+		}
+		if fastroute.Parameters(req) != nil {
+			t.Fatalf("expected parameters to be recycled, case: %d", i)
+		}
+	}
+}
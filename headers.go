@@ -0,0 +1,128 @@
+package fastroute
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+)
+
+// SetHeaders wraps router in order to set response headers
+// of the matched requests, before the handler is served,
+// declared along with routes:
+//
+//	fastroute.Chain(
+//		fastroute.SetHeaders(assets, http.Header{"Cache-Control": {"public, max-age=31536000"}}),
+//		fastroute.SetHeaders(pages, http.Header{"X-Frame-Options": {"DENY"}}),
+//	)
+//
+// Handler may replace or delete any of the headers, since
+// they are set before it writes the response. See
+// OverrideHeaders in order to enforce them. Header keys
+// are canonicalized, and h is copied, so it may be changed
+// afterwards, without affecting the routes.
+func SetHeaders(router Router, h http.Header) Router {
+	h = canonicalHeader(h)
+	return wrapper{RouterFunc(func(req *http.Request) http.Handler {
+		next := router.Route(req)
+		if next == nil {
+			return nil
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			setHeader(w.Header(), h)
+			next.ServeHTTP(w, req)
+		})
+	}), router}
+}
+
+// OverrideHeaders wraps router the same way SetHeaders
+// does, but sets headers when the response is written,
+// replacing the values of the same headers, set by the
+// handler, for example Strict-Transport-Security site-wide.
+//
+// The response writer passed to handler implements
+// http.Flusher, http.Hijacker and io.ReaderFrom, the way
+// the one of Measure does.
+func OverrideHeaders(router Router, h http.Header) Router {
+	h = canonicalHeader(h)
+	return wrapper{RouterFunc(func(req *http.Request) http.Handler {
+		next := router.Route(req)
+		if next == nil {
+			return nil
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			hw := &headerWriter{ResponseWriter: w, header: h}
+			next.ServeHTTP(hw, req)
+			hw.override() // if nothing was written
+		})
+	}), router}
+}
+
+// copies h, having its keys canonicalized
+func canonicalHeader(h http.Header) http.Header {
+	canonical := make(http.Header, len(h))
+	for key, values := range h {
+		canonical[http.CanonicalHeaderKey(key)] = append([]string(nil), values...)
+	}
+	return canonical
+}
+
+// sets copies of values of h to header, so that handlers
+// changing them in place do not change the next responses
+func setHeader(header, h http.Header) {
+	for key, values := range h {
+		header[key] = append([]string(nil), values...)
+	}
+}
+
+type headerWriter struct {
+	http.ResponseWriter
+	header  http.Header
+	written bool
+}
+
+func (w *headerWriter) override() {
+	if w.written {
+		return
+	}
+	w.written = true
+	setHeader(w.ResponseWriter.Header(), w.header)
+}
+
+func (w *headerWriter) WriteHeader(code int) {
+	w.override()
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *headerWriter) Write(b []byte) (int, error) {
+	w.override()
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *headerWriter) ReadFrom(r io.Reader) (int64, error) {
+	rf, ok := w.ResponseWriter.(io.ReaderFrom)
+	if !ok {
+		return io.Copy(struct{ io.Writer }{w}, r) // hides ReadFrom
+	}
+	w.override()
+	return rf.ReadFrom(r)
+}
+
+func (w *headerWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		w.override()
+		f.Flush()
+	}
+}
+
+func (w *headerWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if h, ok := w.ResponseWriter.(http.Hijacker); ok {
+		return h.Hijack()
+	}
+	return nil, nil, errors.New("fastroute: response writer does not support hijacking")
+}
+
+func (w *headerWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
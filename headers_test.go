@@ -0,0 +1,100 @@
+package fastroute_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+func TestSetHeaders(t *testing.T) {
+	t.Parallel()
+
+	headers := http.Header{"cache-control": {"public"}, "X-Frame-Options": {"DENY"}}
+	router := fastroute.SetHeaders(fastroute.Chain(
+		fastroute.New("/assets/*file", func(w http.ResponseWriter, req *http.Request) {}),
+		fastroute.New("/private", func(w http.ResponseWriter, req *http.Request) {
+			w.Header().Set("Cache-Control", "no-store")
+			w.Header().Add("X-Frame-Options", "SAMEORIGIN")
+		}),
+		fastroute.New("/inplace", func(w http.ResponseWriter, req *http.Request) {
+			w.Header()["Cache-Control"][0] = "private"
+		}),
+		fastroute.New("/early", func(w http.ResponseWriter, req *http.Request) {
+			w.WriteHeader(http.StatusAccepted)
+			w.Header().Set("Cache-Control", "no-store") // too late
+		}),
+	), headers)
+	headers.Set("X-Frame-Options", "changed")
+
+	cases := []struct {
+		path, cacheControl string
+		frameOptions       []string
+	}{
+		{"/assets/app.js", "public", []string{"DENY"}},
+		{"/private", "no-store", []string{"DENY", "SAMEORIGIN"}},
+		{"/inplace", "private", []string{"DENY"}},
+		{"/early", "public", []string{"DENY"}},
+		{"/other", "", nil},
+	}
+	for _, c := range cases {
+		req, _ := http.NewRequest("GET", c.path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		res := w.Result()
+		if cc := res.Header.Get("Cache-Control"); cc != c.cacheControl {
+			t.Fatalf("expected Cache-Control of %s to be %q, but got %q", c.path, c.cacheControl, cc)
+		}
+		if fo := res.Header["X-Frame-Options"]; len(fo) != len(c.frameOptions) || (len(fo) > 0 && fo[len(fo)-1] != c.frameOptions[len(fo)-1]) {
+			t.Fatalf("expected X-Frame-Options of %s to be %q, but got %q", c.path, c.frameOptions, fo)
+		}
+	}
+
+	// appended values must not leak to other requests
+	req, _ := http.NewRequest("GET", "/assets/app.js", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if fo := w.Result().Header["X-Frame-Options"]; len(fo) != 1 {
+		t.Fatalf("expected shared header values not to be changed, but got %q", fo)
+	}
+	if cc := w.Result().Header.Get("Cache-Control"); cc != "public" {
+		t.Fatalf("expected shared header values not to be changed in place, but got %q", cc)
+	}
+}
+
+func TestOverrideHeaders(t *testing.T) {
+	t.Parallel()
+
+	var status int
+	router := fastroute.Measure(fastroute.OverrideHeaders(fastroute.Chain(
+		fastroute.New("/set", func(w http.ResponseWriter, req *http.Request) {
+			w.Header().Set("Strict-Transport-Security", "max-age=0")
+			w.Write([]byte("body"))
+		}),
+		fastroute.New("/early", func(w http.ResponseWriter, req *http.Request) {
+			w.Header().Set("Strict-Transport-Security", "max-age=0")
+			w.WriteHeader(http.StatusCreated)
+		}),
+		fastroute.New("/flush", func(w http.ResponseWriter, req *http.Request) {
+			w.(http.Flusher).Flush()
+		}),
+		fastroute.New("/empty", func(w http.ResponseWriter, req *http.Request) {}),
+	), http.Header{"Strict-Transport-Security": {"max-age=63072000"}}),
+		func(pattern string, code, bytes int, d time.Duration) {
+			status = code
+		})
+
+	for path, code := range map[string]int{"/set": 200, "/early": 201, "/flush": 200, "/empty": 200} {
+		req, _ := http.NewRequest("GET", path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if sts := w.Result().Header.Get("Strict-Transport-Security"); sts != "max-age=63072000" {
+			t.Fatalf("expected header of %s to be overridden, but got %q", path, sts)
+		}
+		if w.Code != code || status != code {
+			t.Fatalf("expected status of %s to be %d, but got %d, measured %d", path, code, w.Code, status)
+		}
+	}
+}
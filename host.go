@@ -0,0 +1,179 @@
+package fastroute
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Host scopes router to requests for the given host.
+//
+// Host is compared case insensitively. Port is only
+// compared if pattern has one, for example:
+//
+//	example.com          matches example.com and example.com:8080
+//	example.com:8080     matches example.com:8080 only
+//
+// A host label may end with a named parameter, which
+// matches the rest of the label:
+//
+//	Pattern: tenant-:id.example.com
+//
+//	Hosts:
+//	 tenant-42.example.com               match: id="42"
+//	 tenant-.example.com                 no match
+//	 tenant-42.eu.example.com            no match
+//
+// Host parameters are bound to the request together
+// with path parameters of the routes matched by router,
+// host parameters coming first.
+func Host(pattern string, router Router) Router {
+	name, port := splitHostPort(strings.ToLower(pattern))
+
+	var labels []hostLabel
+	var num int
+	for _, label := range strings.Split(name, ".") {
+		pos := strings.IndexByte(label, ':')
+		switch {
+		case pos == -1:
+			labels = append(labels, hostLabel{prefix: label})
+			continue
+		case pos == len(label)-1:
+			panic("param must be named after sign: " + pattern)
+		case strings.IndexAny(label[pos+1:], ":*") != -1:
+			panic("only one param per host label: " + pattern)
+		}
+		labels = append(labels, hostLabel{prefix: label[:pos], param: label[pos+1:]})
+		num++
+	}
+
+	matchPort := func(req *http.Request) (string, bool) {
+		host, p := splitHostPort(req.Host)
+		return host, port == "" || port == p
+	}
+
+	// maybe static host
+	if num == 0 {
+		return hosted{wrapper{RouterFunc(func(req *http.Request) http.Handler {
+			if host, ok := matchPort(req); ok && strings.EqualFold(host, name) {
+				return router.Route(req)
+			}
+			return nil
+		}), router}, pattern, nil}
+	}
+
+	// pool for host and path parameters
+	num += maxParams(router)
+	pool := newParamsPool(num)
+
+	return hosted{wrapper{RouterFunc(func(req *http.Request) http.Handler {
+		host, ok := matchPort(req)
+		if !ok {
+			return nil
+		}
+
+		ps := boundParams(req)
+		bound := ps != nil
+		if !bound {
+			ps = pool.get()
+		}
+		n := len(ps.params)
+		if !matchHost(labels, host, &ps.params) {
+			ps.params = ps.params[:n]
+			if !bound {
+				pool.Put((*ParamSet)(ps))
+			}
+			return nil
+		}
+
+		if bound {
+			if h := router.Route(req); h != nil {
+				return h // outer router recycles parameters
+			}
+			ps.params = ps.params[:n]
+			return nil
+		}
+
+		ps.pattern = req.URL.Path
+		ps.ReadCloser = req.Body
+		req.Body = ps
+		ps.binding, ps.kept = true, len(ps.params)
+		h := router.Route(req)
+		ps.binding = false
+		if h != nil {
+			return ps.releasing(req, h)
+		}
+		ps.reset(req)
+		return nil
+	}), router}, pattern, labels}
+}
+
+type hostLabel struct {
+	prefix, param string
+}
+
+// matches host labels and pushes named parameters to ps
+func matchHost(labels []hostLabel, host string, ps *Params) bool {
+	for i, label := range labels {
+		end := strings.IndexByte(host, '.')
+		if end == -1 {
+			if i+1 != len(labels) {
+				return false
+			}
+			end = len(host)
+		}
+
+		switch {
+		case label.param == "":
+			if !strings.EqualFold(host[:end], label.prefix) {
+				return false
+			}
+		case end <= len(label.prefix) || !strings.EqualFold(host[:len(label.prefix)], label.prefix):
+			return false
+		default:
+			ps.Append(label.param, host[len(label.prefix):end])
+		}
+
+		if end == len(host) {
+			host = ""
+		} else {
+			host = host[end+1:]
+		}
+	}
+	return host == ""
+}
+
+// splits host and port, if any, without validation
+func splitHostPort(hostport string) (string, string) {
+	pos := strings.LastIndex(hostport, ":")
+	if pos == -1 || strings.IndexByte(hostport[pos:], ']') != -1 {
+		return hostport, ""
+	}
+	for _, c := range hostport[pos+1:] {
+		if c < '0' || c > '9' {
+			return hostport, "" // a parameter, not a port
+		}
+	}
+	return hostport[:pos], hostport[pos+1:]
+}
+
+type hosted struct {
+	wrapper
+	pattern string
+	labels  []hostLabel
+}
+
+func (h hosted) inspect() ([]RouteInfo, bool) {
+	routes, ok := h.wrapper.inspect()
+	var params []string
+	for _, label := range h.labels {
+		if label.param != "" {
+			params = append(params, label.param)
+		}
+	}
+	for i := range routes {
+		routes[i].Host = h.pattern
+		routes[i].Params = append(params[:len(params):len(params)], routes[i].Params...)
+		routes[i].Static = routes[i].Static && len(params) == 0
+	}
+	return routes, ok
+}
@@ -0,0 +1,210 @@
+package fastroute_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+func TestHost(t *testing.T) {
+	t.Parallel()
+
+	handler := func(w http.ResponseWriter, req *http.Request) {
+		fmt.Fprintf(w, "%s %v", fastroute.Pattern(req), fastroute.Parameters(req))
+	}
+
+	router := fastroute.Chain(
+		fastroute.Host("tenant-:id.example.com", fastroute.Chain(
+			fastroute.New("/", handler),
+			fastroute.New("/users/:user", handler),
+		)),
+		fastroute.Host("Admin.Example.com:8080", fastroute.New("/users/:user", handler)),
+	)
+
+	cases := []struct {
+		host, path string
+		code       int
+		body       string
+	}{
+		{"tenant-42.example.com", "/", 200, `/ [id="42"]`},
+		{"tenant-42.example.com:80", "/users/john", 200, `/users/:user [id="42" user="john"]`},
+		{"TENANT-7.example.COM", "/users/john", 200, `/users/:user [id="7" user="john"]`},
+		{"tenant-42.example.com", "/users", 404, ""},
+		{"tenant-.example.com", "/", 404, ""},
+		{"tenant-42.eu.example.com", "/", 404, ""},
+		{"tenant-42.example", "/", 404, ""},
+		{"acme.example.com", "/", 404, ""},
+		{"admin.example.com:8080", "/users/john", 200, `/users/:user [user="john"]`},
+		{"admin.example.com", "/users/john", 404, ""},
+		{"admin.example.com:8081", "/users/john", 404, ""},
+	}
+
+	for i, c := range cases {
+		req, err := http.NewRequest("GET", c.path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Host = c.host
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != c.code {
+			t.Fatalf("expected response code: %d, but got: %d, case: %d", c.code, w.Code, i)
+		}
+		if c.code == 200 && w.Body.String() != c.body {
+			t.Fatalf("expected response body: %s, but got: %s, case: %d", c.body, w.Body.String(), i)
+		}
+		if params := fastroute.Parameters(req); params != nil {
+			t.Fatalf("expected parameters to be recycled, case: %d", i)
+		}
+	}
+}
+
+func TestHostInspect(t *testing.T) {
+	t.Parallel()
+
+	routes := fastroute.Inspect(fastroute.Host("tenant-:id.example.com", fastroute.Chain(
+		fastroute.New("/", http.NotFoundHandler()),
+		fastroute.New("/users/:user", http.NotFoundHandler()),
+	)))
+
+	if len(routes) != 2 {
+		t.Fatalf("expected two routes, but got: %+v", routes)
+	}
+	if routes[0].Host != "tenant-:id.example.com" || routes[0].Static || len(routes[0].Params) != 1 {
+		t.Fatalf("unexpected route: %+v", routes[0])
+	}
+	if fmt.Sprint(routes[1].Params) != "[id user]" {
+		t.Fatalf("expected host params first, but got: %v", routes[1].Params)
+	}
+}
+
+func TestHostPatternValidation(t *testing.T) {
+	t.Parallel()
+
+	for pattern, expected := range map[string]string{
+		"tenant-:.example.com":    "param must be named after sign: tenant-:.example.com",
+		"a:b:c.example.com":       "only one param per host label: a:b:c.example.com",
+		"tenant-:id*.example.com": "only one param per host label: tenant-:id*.example.com",
+	} {
+		func() {
+			defer func() {
+				if err := recover(); fmt.Sprint(err) != expected {
+					t.Fatalf(`expected panic: "%s", but got: "%v"`, expected, err)
+				}
+			}()
+			fastroute.Host(pattern, fastroute.New("/", http.NotFoundHandler()))
+		}()
+	}
+}
+
+func TestHostRecycledByInnerRoute(t *testing.T) {
+	t.Parallel()
+
+	handler := func(w http.ResponseWriter, req *http.Request) {
+		fmt.Fprint(w, fastroute.Parameters(req))
+	}
+	hidden, beta := fastroute.Toggle(fastroute.New("/users/:uid", handler))
+	hidden.SetHandler(nil)
+	hidden.Disable()
+	closed, maintenance := fastroute.Toggle(fastroute.New("/orders/:oid", handler))
+	closed.SetHandler(http.HandlerFunc(handler))
+	closed.Disable()
+
+	router := fastroute.Host("t-:id.example.com", fastroute.Chain(
+		beta, // denied match recycles only what it bound
+		maintenance,
+		fastroute.New("/users/:uid", handler),
+	))
+
+	cases := []struct{ url, body string }{
+		{"http://t-1.example.com/users/1", `[id="1" uid="1"]`},
+		{"http://t-2.example.com/orders/2", `[id="2"]`},
+		{"http://t-3.example.com/users/3", `[id="3" uid="3"]`},
+		{"http://t-4.example.com/users/4", `[id="4" uid="4"]`},
+	}
+	for _, c := range cases {
+		req, _ := http.NewRequest("GET", c.url, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Body.String() != c.body {
+			t.Fatalf("expected parameters: %s for: %s, but got: %s", c.body, c.url, w.Body.String())
+		}
+	}
+}
+
+func TestHandlerServedTwice(t *testing.T) {
+	t.Parallel()
+
+	// served twice, parameters of the first request may be reused
+	// by the second one by now, so it panics in debug builds only
+	servedTwice := func(h http.Handler, req *http.Request) {
+		defer func() {
+			if err := recover(); (err != nil) != poisoning {
+				t.Fatalf("expected handler served twice to panic only in debug builds, but got: %v", err)
+			}
+		}()
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		if w.Code != http.StatusInternalServerError {
+			t.Fatalf("expected handler served twice to respond server error, but got: %d", w.Code)
+		}
+	}
+
+	for name, router := range map[string]fastroute.Router{
+		"host": fastroute.Host("tenant-:tenant.example.com", fastroute.New("/users/:id", func(w http.ResponseWriter, req *http.Request) {
+			params := fastroute.Parameters(req)
+			w.Write([]byte(params.ByName("tenant") + " " + params.ByName("id")))
+		})),
+		"route": fastroute.New("/users/:id", func(w http.ResponseWriter, req *http.Request) {
+			w.Write([]byte(fastroute.Pattern(req) + " " + fastroute.Parameters(req).ByName("id")))
+		}),
+	} {
+		first, _ := http.NewRequest("GET", "http://tenant-a.example.com/users/1", nil)
+		h := router.Route(first)
+		h.ServeHTTP(httptest.NewRecorder(), first)
+
+		second, _ := http.NewRequest("GET", "http://tenant-b.example.com/users/2", nil)
+		next := router.Route(second)
+		servedTwice(h, first)
+
+		w := httptest.NewRecorder()
+		next.ServeHTTP(w, second)
+		if w.Body.String() != "b 2" && w.Body.String() != "/users/:id 2" {
+			t.Fatalf("expected parameters of the second request to be intact for %s, but got: %q", name, w.Body.String())
+		}
+	}
+}
+
+func TestHandlerServedWithReplacedBody(t *testing.T) {
+	t.Parallel()
+
+	router := fastroute.Host("tenant-:tenant.example.com", fastroute.New("/users/:id", func(w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte("served"))
+	}))
+
+	replace := []func(*http.Request) *http.Request{
+		func(req *http.Request) *http.Request {
+			req.Body = http.MaxBytesReader(nil, req.Body, 1<<10)
+			return req
+		},
+		func(req *http.Request) *http.Request {
+			r := *req
+			r.Body = http.MaxBytesReader(nil, r.Body, 1<<10)
+			return &r
+		},
+	}
+	for i, middleware := range replace {
+		req, _ := http.NewRequest("POST", "http://tenant-a.example.com/users/1", strings.NewReader("{}"))
+		h := router.Route(req)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, middleware(req))
+		if w.Code != http.StatusOK || w.Body.String() != "served" {
+			t.Fatalf("expected handler to be served with replaced body, but got: %d, case: %d", w.Code, i)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package fastroute
+
+import (
+	"net"
+	"net/http"
+	"strings"
+)
+
+// HTTPSOption configures ForceHTTPS.
+type HTTPSOption func(*httpsRedirect)
+
+type httpsRedirect struct {
+	exempt  []Router
+	schemes []string
+}
+
+// HTTPSExempt lets requests matching any of route patterns
+// be served over plain HTTP, like ACME HTTP-01 challenges:
+//
+//	fastroute.HTTPSExempt("/.well-known/acme-challenge/:token")
+//
+// It panics if any pattern is invalid.
+func HTTPSExempt(patterns ...string) HTTPSOption {
+	return func(r *httpsRedirect) {
+		for _, pattern := range patterns {
+			r.exempt = append(r.exempt, NewNoParams(pattern, http.NotFoundHandler()))
+		}
+	}
+}
+
+// HTTPSSecureSchemes sets values of X-Forwarded-Proto header,
+// which tell the request reached the proxy securely, it is
+// "https" by default. If none are given, the header is not
+// trusted at all.
+func HTTPSSecureSchemes(schemes ...string) HTTPSOption {
+	return func(r *httpsRedirect) {
+		r.schemes = schemes
+	}
+}
+
+// ForceHTTPS wraps router in order to redirect requests
+// arriving over plain HTTP to the same URL of https scheme,
+// before they are routed:
+//
+//	fastroute.ForceHTTPS(routes, fastroute.HTTPSExempt("/healthz"))
+//
+// The path and query are preserved, while the port is
+// dropped from the host, so the default HTTPS port is used.
+// GET and HEAD requests are redirected with 301 Moved
+// Permanently, others with 308 Permanent Redirect, so the
+// method and body are not changed. Secure requests and
+// exempt paths are routed by router.
+//
+// Request is secure if it was received over TLS, or if its
+// X-Forwarded-Proto header is a secure scheme. The header
+// is set by a TLS terminating proxy, but any client may send
+// it too, which only keeps the client itself on plain HTTP.
+// Let the proxy overwrite the header, or do not trust it by
+// HTTPSSecureSchemes without schemes, when the server is
+// reachable directly.
+func ForceHTTPS(router Router, options ...HTTPSOption) Router {
+	r := &httpsRedirect{schemes: []string{"https"}}
+	for _, option := range options {
+		option(r)
+	}
+	exempt := Chain(r.exempt...)
+
+	return wrapper{RouterFunc(func(req *http.Request) http.Handler {
+		if req.TLS != nil || r.secureProxy(req) || exempt.Route(req) != nil {
+			return router.Route(req)
+		}
+		host := req.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+			if strings.IndexByte(h, ':') != -1 {
+				host = "[" + h + "]"
+			}
+		}
+		code := http.StatusMovedPermanently
+		if req.Method != "GET" && req.Method != "HEAD" {
+			code = 308 // Permanent Redirect
+		}
+		return http.RedirectHandler("https://"+host+req.URL.RequestURI(), code)
+	}), router}
+}
+
+// whether request reached the proxy by a secure scheme
+func (r *httpsRedirect) secureProxy(req *http.Request) bool {
+	proto := req.Header.Get("X-Forwarded-Proto")
+	if pos := strings.IndexByte(proto, ','); pos != -1 {
+		proto = proto[:pos] // the first proxy is the one client reached
+	}
+	proto = strings.TrimSpace(proto)
+	for _, scheme := range r.schemes {
+		if strings.EqualFold(proto, scheme) {
+			return true
+		}
+	}
+	return false
+}
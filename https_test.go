@@ -0,0 +1,59 @@
+package fastroute_test
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+func TestForceHTTPS(t *testing.T) {
+	t.Parallel()
+	handler := func(w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte(fastroute.Pattern(req)))
+	}
+	routes := fastroute.Chain(
+		fastroute.New("/users/:id", handler),
+		fastroute.New("/.well-known/acme-challenge/:token", handler),
+	)
+	router := fastroute.ForceHTTPS(routes, fastroute.HTTPSExempt("/.well-known/acme-challenge/:token"))
+	untrusted := fastroute.ForceHTTPS(routes, fastroute.HTTPSSecureSchemes())
+
+	cases := []struct {
+		router                 fastroute.Router
+		method, url, forwarded string
+		secure                 bool
+		code                   int
+		location               string
+	}{
+		{router, "GET", "http://example.com/users/5?tab=posts", "", false, 301, "https://example.com/users/5?tab=posts"},
+		{router, "HEAD", "http://example.com:8080/users/5", "", false, 301, "https://example.com/users/5"},
+		{router, "GET", "http://[::1]:8080/users/5", "", false, 301, "https://[::1]/users/5"},
+		{router, "POST", "http://example.com/users/5", "", false, 308, "https://example.com/users/5"},
+		{router, "GET", "http://example.com/missing", "", false, 301, "https://example.com/missing"},
+		{router, "GET", "http://example.com/users/5", "", true, 200, ""},
+		{router, "GET", "http://example.com/users/5", "HTTPS", false, 200, ""},
+		{router, "GET", "http://example.com/users/5", "https, http", false, 200, ""},
+		{router, "GET", "http://example.com/users/5", "http", false, 301, "https://example.com/users/5"},
+		{router, "GET", "http://example.com/.well-known/acme-challenge/abc", "", false, 200, ""},
+		{router, "GET", "https://example.com/missing", "", true, 404, ""},
+		{untrusted, "GET", "http://example.com/users/5", "https", false, 301, "https://example.com/users/5"},
+	}
+	for i, c := range cases {
+		req, _ := http.NewRequest(c.method, c.url, nil)
+		if c.forwarded != "" {
+			req.Header.Set("X-Forwarded-Proto", c.forwarded)
+		}
+		if c.secure {
+			req.TLS = &tls.ConnectionState{}
+		}
+		w := httptest.NewRecorder()
+		c.router.ServeHTTP(w, req)
+
+		if w.Code != c.code || w.Header().Get("Location") != c.location {
+			t.Fatalf("expected response: %d %q, but got: %d %q, case: %d", c.code, c.location, w.Code, w.Header().Get("Location"), i)
+		}
+	}
+}
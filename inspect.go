@@ -0,0 +1,152 @@
+package fastroute
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// RouteInfo describes a single route, as
+// reported by Inspect.
+type RouteInfo struct {
+	// Pattern is the path pattern matched by route.
+	Pattern string `json:"pattern"`
+
+	// Static is true if pattern has no parameters.
+	Static bool `json:"static"`
+
+	// Params lists parameter names in the order
+	// they are bound, host parameters first.
+	Params []string `json:"params,omitempty"`
+
+	// CatchAll is true if pattern has a
+	// catch-all parameter.
+	CatchAll bool `json:"catch_all"`
+
+	// Host is the host pattern, if the route
+	// is scoped by Host.
+	Host string `json:"host,omitempty"`
+
+	// Methods lists request methods accepted by
+	// the route, if it is method scoped.
+	Methods []string `json:"methods,omitempty"`
+
+	// Handler identifies the handler served. It is
+	// the function name if handler is a function,
+	// otherwise the type name of handler. Empty if
+	// the route was only enumerated by Patterner.
+	Handler string `json:"handler,omitempty"`
+
+	// Disabled is true if the route was taken
+	// out of service by Toggle, or is not active
+	// at the moment by Schedule.
+	Disabled bool `json:"disabled,omitempty"`
+
+	// Stats holds match statistics of the route,
+	// if it is counted by Stats.
+	Stats *RouteStats `json:"stats,omitempty"`
+
+	// Tags lists tags given to the route by Tagged.
+	Tags []string `json:"tags,omitempty"`
+}
+
+// Inspect describes all the routes the given router
+// is composed of, in the order they are tried.
+//
+// Routes of this package are described fully, while
+// routers which only implement Patterner are described
+// by parsing their patterns. Opaque routers, which cannot
+// be enumerated are skipped, see Patterns in order to
+// find out whether the description is complete.
+func Inspect(router Router) []RouteInfo {
+	routes, _ := inspect(router)
+	return routes
+}
+
+// used internally by routers of this package to
+// describe routes, the boolean reports whether
+// all of them could be enumerated
+type inspector interface {
+	inspect() ([]RouteInfo, bool)
+}
+
+func inspect(router Router) ([]RouteInfo, bool) {
+	switch t := router.(type) {
+	case inspector:
+		return t.inspect()
+	case Patterner:
+		patterns := t.Patterns()
+		routes := make([]RouteInfo, len(patterns))
+		for i, pattern := range patterns {
+			routes[i] = describe(pattern)
+		}
+		o, partial := router.(Opaquer)
+		return routes, !partial || !o.Opaque()
+	}
+	return nil, false
+}
+
+// describes route by parsing its pattern
+func describe(pattern string) RouteInfo {
+	info := RouteInfo{Pattern: pattern, Static: strings.IndexAny(pattern, ":*") == -1}
+	if info.Static {
+		return info
+	}
+
+	path, query := pattern, ""
+	if q := queryStart(pattern); q != -1 {
+		path, query = pattern[:q], pattern[q+1:]
+	}
+	for _, seg := range strings.Split(strings.Trim(path, "/"), "/") {
+		if seg == "*" {
+			info.CatchAll = true // anonymous
+			continue
+		}
+		if len(seg) > 1 && (seg[0] == ':' || seg[0] == '*') {
+			if pos := strings.IndexByte(seg, '\\'); pos != -1 {
+				seg = seg[:pos] // followed by literal suffix
+			}
+			info.Params = append(info.Params, seg[1:])
+			info.CatchAll = seg[0] == '*'
+		}
+	}
+	if query != "" {
+		params, _ := parseQuery(query)
+		for _, param := range params {
+			info.Params = append(info.Params, param.name)
+		}
+	}
+	if strings.IndexByte(pattern, '\\') != -1 {
+		info.Static = info.Params == nil // signs may all be escaped
+	}
+	return info
+}
+
+func handlerName(h http.Handler) string {
+	var v reflect.Value
+	if eh, ok := h.(*errorHandler); ok {
+		v = reflect.ValueOf(eh.handler)
+	} else {
+		v = reflect.ValueOf(h)
+	}
+	if v.Kind() == reflect.Func {
+		if fn := runtime.FuncForPC(v.Pointer()); fn != nil {
+			return fn.Name()
+		}
+	}
+	return fmt.Sprintf("%T", h)
+}
+
+// the largest number of parameters bound by any
+// of enumerable router routes
+func maxParams(router Router) int {
+	var num int
+	for _, info := range Inspect(router) {
+		if len(info.Params) > num {
+			num = len(info.Params)
+		}
+	}
+	return num
+}
@@ -0,0 +1,87 @@
+package fastroute_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+func inspectHandlerFunc(w http.ResponseWriter, req *http.Request) {}
+
+func TestInspect(t *testing.T) {
+	t.Parallel()
+
+	router := fastroute.Chain(
+		fastroute.New("/status", inspectHandlerFunc),
+		fastroute.New("/users/:id/files/*filepath", http.HandlerFunc(inspectHandlerFunc)),
+		fastroute.New("/hello/:name", http.NotFoundHandler()),
+		fastroute.New("/x/:id", paramWriter("id")),
+		fastroute.RouterFunc(func(req *http.Request) http.Handler {
+			return nil
+		}),
+	)
+
+	expected := []fastroute.RouteInfo{
+		{
+			Pattern: "/status",
+			Static:  true,
+			Handler: "github.com/DATA-DOG/fastroute_test.inspectHandlerFunc",
+		},
+		{
+			Pattern:  "/users/:id/files/*filepath",
+			Params:   []string{"id", "filepath"},
+			CatchAll: true,
+			Handler:  "github.com/DATA-DOG/fastroute_test.inspectHandlerFunc",
+		},
+		{
+			Pattern: "/hello/:name",
+			Params:  []string{"name"},
+			Handler: "net/http.NotFound",
+		},
+		{
+			Pattern: "/x/:id",
+			Params:  []string{"id"},
+			Handler: "fastroute_test.paramWriter",
+		},
+	}
+
+	actual := fastroute.Inspect(router)
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("expected routes: %+v, but got: %+v", expected, actual)
+	}
+
+	data, err := json.Marshal(actual[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"params":["id","filepath"]`) {
+		t.Fatalf("unexpected json: %s", data)
+	}
+}
+
+func TestInspectPatterner(t *testing.T) {
+	t.Parallel()
+
+	router := struct {
+		fastroute.Router
+		fastroute.Patterner
+	}{fastroute.New("/a", inspectHandlerFunc), patterns{"/a", "/b/:id"}}
+
+	actual := fastroute.Inspect(router)
+	if len(actual) != 2 {
+		t.Fatalf("expected two routes, but got: %+v", actual)
+	}
+	if actual[1].Static || actual[1].Params[0] != "id" || actual[1].Handler != "" {
+		t.Fatalf("unexpected route description: %+v", actual[1])
+	}
+}
+
+type patterns []string
+
+func (p patterns) Patterns() []string {
+	return p
+}
@@ -0,0 +1,189 @@
+//go:build go1.18
+// +build go1.18
+
+package fastroute
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// JSONOption configures JSON.
+type JSONOption func(*jsonEndpoint)
+
+type jsonEndpoint struct {
+	limit  int64
+	errors []ErrorOption
+}
+
+// JSONBodyLimit sets the size of request body in bytes,
+// which is decoded at most, 1MB by default. Larger bodies
+// are responded with 413 Request Entity Too Large.
+func JSONBodyLimit(n int64) JSONOption {
+	return func(e *jsonEndpoint) {
+		e.limit = n
+	}
+}
+
+// JSONErrorHandler responds to errors by respond, instead
+// of DefaultErrorHandler, the same as ErrorHandler does.
+func JSONErrorHandler(respond func(http.ResponseWriter, *http.Request, error)) JSONOption {
+	return func(e *jsonEndpoint) {
+		e.errors = append(e.errors, ErrorHandler(respond))
+	}
+}
+
+// JSON creates handler of a JSON endpoint, which decodes
+// request into Req and encodes the result of f as response,
+// so that f deals with typed values only:
+//
+//	type getUser struct {
+//		ID     int  `param:"id"`
+//		Active bool `json:"active"`
+//	}
+//
+//	fastroute.New("/users/:id", fastroute.JSON(func(ctx context.Context, req getUser) (User, error) {
+//		return users.Find(ctx, req.ID, req.Active)
+//	}))
+//
+// Request body is decoded by encoding/json, if there is
+// any, and it must be of application/json, or other JSON
+// media type, like application/merge-patch+json. Requests
+// without body, like GET ones usually are, leave Req as is.
+// Then fields of struct tagged by param are set to values
+// of path parameters, converted to the field type, which
+// may be string, bool, or any integer or float kind, so
+// path parameters take precedence over the body.
+//
+// The result is encoded with 200 OK status. Errors of f
+// are responded by DefaultErrorHandler, unless
+// JSONErrorHandler is given, while request, which
+// cannot be decoded is responded with StatusError of 415
+// Unsupported Media Type, 413 Request Entity Too Large, or
+// 400 Bad Request. Parameters are recycled before errors
+// are responded, as for any handler returning error.
+func JSON[Req, Resp any](f func(ctx context.Context, req Req) (Resp, error), options ...JSONOption) http.Handler {
+	e := &jsonEndpoint{limit: 1 << 20}
+	for _, option := range options {
+		option(e)
+	}
+	return HandleErrors(func(w http.ResponseWriter, req *http.Request) error {
+		var in Req
+		if err := decodeJSON(req, &in, e.limit); err != nil {
+			return err
+		}
+		if err := bindParams(Parameters(req), reflect.ValueOf(&in).Elem()); err != nil {
+			return err
+		}
+		out, err := f(req.Context(), in)
+		if err != nil {
+			return err
+		}
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		return json.NewEncoder(w).Encode(out)
+	}, e.errors...)
+}
+
+// decodes JSON body of request to v, unless it is empty
+// or larger than limit
+func decodeJSON(req *http.Request, v interface{}, limit int64) error {
+	if req.Body == nil || req.ContentLength == 0 {
+		return nil
+	}
+	tooLarge := StatusError{http.StatusRequestEntityTooLarge, errors.New("request body is too large")}
+	if req.ContentLength > limit {
+		return tooLarge
+	}
+	data, err := ioutil.ReadAll(io.LimitReader(req.Body, limit+1))
+	switch {
+	case err != nil:
+		return StatusError{http.StatusBadRequest, err}
+	case int64(len(data)) > limit:
+		return tooLarge
+	case len(bytes.TrimSpace(data)) == 0:
+		return nil // length was not known
+	}
+
+	contentType := req.Header.Get("Content-Type")
+	if mediaType, _, err := mime.ParseMediaType(contentType); err != nil ||
+		(mediaType != "application/json" && !strings.HasSuffix(mediaType, "+json")) {
+		return StatusError{http.StatusUnsupportedMediaType, fmt.Errorf("request body is not JSON, but: %q", contentType)}
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return StatusError{http.StatusBadRequest, err}
+	}
+	return nil
+}
+
+// sets fields of struct v tagged by param
+// to values of the named parameters
+func bindParams(ps Params, v reflect.Value) error {
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+	for i := 0; i < v.NumField(); i++ {
+		name, ok := v.Type().Field(i).Tag.Lookup("param")
+		if !ok {
+			continue
+		}
+		value, found := "", false
+		for _, p := range ps {
+			if p.Key == name {
+				value, found = p.Value, true
+				break
+			}
+		}
+		if !found {
+			continue
+		}
+		if err := setField(v.Field(i), value); err != nil {
+			return StatusError{http.StatusBadRequest, fmt.Errorf("path parameter %q: %s", name, err)}
+		}
+	}
+	return nil
+}
+
+// converts parameter value to the kind of field
+func setField(field reflect.Value, value string) error {
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(value, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(value, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetFloat(n)
+	default:
+		return fmt.Errorf("cannot be bound to field of %s", field.Type())
+	}
+	return nil
+}
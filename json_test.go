@@ -0,0 +1,119 @@
+//go:build go1.18
+// +build go1.18
+
+package fastroute_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+type updateUser struct {
+	ID     int64  `param:"id"`
+	Org    string `param:"org" json:"org"`
+	Name   string `json:"name"`
+	Active bool   `json:"active"`
+}
+
+type user struct {
+	ID     int64  `json:"id"`
+	Org    string `json:"org"`
+	Name   string `json:"name"`
+	Active bool   `json:"active"`
+}
+
+func TestJSON(t *testing.T) {
+	t.Parallel()
+	handler := fastroute.JSON(func(ctx context.Context, req updateUser) (user, error) {
+		if req.Name == "nobody" {
+			return user{}, fastroute.StatusError{Code: http.StatusNotFound, Err: errors.New("no such user")}
+		}
+		if req.Name == "broken" {
+			return user{}, errors.New("database is down")
+		}
+		return user{req.ID, req.Org, req.Name, req.Active}, nil
+	})
+	router := fastroute.New("/orgs/:org/users/:id", handler)
+
+	cases := []struct {
+		method, path, contentType, body string
+		code                            int
+		response                        string
+	}{
+		{"PUT", "/orgs/acme/users/5", "application/json", `{"name":"gopher","active":true,"org":"other"}`, 200, `{"id":5,"org":"acme","name":"gopher","active":true}`},
+		{"PATCH", "/orgs/acme/users/5", "application/merge-patch+json; charset=utf-8", `{"name":"gopher"}`, 200, `{"id":5,"org":"acme","name":"gopher","active":false}`},
+		{"GET", "/orgs/acme/users/5", "", "", 200, `{"id":5,"org":"acme","name":"","active":false}`},
+		{"DELETE", "/orgs/acme/users/5", "text/plain", "  \n", 200, `{"id":5,"org":"acme","name":"","active":false}`},
+		{"PUT", "/orgs/acme/users/5", "text/plain", `{"name":"gopher"}`, 415, "Unsupported Media Type"},
+		{"PUT", "/orgs/acme/users/5", "", `{"name":"gopher"}`, 415, "Unsupported Media Type"},
+		{"PUT", "/orgs/acme/users/5", "application/json", `{"name":`, 400, "Bad Request"},
+		{"PUT", "/orgs/acme/users/x", "application/json", `{}`, 400, "Bad Request"},
+		{"PUT", "/orgs/acme/users/5", "application/json", `{"name":"` + strings.Repeat("a", 1<<20) + `"}`, 413, "Request Entity Too Large"},
+		{"PUT", "/orgs/acme/users/5", "application/json", `{"name":"nobody"}`, 404, "Not Found"},
+		{"PUT", "/orgs/acme/users/5", "application/json", `{"name":"broken"}`, 500, "Internal Server Error"},
+	}
+	for i, c := range cases {
+		req, _ := http.NewRequest(c.method, c.path, strings.NewReader(c.body))
+		if c.contentType != "" {
+			req.Header.Set("Content-Type", c.contentType)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != c.code || strings.TrimSpace(w.Body.String()) != c.response {
+			t.Fatalf("expected response: %d %s, but got: %d %s, case: %d", c.code, c.response, w.Code, w.Body.String(), i)
+		}
+		if c.code == 200 && w.Header().Get("Content-Type") != "application/json; charset=utf-8" {
+			t.Fatalf("expected JSON content type, but got: %q, case: %d", w.Header().Get("Content-Type"), i)
+		}
+	}
+
+	// oversized body of unknown length
+	req, _ := http.NewRequest("PUT", "/orgs/acme/users/5", strings.NewReader(`{"name":"`+strings.Repeat("a", 1<<20)+`"}`))
+	req.ContentLength = -1
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != 413 {
+		t.Fatalf("expected oversized body of unknown length to be rejected, but got: %d", w.Code)
+	}
+}
+
+func TestJSONOptions(t *testing.T) {
+	t.Parallel()
+	router := fastroute.New("/users/:id", fastroute.JSON(func(ctx context.Context, req updateUser) (user, error) {
+		if req.Name == "nobody" {
+			return user{}, fmt.Errorf("user %d: %w", req.ID, fastroute.StatusError{Code: http.StatusNotFound, Err: errors.New("no such user")})
+		}
+		return user{ID: req.ID, Name: req.Name}, nil
+	}, fastroute.JSONBodyLimit(32), fastroute.JSONErrorHandler(func(w http.ResponseWriter, req *http.Request, err error) {
+		w.Header().Set("X-Error", err.Error())
+		fastroute.DefaultErrorHandler(w, req, err)
+	})))
+
+	cases := []struct {
+		body, errs string
+		code       int
+	}{
+		{`{"name":"gopher"}`, "", 200},
+		{`{"name":"` + strings.Repeat("a", 32) + `"}`, "request body is too large", 413},
+		{`{"name":"nobody"}`, "user 5: no such user", 404},
+	}
+	for i, c := range cases {
+		req, _ := http.NewRequest("PUT", "/users/5", strings.NewReader(c.body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != c.code || w.Header().Get("X-Error") != c.errs {
+			t.Fatalf("expected response: %d %q, but got: %d %q, case: %d", c.code, c.errs, w.Code, w.Header().Get("X-Error"), i)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package fastroute
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Languages creates Router, which routes request by the
+// router of the language it prefers, negotiated by its
+// Accept-Language header, for pre-localized handlers:
+//
+//	fastroute.Languages(map[string]fastroute.Router{
+//		"en": enRoutes,
+//		"de": deRoutes,
+//	}, "en")
+//
+// Language ranges are tried in the order of their quality,
+// each matched by lookup of RFC 4647: "de-AT" is looked up
+// as "de-AT" and then "de". Tags are compared ignoring case.
+// Wildcard, missing or malformed header, or ranges matching
+// none of the languages, choose the default one, rather
+// than failing the request with 406 Not Acceptable.
+//
+// The chosen tag, as given in the map, is bound as "language"
+// parameter before path parameters, the way Host binds its
+// parameters. Served responses vary by Accept-Language
+// header, which is added to Vary header before the handler
+// is served.
+//
+// It panics if there is no router for the default tag.
+func Languages(languages map[string]Router, defaultTag string) Router {
+	if languages[defaultTag] == nil {
+		panic("there is no router for default language: " + defaultTag)
+	}
+
+	tags := make([]string, 0, len(languages))
+	for tag := range languages {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	routers := make([]Router, len(tags))
+	var num int
+	for i, tag := range tags {
+		routers[i] = languages[tag]
+		if n := maxParams(routers[i]); n > num {
+			num = n
+		}
+	}
+	num++
+	pool := newParamsPool(num)
+
+	return negotiated{wrapper{RouterFunc(func(req *http.Request) http.Handler {
+		tag := negotiate(req.Header.Get("Accept-Language"), tags)
+		if tag == "" {
+			tag = defaultTag
+		}
+		router := languages[tag]
+
+		ps := boundParams(req)
+		bound := ps != nil
+		if !bound {
+			ps = pool.get()
+		}
+		n := len(ps.params)
+		ps.params.Append("language", tag)
+
+		var h http.Handler
+		if bound {
+			if h = router.Route(req); h == nil {
+				ps.params = ps.params[:n]
+				return nil
+			}
+		} else {
+			ps.pattern = req.URL.Path
+			ps.ReadCloser = req.Body
+			req.Body = ps
+			ps.binding, ps.kept = true, len(ps.params)
+			h = router.Route(req)
+			ps.binding = false
+			if h == nil {
+				ps.reset(req)
+				return nil
+			}
+			h = ps.releasing(req, h)
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			w.Header().Add("Vary", "Accept-Language")
+			h.ServeHTTP(w, req)
+		})
+	}), Chain(routers...)}, tags, routers}
+}
+
+// routes by the router of negotiated language,
+// routers are in the order of their tags
+type negotiated struct {
+	wrapper
+	tags    []string
+	routers []Router
+}
+
+// chooses the tag for Accept-Language header by lookup,
+// returns empty string if none of the ranges match
+func negotiate(header string, tags []string) string {
+	var chosen string
+	best := 0.0
+	for header != "" {
+		lang := header
+		if pos := strings.IndexByte(header, ','); pos != -1 {
+			lang, header = header[:pos], header[pos+1:]
+		} else {
+			header = ""
+		}
+
+		q := 1.0
+		if pos := strings.IndexByte(lang, ';'); pos != -1 {
+			param := strings.TrimSpace(lang[pos+1:])
+			lang = lang[:pos]
+			if len(param) < 3 || (param[0] != 'q' && param[0] != 'Q') || param[1] != '=' {
+				continue
+			}
+			var err error
+			if q, err = strconv.ParseFloat(param[2:], 64); err != nil || q > 1 {
+				continue
+			}
+		}
+		if q <= best {
+			continue // a preceding range of the same quality wins
+		}
+		if tag := lookup(strings.TrimSpace(lang), tags); tag != "" {
+			chosen, best = tag, q
+		}
+	}
+	return chosen
+}
+
+// looks up the tag for language range, truncating
+// its subtags from the end, until one matches
+func lookup(lang string, tags []string) string {
+	for lang != "" && lang != "*" {
+		for _, tag := range tags {
+			if strings.EqualFold(tag, lang) {
+				return tag
+			}
+		}
+		pos := strings.LastIndex(lang, "-")
+		if pos == -1 {
+			return ""
+		}
+		lang = lang[:pos]
+		if pos >= 2 && lang[pos-2] == '-' {
+			lang = lang[:pos-2] // single character subtag goes along
+		}
+	}
+	return ""
+}
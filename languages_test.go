@@ -0,0 +1,63 @@
+package fastroute_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+func TestLanguages(t *testing.T) {
+	t.Parallel()
+	localized := func(lang string) fastroute.Router {
+		return fastroute.New("/greet/:name", func(w http.ResponseWriter, req *http.Request) {
+			ps := fastroute.Parameters(req)
+			w.Write([]byte(lang + " " + ps.ByName("language") + " " + ps.ByName("name")))
+		})
+	}
+	router := fastroute.Languages(map[string]fastroute.Router{
+		"en":    localized("en"),
+		"de":    localized("de"),
+		"pt-BR": localized("pt-BR"),
+	}, "en")
+
+	cases := map[string]string{
+		"":                           "en en bob",
+		"de":                         "de de bob",
+		"de-AT":                      "de de bob",
+		"DE-at-x-private":            "de de bob",
+		"fr, de;q=0.5":               "de de bob",
+		"en;q=0.4, de;q=0.8":         "de de bob",
+		"de;q=0.8, en;q=0.8":         "de de bob",
+		"pt-br":                      "pt-BR pt-BR bob",
+		"pt":                         "en en bob",
+		"*":                          "en en bob",
+		"fr, *;q=0.5":                "en en bob",
+		"de;q=0":                     "en en bob",
+		"de;q=oops, ;;, \x00garbage": "en en bob",
+	}
+	for header, expected := range cases {
+		req, _ := http.NewRequest("GET", "/greet/bob", nil)
+		req.Header.Set("Accept-Language", header)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Body.String() != expected {
+			t.Fatalf("expected Accept-Language %q to be served %q, but got %q", header, expected, w.Body.String())
+		}
+		if vary := w.Header().Get("Vary"); vary != "Accept-Language" {
+			t.Fatalf("expected response to vary by Accept-Language, but got: %q", vary)
+		}
+		if fastroute.Parameters(req) != nil {
+			t.Fatal("expected parameters to be recycled")
+		}
+	}
+
+	req, _ := http.NewRequest("GET", "/missing", nil)
+	if router.Route(req) != nil || fastroute.Parameters(req) != nil {
+		t.Fatal("expected request not to be matched")
+	}
+	if patterns, _ := fastroute.Patterns(router); len(patterns) != 3 {
+		t.Fatalf("expected routes of all languages, but got: %v", patterns)
+	}
+}
@@ -0,0 +1,136 @@
+package fastroute
+
+import (
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// LimitOption configures Limit.
+type LimitOption func(*limiter)
+
+type limiter struct {
+	depth      int32
+	timeout    time.Duration
+	retryAfter string
+	metrics    *LimitMetrics
+}
+
+// LimitMetrics counts requests, which exceeded the
+// limit, see LimitReport. It is safe to read while
+// requests are served.
+type LimitMetrics struct {
+	queued   int64
+	rejected int64
+}
+
+// Queued is the number of requests, which had to
+// wait for another one to finish.
+func (m *LimitMetrics) Queued() int64 {
+	return atomic.LoadInt64(&m.queued)
+}
+
+// Rejected is the number of requests denied with
+// 503 Service Unavailable.
+func (m *LimitMetrics) Rejected() int64 {
+	return atomic.LoadInt64(&m.rejected)
+}
+
+// LimitQueue lets up to depth requests over the limit
+// wait for timeout, before they are rejected. Requests
+// wait without timeout, if it is not positive.
+func LimitQueue(depth int, timeout time.Duration) LimitOption {
+	return func(l *limiter) {
+		l.depth, l.timeout = int32(depth), timeout
+	}
+}
+
+// LimitRetryAfter sets Retry-After header of rejected
+// requests, it is one second by default.
+func LimitRetryAfter(d time.Duration) LimitOption {
+	return func(l *limiter) {
+		l.retryAfter = strconv.Itoa(int((d + time.Second - 1) / time.Second))
+	}
+}
+
+// LimitReport counts queued and rejected requests
+// to the given metrics.
+func LimitReport(metrics *LimitMetrics) LimitOption {
+	return func(l *limiter) {
+		l.metrics = metrics
+	}
+}
+
+// Limit wraps router in order to serve at most n matched
+// requests at a time, for example to keep an expensive
+// report endpoint from starving the process:
+//
+//	fastroute.Limit(4, reports, fastroute.LimitQueue(16, 5*time.Second))
+//
+// Requests over the limit are denied with 503 Service
+// Unavailable and Retry-After header, unless LimitQueue
+// lets them wait. Parameters stay bound to a waiting
+// request, so they are available to the handler once it
+// is served, and are recycled when it is rejected. Waiting
+// request is rejected as well, once its context is done.
+// A slot is released even if handler panics.
+//
+// It panics if n is not positive.
+func Limit(n int, router Router, options ...LimitOption) Router {
+	if n <= 0 {
+		panic("limit of concurrent requests must be positive: " + strconv.Itoa(n))
+	}
+	l := &limiter{retryAfter: "1", metrics: &LimitMetrics{}}
+	for _, option := range options {
+		option(l)
+	}
+	slots := make(chan struct{}, n)
+	var waiting int32
+
+	acquire := func(req *http.Request) bool {
+		select {
+		case slots <- struct{}{}:
+			return true
+		default:
+		}
+		if atomic.AddInt32(&waiting, 1) > l.depth {
+			atomic.AddInt32(&waiting, -1)
+			return false
+		}
+		defer atomic.AddInt32(&waiting, -1)
+		atomic.AddInt64(&l.metrics.queued, 1)
+		var timeout <-chan time.Time // never, if not positive
+		if l.timeout > 0 {
+			timer := time.NewTimer(l.timeout)
+			defer timer.Stop()
+			timeout = timer.C
+		}
+		select {
+		case slots <- struct{}{}:
+			return true
+		case <-timeout:
+			return false
+		case <-requestDone(req):
+			return false
+		}
+	}
+
+	return wrapper{RouterFunc(func(req *http.Request) http.Handler {
+		h := router.Route(req)
+		if h == nil {
+			return nil
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			if !acquire(req) {
+				atomic.AddInt64(&l.metrics.rejected, 1)
+				Recycle(req)
+				w.Header().Set("Retry-After", l.retryAfter)
+				http.Error(w, "Service Unavailable", http.StatusServiceUnavailable)
+				return
+			}
+			defer func() { <-slots }()
+			h.ServeHTTP(w, req)
+		})
+	}), router}
+}
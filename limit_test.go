@@ -0,0 +1,98 @@
+package fastroute_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+func TestLimit(t *testing.T) {
+	t.Parallel()
+	entered, proceed := make(chan string), make(chan bool)
+	report := fastroute.New("/reports/:id", func(w http.ResponseWriter, req *http.Request) {
+		entered <- fastroute.Parameters(req).ByName("id")
+		if !<-proceed {
+			panic("report failed")
+		}
+	})
+
+	serve := func(router fastroute.Router, path string) chan *httptest.ResponseRecorder {
+		done := make(chan *httptest.ResponseRecorder, 1)
+		go func() {
+			req, _ := http.NewRequest("GET", path, nil)
+			w := httptest.NewRecorder()
+			defer func() {
+				recover()
+				done <- w
+			}()
+			router.ServeHTTP(w, req)
+		}()
+		return done
+	}
+
+	metrics := &fastroute.LimitMetrics{}
+	router := fastroute.Limit(1, report, fastroute.LimitRetryAfter(30*time.Second), fastroute.LimitReport(metrics))
+	first := serve(router, "/reports/1")
+	if id := <-entered; id != "1" {
+		t.Fatalf("expected first report to be served, but got: %s", id)
+	}
+	w := <-serve(router, "/reports/2")
+	if w.Code != http.StatusServiceUnavailable || w.Header().Get("Retry-After") != "30" {
+		t.Fatalf("expected request over the limit to be rejected, but got: %d %v", w.Code, w.Header())
+	}
+	proceed <- false // panics, slot must be released anyway
+	<-first
+	second := serve(router, "/reports/3")
+	if id := <-entered; id != "3" {
+		t.Fatalf("expected slot to be released after panic, but got: %s", id)
+	}
+	proceed <- true
+	<-second
+	if metrics.Rejected() != 1 || metrics.Queued() != 0 {
+		t.Fatalf("expected one rejected request, but got: %d rejected, %d queued", metrics.Rejected(), metrics.Queued())
+	}
+
+	router = fastroute.Limit(1, report, fastroute.LimitQueue(1, time.Minute), fastroute.LimitReport(metrics))
+	first = serve(router, "/reports/4")
+	<-entered
+	queued := serve(router, "/reports/5")
+	for metrics.Queued() != 1 {
+		time.Sleep(time.Millisecond)
+	}
+	if w := <-serve(router, "/reports/6"); w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected request over the queue depth to be rejected, but got: %d", w.Code)
+	}
+	proceed <- true
+	<-first
+	if id := <-entered; id != "5" {
+		t.Fatalf("expected queued request to be served with its parameters, but got: %q", id)
+	}
+	proceed <- true
+	if w := <-queued; w.Code != http.StatusOK {
+		t.Fatalf("expected queued request to be served, but got: %d", w.Code)
+	}
+
+	router = fastroute.Limit(1, report, fastroute.LimitQueue(1, time.Millisecond))
+	first = serve(router, "/reports/7")
+	<-entered
+	if w := <-serve(router, "/reports/8"); w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected queued request to time out, but got: %d", w.Code)
+	}
+	proceed <- true
+	<-first
+}
+
+func TestLimitMustBePositive(t *testing.T) {
+	t.Parallel()
+	defer func() {
+		expected := "limit of concurrent requests must be positive: 0"
+		if err := recover(); fmt.Sprint(err) != expected {
+			t.Fatalf(`expected panic: "%s", but got: "%v"`, expected, err)
+		}
+	}()
+	fastroute.Limit(0, fastroute.New("/", http.NotFoundHandler()))
+}
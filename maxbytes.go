@@ -0,0 +1,46 @@
+package fastroute
+
+import "net/http"
+
+// MaxBytes wraps router in order to limit the size of
+// request body of matched requests to n bytes, using
+// http.MaxBytesReader.
+//
+// Requests declaring Content-Length over the limit are
+// denied with 413 Request Entity Too Large response and
+// the matched handler is not invoked, their parameters
+// are recycled before the 413 handler is returned.
+//
+// Otherwise the limit applies before handler reads
+// anything. Reading beyond the limit fails with an error,
+// which handler should respond to with 413 as well, as it
+// would with http.MaxBytesReader directly.
+//
+// When parameters are bound to the request, the limited
+// body is placed inside the parameters wrapper, so both
+// Parameters(req) and the body are available to handler,
+// and request has its original body restored afterwards.
+func MaxBytes(n int64, router Router) Router {
+	return wrapper{RouterFunc(func(req *http.Request) http.Handler {
+		h := router.Route(req)
+		if h == nil {
+			return nil
+		}
+		if req.ContentLength > n {
+			Recycle(req)
+			return entityTooLarge
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			limitBody(w, req, n, h)
+		})
+	}), router}
+}
+
+var entityTooLarge = http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+	http.Error(w, "Request Entity Too Large", http.StatusRequestEntityTooLarge)
+})
+
+// serves h with request body limited to n bytes
+func limitBody(w http.ResponseWriter, req *http.Request, n int64, h http.Handler) {
+	serveWithBody(w, req, http.MaxBytesReader(w, requestBody(req), n), h)
+}
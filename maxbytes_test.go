@@ -0,0 +1,90 @@
+package fastroute_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+func TestMaxBytes(t *testing.T) {
+	t.Parallel()
+
+	handler := func(w http.ResponseWriter, req *http.Request) {
+		body, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
+		w.Write([]byte(fastroute.Parameters(req).ByName("id") + " " + string(body)))
+	}
+
+	router := fastroute.MaxBytes(5, fastroute.Chain(
+		fastroute.New("/upload/:id", handler),
+		fastroute.New("/upload", handler),
+	))
+
+	cases := []struct {
+		path, body string
+		code       int
+		response   string
+	}{
+		{"/upload/5", "12345", 200, "5 12345"},
+		{"/upload/5", "123456", 413, "http: request body too large\n"},
+		{"/upload", "12345", 200, " 12345"},
+		{"/upload", "123456", 413, "http: request body too large\n"},
+	}
+
+	for i, c := range cases {
+		body := ioutil.NopCloser(strings.NewReader(c.body))
+		req, err := http.NewRequest("POST", c.path, body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != c.code || w.Body.String() != c.response {
+			t.Fatalf("expected response: %d %q, but got: %d %q, case: %d", c.code, c.response, w.Code, w.Body.String(), i)
+		}
+		if req.Body != body {
+			t.Fatalf("expected original body to be restored, but got: %T, case: %d", req.Body, i)
+		}
+		if fastroute.Parameters(req) != nil {
+			t.Fatalf("expected parameters to be recycled, case: %d", i)
+		}
+	}
+}
+
+func TestMaxBytesDeclaredContentLength(t *testing.T) {
+	t.Parallel()
+
+	var served bool
+	router := fastroute.MaxBytes(5, fastroute.New("/upload/:id", func(w http.ResponseWriter, req *http.Request) {
+		served = true
+	}))
+
+	req, err := http.NewRequest("POST", "/upload/5", strings.NewReader("123456"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.ContentLength != 6 {
+		t.Fatalf("expected content length to be declared, but got: %d", req.ContentLength)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if served || w.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("expected oversized request to be denied before handler, but got: %d", w.Code)
+	}
+	if fastroute.Parameters(req) != nil {
+		t.Fatal("expected parameters to be recycled")
+	}
+
+	if req, _ = http.NewRequest("POST", "/users", strings.NewReader("123456")); router.Route(req) != nil {
+		t.Fatal("expected unmatched oversized request to fall through")
+	}
+}
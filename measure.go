@@ -0,0 +1,111 @@
+package fastroute
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"time"
+)
+
+// Measure wraps router in order to record the response
+// of every matched request, by the pattern of route:
+// the status code, number of body bytes written and
+// the time it took to serve the handler.
+//
+// Status is the code of the first WriteHeader call,
+// or 200 if handler wrote body or returned without
+// writing anything. Record is called exactly once after
+// the handler is served, even if it panics, in which case
+// status is 500 unless it was written already, and the
+// panic is propagated further after recording.
+//
+// The response writer passed to handler implements
+// http.Flusher, http.Hijacker and io.ReaderFrom whether
+// the underlying one does or not, in which case flush is
+// a no-op and hijack fails. It also exposes the underlying
+// writer by Unwrap, used by http.ResponseController.
+func Measure(router Router, record func(pattern string, status, bytes int, d time.Duration)) Router {
+	return wrapper{RouterFunc(func(req *http.Request) http.Handler {
+		h := router.Route(req)
+		if h == nil {
+			return nil
+		}
+
+		pattern := Pattern(req) // parameters are recycled when served
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			mw := &measuredWriter{ResponseWriter: w}
+			start := time.Now()
+			defer func() {
+				if r := recover(); r != nil {
+					if mw.status == 0 {
+						mw.status = http.StatusInternalServerError
+					}
+					record(pattern, mw.status, mw.bytes, time.Since(start))
+					panic(r)
+				}
+				if mw.status == 0 {
+					mw.status = http.StatusOK
+				}
+				record(pattern, mw.status, mw.bytes, time.Since(start))
+			}()
+			h.ServeHTTP(mw, req)
+		})
+	}), router}
+}
+
+type measuredWriter struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (w *measuredWriter) WriteHeader(code int) {
+	if w.status == 0 {
+		w.status = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *measuredWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += n
+	return n, err
+}
+
+func (w *measuredWriter) ReadFrom(r io.Reader) (int64, error) {
+	rf, ok := w.ResponseWriter.(io.ReaderFrom)
+	if !ok {
+		return io.Copy(struct{ io.Writer }{w}, r) // hides ReadFrom
+	}
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := rf.ReadFrom(r)
+	w.bytes += int(n)
+	return n, err
+}
+
+func (w *measuredWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		if w.status == 0 {
+			w.status = http.StatusOK
+		}
+		f.Flush()
+	}
+}
+
+func (w *measuredWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if h, ok := w.ResponseWriter.(http.Hijacker); ok {
+		return h.Hijack()
+	}
+	return nil, nil, errors.New("fastroute: response writer does not support hijacking")
+}
+
+func (w *measuredWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,126 @@
+package fastroute_test
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+type measurement struct {
+	pattern       string
+	status, bytes int
+}
+
+func TestMeasure(t *testing.T) {
+	t.Parallel()
+
+	var records []measurement
+	router := fastroute.Measure(fastroute.Chain(
+		fastroute.New("/users/:id", func(w http.ResponseWriter, req *http.Request) {
+			w.Write([]byte("user " + fastroute.Parameters(req).ByName("id")))
+		}),
+		fastroute.New("/teapot", func(w http.ResponseWriter, req *http.Request) {
+			w.WriteHeader(http.StatusTeapot)
+			w.WriteHeader(http.StatusOK)
+			io.Copy(w.(io.ReaderFrom).(io.Writer), strings.NewReader("short"))
+			w.(io.ReaderFrom).ReadFrom(strings.NewReader("and stout"))
+		}),
+		fastroute.New("/empty", func(w http.ResponseWriter, req *http.Request) {}),
+		fastroute.New("/panic", func(w http.ResponseWriter, req *http.Request) {
+			panic("oops")
+		}),
+	), func(pattern string, status, bytes int, d time.Duration) {
+		if d < 0 {
+			t.Errorf("expected non negative duration, but got: %s", d)
+		}
+		records = append(records, measurement{pattern, status, bytes})
+	})
+
+	for _, path := range []string{"/users/12", "/teapot", "/empty", "/none"} {
+		req, _ := http.NewRequest("GET", path, nil)
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	func() {
+		defer func() {
+			if r := recover(); r != "oops" {
+				t.Fatalf("expected handler panic to be propagated, but got: %v", r)
+			}
+		}()
+		req, _ := http.NewRequest("GET", "/panic", nil)
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}()
+
+	expected := []measurement{
+		{"/users/:id", 200, 7},
+		{"/teapot", 418, 14},
+		{"/empty", 200, 0},
+		{"/panic", 500, 0},
+	}
+	if len(records) != len(expected) {
+		t.Fatalf("expected %d records, but got: %+v", len(expected), records)
+	}
+	for i, m := range expected {
+		if records[i] != m {
+			t.Fatalf("expected record: %+v, but got: %+v", m, records[i])
+		}
+	}
+}
+
+type hijackableRecorder struct {
+	*httptest.ResponseRecorder
+}
+
+func (hijackableRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return nil, nil, nil
+}
+
+func TestMeasuredWriterInterfaces(t *testing.T) {
+	t.Parallel()
+
+	var w http.ResponseWriter
+	router := fastroute.Measure(fastroute.New("/", func(rw http.ResponseWriter, req *http.Request) {
+		w = rw
+	}), func(string, int, int, time.Duration) {})
+
+	rec := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/", nil)
+	router.ServeHTTP(hijackableRecorder{rec}, req)
+
+	if _, ok := w.(http.Flusher); !ok {
+		t.Fatal("expected writer to implement http.Flusher")
+	}
+	if _, ok := w.(io.ReaderFrom); !ok {
+		t.Fatal("expected writer to implement io.ReaderFrom")
+	}
+	h, ok := w.(http.Hijacker)
+	if !ok {
+		t.Fatal("expected writer to implement http.Hijacker")
+	}
+	if _, _, err := h.Hijack(); err != nil {
+		t.Fatalf("expected hijack to be delegated, but got: %v", err)
+	}
+	u, ok := w.(interface {
+		Unwrap() http.ResponseWriter
+	})
+	if !ok || u.Unwrap().(hijackableRecorder).ResponseRecorder != rec {
+		t.Fatal("expected writer to unwrap to the underlying one")
+	}
+
+	w.(http.Flusher).Flush()
+	if !rec.Flushed {
+		t.Fatal("expected flush to be delegated")
+	}
+
+	router.ServeHTTP(rec, req)
+	if _, _, err := w.(http.Hijacker).Hijack(); err == nil {
+		t.Fatal("expected hijack to fail, when underlying writer does not support it")
+	}
+}
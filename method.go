@@ -0,0 +1,131 @@
+package fastroute
+
+import (
+	"net/http"
+	"strings"
+)
+
+// MethodGroup chains routes into single Router, which
+// matches only requests of the given method:
+//
+//	fastroute.Chain(
+//		fastroute.MethodGroup("GET",
+//			fastroute.New("/users", listUsers),
+//			fastroute.New("/users/:id", getUser),
+//		),
+//		fastroute.MethodGroup("POST",
+//			fastroute.New("/users", createUser),
+//		),
+//	)
+//
+// Several methods may be given delimited by pipe,
+// like "GET|HEAD", in order to match any of them.
+//
+// Method is compared case sensitively, as request
+// methods are. It panics if any method is not a valid
+// HTTP token. Accepted methods are reported by Inspect
+// for all the grouped routes.
+func MethodGroup(method string, routes ...Router) Router {
+	methods := strings.Split(method, "|")
+	for _, m := range methods {
+		if !validMethod(m) {
+			panic("not a valid request method: " + method)
+		}
+	}
+
+	router := Chain(routes...)
+	return methodScoped{wrapper{RouterFunc(func(req *http.Request) http.Handler {
+		for _, m := range methods {
+			if req.Method == m {
+				return router.Route(req)
+			}
+		}
+		return nil
+	}), router}, methods}
+}
+
+// whether method is a token as defined by RFC 7230
+func validMethod(method string) bool {
+	if method == "" {
+		return false
+	}
+	for i := 0; i < len(method); i++ {
+		c := method[i]
+		if c <= ' ' || c >= 0x7f || strings.IndexByte(`"(),/:;<=>?@[\]{}`, c) != -1 {
+			return false
+		}
+	}
+	return true
+}
+
+type methodScoped struct {
+	wrapper
+	methods []string
+}
+
+func (m methodScoped) inspect() ([]RouteInfo, bool) {
+	routes, ok := m.wrapper.inspect()
+	for i := range routes {
+		routes[i].Methods = scopeMethods(routes[i].Methods, m.methods)
+	}
+	return routes, ok
+}
+
+// methods accepted by both, inner scope
+// and the outer one
+func scopeMethods(inner, outer []string) []string {
+	if inner == nil {
+		return outer
+	}
+	accepted := []string{}
+	for _, method := range inner {
+		for _, allowed := range outer {
+			if method == allowed {
+				accepted = append(accepted, method)
+				break
+			}
+		}
+	}
+	return accepted
+}
+
+// AsteriskOptions wraps router in order to respond to
+// the server wide "OPTIONS *" request, as described in
+// RFC 7230 section 5.3.4, which does not target any
+// path and so is not matched by routes.
+//
+// The response has Allow header listing the union of
+// methods accepted by method scoped routes of router,
+// as reported by Inspect, and OPTIONS itself. Any other
+// request is routed to router.
+func AsteriskOptions(router Router) Router {
+	return wrapper{RouterFunc(func(req *http.Request) http.Handler {
+		if req.Method != "OPTIONS" || (req.URL.Path != "*" && req.RequestURI != "*") {
+			return router.Route(req)
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			w.Header().Set("Allow", strings.Join(allowedMethods(router), ", "))
+			w.Header().Set("Content-Length", "0")
+			w.WriteHeader(http.StatusOK)
+		})
+	}), router}
+}
+
+// union of methods accepted by router routes
+// in the order of appearance, including OPTIONS
+func allowedMethods(router Router) []string {
+	var methods []string
+	seen := map[string]bool{}
+	for _, info := range Inspect(router) {
+		for _, method := range info.Methods {
+			if !seen[method] {
+				seen[method] = true
+				methods = append(methods, method)
+			}
+		}
+	}
+	if !seen["OPTIONS"] {
+		methods = append(methods, "OPTIONS")
+	}
+	return methods
+}
@@ -0,0 +1,131 @@
+package fastroute_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+func TestMethodGroup(t *testing.T) {
+	t.Parallel()
+
+	handler := func(name string) http.HandlerFunc {
+		return func(w http.ResponseWriter, req *http.Request) {
+			fmt.Fprint(w, name, fastroute.Parameters(req).ByName("id"))
+		}
+	}
+
+	router := fastroute.Chain(
+		fastroute.MethodGroup("GET",
+			fastroute.New("/users", handler("list")),
+			fastroute.New("/users/:id", handler("get")),
+		),
+		fastroute.MethodGroup("POST",
+			fastroute.New("/users", handler("create")),
+		),
+		fastroute.MethodGroup("PUT|PATCH",
+			fastroute.New("/users/:id", handler("update")),
+		),
+	)
+
+	cases := []struct {
+		method, path string
+		code         int
+		body         string
+	}{
+		{"GET", "/users", 200, "list"},
+		{"GET", "/users/5", 200, "get5"},
+		{"POST", "/users", 200, "create"},
+		{"POST", "/users/5", 404, "404 page not found\n"},
+		{"get", "/users", 404, "404 page not found\n"},
+		{"DELETE", "/users", 404, "404 page not found\n"},
+		{"PUT", "/users/5", 200, "update5"},
+		{"PATCH", "/users/5", 200, "update5"},
+		{"PUT|PATCH", "/users/5", 404, "404 page not found\n"},
+	}
+
+	for i, c := range cases {
+		req, err := http.NewRequest(c.method, c.path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != c.code || w.Body.String() != c.body {
+			t.Fatalf("expected response: %d %q, but got: %d %q, case: %d", c.code, c.body, w.Code, w.Body.String(), i)
+		}
+	}
+
+	routes := fastroute.Inspect(router)
+	if len(routes) != 4 {
+		t.Fatalf("expected four routes, but got: %+v", routes)
+	}
+	for i, method := range []string{"GET", "GET", "POST", "PUT PATCH"} {
+		if fmt.Sprint(routes[i].Methods) != "["+method+"]" {
+			t.Fatalf("expected route: %s to accept %s, but got: %v", routes[i].Pattern, method, routes[i].Methods)
+		}
+	}
+
+	nested := fastroute.Inspect(fastroute.MethodGroup("GET", fastroute.MethodGroup("POST", fastroute.New("/", handler("")))))
+	if len(nested[0].Methods) != 0 || nested[0].Methods == nil {
+		t.Fatalf("expected nested method groups to accept no methods, but got: %v", nested[0].Methods)
+	}
+}
+
+func TestMethodGroupValidation(t *testing.T) {
+	t.Parallel()
+
+	for _, method := range []string{"", "GET POST", "GE/T", "GET\n", "GET|", "|GET", "GET||POST"} {
+		func() {
+			defer func() {
+				if err := recover(); fmt.Sprint(err) != "not a valid request method: "+method {
+					t.Fatalf("expected panic for method: %q, but got: %v", method, err)
+				}
+			}()
+			fastroute.MethodGroup(method)
+		}()
+	}
+}
+
+func TestAsteriskOptions(t *testing.T) {
+	t.Parallel()
+
+	handler := http.NotFoundHandler()
+	router := fastroute.AsteriskOptions(fastroute.Chain(
+		fastroute.MethodGroup("GET", fastroute.New("/users", handler)),
+		fastroute.MethodGroup("POST", fastroute.New("/users", handler)),
+		fastroute.MethodGroup("DELETE", fastroute.New("/users/:id", handler)),
+		fastroute.MethodGroup("GET", fastroute.New("/status", func(w http.ResponseWriter, req *http.Request) {})),
+	))
+
+	req, err := http.NewRequest("OPTIONS", "*", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != 200 || w.Header().Get("Allow") != "GET, POST, DELETE, OPTIONS" {
+		t.Fatalf("unexpected response: %d, allow: %s", w.Code, w.Header().Get("Allow"))
+	}
+
+	req, err = http.NewRequest("OPTIONS", "/status", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != 404 || w.Header().Get("Allow") != "" {
+		t.Fatalf("expected OPTIONS for path to be routed as usual, but got: %d", w.Code)
+	}
+
+	req, _ = http.NewRequest("GET", "/status", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != 200 {
+		t.Fatalf("expected request to be routed, but got: %d", w.Code)
+	}
+}
@@ -0,0 +1,144 @@
+package fastroute
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync/atomic"
+)
+
+// MirrorOption configures Mirror.
+type MirrorOption func(*mirror)
+
+type mirror struct {
+	seq       uint64
+	shadow    http.Handler
+	bodyLimit int64
+	slots     chan struct{}
+}
+
+// MirrorBodyLimit sets the maximum size of request body
+// buffered for the shadow handler, 64KB by default.
+// Requests having larger bodies are not mirrored.
+func MirrorBodyLimit(n int64) MirrorOption {
+	return func(m *mirror) {
+		m.bodyLimit = n
+	}
+}
+
+// MirrorConcurrency sets the maximum number of requests
+// served by the shadow handler at once, 16 by default.
+// Requests sampled while all of them are busy are not
+// mirrored.
+func MirrorConcurrency(n int) MirrorOption {
+	return func(m *mirror) {
+		m.slots = make(chan struct{}, n)
+	}
+}
+
+// Mirror serves requests matched by primary router as
+// usual, and replays the sample, a fraction from 0 to 1,
+// of them to shadow handler in the background. It is
+// meant to test a rewritten handler with real traffic,
+// before cutting over to it.
+//
+// Shadow handler receives a copy of request, having the
+// body buffered, the same parameters bound and context
+// not canceled together with the client request. If
+// shadow is a Router, it binds parameters itself. Its
+// response is discarded and panics are recovered, so
+// the shadow never affects the primary response.
+func Mirror(primary Router, shadow http.Handler, sample float64, options ...MirrorOption) Router {
+	m := &mirror{shadow: shadow, bodyLimit: 64 << 10, slots: make(chan struct{}, 16)}
+	for _, option := range options {
+		option(m)
+	}
+
+	return wrapper{RouterFunc(func(req *http.Request) http.Handler {
+		h := primary.Route(req)
+		if h == nil || float64(mix(atomic.AddUint64(&m.seq, 1))%10000) >= sample*10000 {
+			return h
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			select {
+			case m.slots <- struct{}{}:
+			default:
+				h.ServeHTTP(w, req) // shadow is busy
+				return
+			}
+
+			clone, body := m.clone(req)
+			if clone == nil {
+				<-m.slots
+				serveWithBody(w, req, body, h)
+				return
+			}
+
+			go func() {
+				defer func() {
+					recover()
+					<-m.slots
+				}()
+				shadow.ServeHTTP(&discardResponse{header: make(http.Header)}, clone)
+			}()
+			serveWithBody(w, req, body, h)
+		})
+	}), primary}
+}
+
+// clones request for the shadow, if its body fits the limit,
+// returns the body to replace the original one, since it
+// was partially read
+func (m *mirror) clone(req *http.Request) (*http.Request, io.ReadCloser) {
+	original := requestBody(req)
+
+	var buf []byte
+	var err error
+	body := original
+	if original != nil {
+		buf, err = ioutil.ReadAll(io.LimitReader(original, m.bodyLimit+1))
+		body = &replayedBody{io.MultiReader(bytes.NewReader(buf), original), original}
+	}
+	if err != nil || int64(len(buf)) > m.bodyLimit {
+		return nil, body
+	}
+
+	u := *req.URL
+	clone := &http.Request{
+		Method:        req.Method,
+		URL:           &u,
+		Proto:         req.Proto,
+		ProtoMajor:    req.ProtoMajor,
+		ProtoMinor:    req.ProtoMinor,
+		Header:        make(http.Header, len(req.Header)),
+		ContentLength: int64(len(buf)),
+		Host:          req.Host,
+		RemoteAddr:    req.RemoteAddr,
+		RequestURI:    req.RequestURI,
+	}
+	for key, values := range req.Header {
+		clone.Header[key] = append([]string(nil), values...)
+	}
+
+	clone.Body = ioutil.NopCloser(bytes.NewReader(buf))
+	if _, routes := m.shadow.(Router); !routes {
+		params := append(Params(nil), Parameters(req)...)
+		clone.Body = &parameters{ReadCloser: clone.Body, params: params, pattern: Pattern(req)}
+	}
+	return clone, body
+}
+
+// body partially buffered before primary handler reads it
+type replayedBody struct {
+	io.Reader
+	io.Closer
+}
+
+type discardResponse struct {
+	header http.Header
+}
+
+func (w *discardResponse) Header() http.Header         { return w.header }
+func (w *discardResponse) Write(b []byte) (int, error) { return len(b), nil }
+func (w *discardResponse) WriteHeader(int)             {}
@@ -0,0 +1,95 @@
+package fastroute_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+func TestMirror(t *testing.T) {
+	t.Parallel()
+
+	primary := fastroute.New("/users/:id", func(w http.ResponseWriter, req *http.Request) {
+		body, _ := ioutil.ReadAll(req.Body)
+		fmt.Fprintf(w, "%s %s", fastroute.Parameters(req).ByName("id"), body)
+	})
+
+	mirrored := make(chan string, 10)
+	shadow := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := ioutil.ReadAll(req.Body)
+		w.WriteHeader(500)
+		mirrored <- fmt.Sprintf("%s %s %s %s %s", req.Method, fastroute.Pattern(req), fastroute.Parameters(req).ByName("id"), body, req.Header.Get("X-Test"))
+		panic("shadow must not affect primary")
+	})
+
+	router := fastroute.Mirror(primary, shadow, 1, fastroute.MirrorBodyLimit(10))
+
+	serve := func(body string) string {
+		req, err := http.NewRequest("POST", "/users/5", strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("X-Test", "header")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if fastroute.Parameters(req) != nil {
+			t.Fatal("expected parameters to be recycled")
+		}
+		return w.Body.String()
+	}
+
+	if body := serve("payload"); body != "5 payload" {
+		t.Fatalf("unexpected primary response: %s", body)
+	}
+	select {
+	case shadowed := <-mirrored:
+		if shadowed != "POST /users/:id 5 payload header" {
+			t.Fatalf("unexpected mirrored request: %s", shadowed)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected request to be mirrored")
+	}
+
+	if body := serve("too large payload"); body != "5 too large payload" {
+		t.Fatalf("unexpected primary response: %s", body)
+	}
+	select {
+	case shadowed := <-mirrored:
+		t.Fatalf("expected request with large body not to be mirrored, but got: %s", shadowed)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestMirrorSampleAndConcurrency(t *testing.T) {
+	t.Parallel()
+
+	primary := fastroute.New("/", func(w http.ResponseWriter, req *http.Request) {})
+	release := make(chan struct{})
+	started := make(chan struct{}, 100)
+	shadow := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		started <- struct{}{}
+		<-release
+	})
+
+	serve := func(router fastroute.Router, n int) {
+		for i := 0; i < n; i++ {
+			req, _ := http.NewRequest("GET", "/", nil)
+			router.ServeHTTP(httptest.NewRecorder(), req)
+		}
+	}
+
+	serve(fastroute.Mirror(primary, shadow, 0), 100)
+	serve(fastroute.Mirror(primary, shadow, 1, fastroute.MirrorConcurrency(2)), 10)
+
+	time.Sleep(50 * time.Millisecond)
+	if len(started) != 2 {
+		t.Fatalf("expected mirroring to be bounded by two goroutines, but got: %d", len(started))
+	}
+	close(release)
+}
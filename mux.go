@@ -0,0 +1,283 @@
+package fastroute
+
+import (
+	"net/http"
+	"strings"
+)
+
+// TrailingSlash is the policy of Mux for requests,
+// which differ from a route by the trailing slash.
+type TrailingSlash int
+
+const (
+	// StrictSlash matches paths only as routes are
+	// registered, the way New does.
+	StrictSlash TrailingSlash = iota
+
+	// OptionalSlash matches paths regardless of the
+	// trailing slash, the way NewOptionalSlash does.
+	OptionalSlash
+
+	// RedirectSlash redirects requests to the path
+	// with or without the trailing slash, if that one
+	// is matched, with 301 Moved Permanently for GET
+	// and HEAD requests, or 308 Permanent Redirect for
+	// other methods, which must not be changed.
+	RedirectSlash
+)
+
+// MuxOption configures Mux.
+type MuxOption func(*muxTable)
+
+// MuxNotFound sets the handler serving requests, which
+// none of routes match, http.NotFound by default.
+func MuxNotFound(handler interface{}) MuxOption {
+	return func(t *muxTable) {
+		t.notFound = toHandler(handler)
+	}
+}
+
+// MuxMethodNotAllowed sets the handler serving requests,
+// which match a route by path, but not by method. Allow
+// header is set before it is served. By default, 405
+// Method Not Allowed is responded.
+func MuxMethodNotAllowed(handler interface{}) MuxOption {
+	return func(t *muxTable) {
+		t.methodNotAllowed = toHandler(handler)
+	}
+}
+
+// MuxPanicHandler sets the function responding to requests,
+// whose handler panicked with the recovered value. Panics
+// are not recovered by default.
+func MuxPanicHandler(handler func(w http.ResponseWriter, req *http.Request, recovered interface{})) MuxOption {
+	return func(t *muxTable) {
+		t.panicHandler = handler
+	}
+}
+
+// MuxTrailingSlash sets the policy for trailing slash,
+// StrictSlash by default.
+func MuxTrailingSlash(policy TrailingSlash) MuxOption {
+	return func(t *muxTable) {
+		t.slash = policy
+	}
+}
+
+// Mux is a Router wiring routes together with responses
+// to requests not found, or of a method not allowed, and
+// recovery from panics, see NewMux.
+type Mux struct {
+	prefix string
+	table  *muxTable
+}
+
+type muxTable struct {
+	entries []muxEntry
+	routes  []Router
+	router  Router // chain of routes
+
+	notFound         http.Handler
+	methodNotAllowed http.Handler
+	panicHandler     func(http.ResponseWriter, *http.Request, interface{})
+	slash            TrailingSlash
+}
+
+type muxEntry struct {
+	methods []string // nil if any method is accepted
+	path    Router   // matching path regardless of method
+}
+
+// NewMux creates Mux, for the common setup of a service:
+//
+//	mux := fastroute.NewMux(
+//		fastroute.MuxTrailingSlash(fastroute.RedirectSlash),
+//		fastroute.MuxPanicHandler(reportPanic),
+//	)
+//	mux.Handle("GET", "/users/:id", getUser)
+//
+//	api := mux.Group("/api/v1")
+//	api.Handle("POST", "/orders", createOrder)
+//
+//	http.ListenAndServe(":8080", mux)
+//
+// Routes are created by New, or NewOptionalSlash, scoped
+// by MethodGroup and chained in the order of registration,
+// so Mux is composed of the same routers one would chain
+// by hand. It is a Router itself, which may be nested in
+// other compositions, and it is enumerable by Patterns
+// and Inspect.
+//
+// Routes must be registered before requests are served.
+func NewMux(options ...MuxOption) *Mux {
+	t := &muxTable{router: Chain()}
+	for _, option := range options {
+		option(t)
+	}
+	return &Mux{table: t}
+}
+
+// Handle registers route for pattern and handler, given
+// in any format accepted by New, which matches requests
+// of the method. Several methods may be delimited by pipe,
+// like "GET|HEAD", and an empty method accepts any.
+//
+// It panics if pattern, handler or method is invalid.
+func (m *Mux) Handle(method, pattern string, handler interface{}) {
+	t := m.table
+	p := m.prefix + "/" + strings.TrimLeft(pattern, "/")
+	path := New(p, handler)
+	if t.slash == OptionalSlash {
+		path = NewOptionalSlash(p, handler)
+	}
+
+	entry, router := muxEntry{path: path}, path
+	if method != "" {
+		entry.methods = strings.Split(method, "|")
+		router = MethodGroup(method, path)
+	}
+	t.entries = append(t.entries, entry)
+	t.routes = append(t.routes, router)
+	t.router = Chain(t.routes...)
+}
+
+// Group returns Mux registering routes to the same
+// table, having pattern prefixed by prefix, like
+// "/api/v1". Groups may be nested.
+func (m *Mux) Group(prefix string) *Mux {
+	return &Mux{prefix: m.prefix + "/" + strings.Trim(prefix, "/"), table: m.table}
+}
+
+// Route routes request by the first route matching it.
+// Otherwise, if a route matches the path, but not the
+// method, or the other trailing slash is matched by
+// RedirectSlash policy, the request is claimed by the
+// response for it. It returns nil when no route matches
+// the path, so a following router may match it.
+func (m *Mux) Route(req *http.Request) http.Handler {
+	t := m.table
+	if h := t.router.Route(req); h != nil {
+		return t.recovered(h)
+	}
+	if allowed := t.allowed(req); len(allowed) > 0 {
+		return t.notAllowed(allowed)
+	}
+	if t.slash == RedirectSlash {
+		return t.redirect(req)
+	}
+	return nil
+}
+
+// ServeHTTP routes and serves request, or serves
+// the not found handler if none of routes matches.
+func (m *Mux) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if h := m.Route(req); h != nil {
+		h.ServeHTTP(w, req)
+	} else if m.table.notFound != nil {
+		m.table.notFound.ServeHTTP(w, req)
+	} else {
+		http.NotFound(w, req)
+	}
+}
+
+// Patterns lists patterns of all the registered routes.
+func (m *Mux) Patterns() []string {
+	patterns, _ := Patterns(m.table.router)
+	return patterns
+}
+
+func (m *Mux) inspect() ([]RouteInfo, bool) {
+	return inspect(m.table.router)
+}
+
+// serves handler recovering panics, if there is
+// a panic handler
+func (t *muxTable) recovered(h http.Handler) http.Handler {
+	if t.panicHandler == nil {
+		return h
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		defer func() {
+			if r := recover(); r != nil {
+				Recycle(req)
+				t.panicHandler(w, req, r)
+			}
+		}()
+		h.ServeHTTP(w, req)
+	})
+}
+
+// methods of routes matching request path, probed by
+// a copy of the request, not to touch its parameters
+func (t *muxTable) allowed(req *http.Request) []string {
+	var allowed []string
+	probe := *req
+	for _, entry := range t.entries {
+		probe.Body = nil
+		if entry.methods == nil || entry.path.Route(&probe) == nil {
+			continue
+		}
+		Recycle(&probe)
+		for _, method := range entry.methods {
+			if !contains(allowed, method) {
+				allowed = append(allowed, method)
+			}
+		}
+	}
+	return allowed
+}
+
+func (t *muxTable) notAllowed(allowed []string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Allow", strings.Join(allowed, ", "))
+		if t.methodNotAllowed != nil {
+			t.methodNotAllowed.ServeHTTP(w, req)
+		} else {
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		}
+	})
+}
+
+// redirects request to the other trailing slash,
+// if any of routes matches it
+func (t *muxTable) redirect(req *http.Request) http.Handler {
+	path := req.URL.Path
+	switch trimmed := trimSlash(path); {
+	case trimmed != path:
+		path = trimmed
+	case strings.HasSuffix(path, "/"):
+		return nil // root or repeated slashes
+	default:
+		path += "/"
+	}
+
+	probe, u := *req, *req.URL
+	u.Path = path
+	probe.URL, probe.Body = &u, nil
+	if t.router.Route(&probe) == nil {
+		return nil
+	}
+	Recycle(&probe)
+
+	// empty segments may match parameters, but location
+	// like "//evil.com/" would refer to another host
+	location := u.RequestURI()
+	if strings.HasPrefix(location, "//") || strings.HasPrefix(location, "/\\") {
+		return nil
+	}
+
+	code := http.StatusMovedPermanently
+	if req.Method != "GET" && req.Method != "HEAD" {
+		code = 308 // Permanent Redirect
+	}
+	return http.RedirectHandler(location, code)
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,136 @@
+package fastroute_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+func TestMux(t *testing.T) {
+	t.Parallel()
+	handler := func(w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte(fastroute.Pattern(req) + " " + fastroute.Parameters(req).ByName("id")))
+	}
+
+	var recovered interface{}
+	mux := fastroute.NewMux(
+		fastroute.MuxNotFound(func(w http.ResponseWriter, req *http.Request) {
+			http.Error(w, "nothing here", http.StatusNotFound)
+		}),
+		fastroute.MuxPanicHandler(func(w http.ResponseWriter, req *http.Request, r interface{}) {
+			recovered = r
+			http.Error(w, "oops", http.StatusInternalServerError)
+		}),
+		fastroute.MuxTrailingSlash(fastroute.RedirectSlash),
+	)
+	mux.Handle("GET|HEAD", "/users/:id", handler)
+	mux.Handle("DELETE", "/users/:id", handler)
+	mux.Handle("", "/status", handler)
+	mux.Handle("GET", "/docs/", handler)
+	mux.Handle("GET", "/panic", func(w http.ResponseWriter, req *http.Request) {
+		panic("at the disco")
+	})
+	api := mux.Group("/api/v1")
+	api.Handle("POST", "orders", handler)
+	api.Group("admin").Handle("GET", "/orders/:id", handler)
+
+	cases := []struct {
+		method, path string
+		code         int
+		body, header string
+	}{
+		{"GET", "/users/5", 200, "/users/:id 5", ""},
+		{"DELETE", "/users/5", 200, "/users/:id 5", ""},
+		{"PUT", "/users/5", 405, "Method Not Allowed\n", "GET, HEAD, DELETE"},
+		{"PATCH", "/status", 200, "/status ", ""},
+		{"POST", "/api/v1/orders", 200, "/api/v1/orders ", ""},
+		{"GET", "/api/v1/orders", 405, "Method Not Allowed\n", "POST"},
+		{"GET", "/api/v1/admin/orders/7", 200, "/api/v1/admin/orders/:id 7", ""},
+		{"GET", "/users/5/", 301, "", "/users/5"},
+		{"DELETE", "/users/5/", 308, "", "/users/5"},
+		{"GET", "/docs", 301, "", "/docs/"},
+		{"GET", "/docs?page=2", 301, "", "/docs/?page=2"},
+		{"GET", "/missing", 404, "nothing here\n", ""},
+		{"GET", "/", 404, "nothing here\n", ""},
+		{"GET", "/panic", 500, "oops\n", ""},
+	}
+	for i, c := range cases {
+		req, _ := http.NewRequest(c.method, c.path, nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+
+		if w.Code != c.code || (c.body != "" && w.Body.String() != c.body) {
+			t.Fatalf("expected response: %d %q, but got: %d %q, case: %d", c.code, c.body, w.Code, w.Body.String(), i)
+		}
+		header := w.Header().Get("Allow")
+		if c.code/100 == 3 {
+			header = w.Header().Get("Location")
+		}
+		if header != c.header {
+			t.Fatalf("expected header %q, but got: %q, case: %d", c.header, header, i)
+		}
+	}
+	if recovered != "at the disco" {
+		t.Fatalf("expected panic to be recovered, but got: %v", recovered)
+	}
+
+	patterns, complete := fastroute.Patterns(mux)
+	expected := "/users/:id /users/:id /status /docs/ /panic /api/v1/orders /api/v1/admin/orders/:id"
+	if !complete || strings.Join(patterns, " ") != expected {
+		t.Fatalf("expected patterns %q, but got: %v %v", expected, patterns, complete)
+	}
+	if info := fastroute.Inspect(mux); strings.Join(info[1].Methods, "|") != "DELETE" || info[2].Methods != nil {
+		t.Fatalf("expected methods to be inspected, but got: %+v", info)
+	}
+
+	// nested in a composition, it falls through when nothing matches
+	router := fastroute.Chain(mux, fastroute.New("/*any", handler))
+	if actual := routed(router, "/missing"); actual != "/*any any=/missing" {
+		t.Fatalf("expected nested mux to fall through, but got: %s", actual)
+	}
+	hosted := fastroute.Host(":tenant.example.com", mux)
+	req, _ := http.NewRequest("PUT", "http://acme.example.com/users/5", nil)
+	w := httptest.NewRecorder()
+	hosted.ServeHTTP(w, req)
+	if w.Code != 405 {
+		t.Fatalf("expected hosted request to be not allowed, but got: %d", w.Code)
+	}
+}
+
+func TestMuxRedirectSlashStaysOnHost(t *testing.T) {
+	t.Parallel()
+	handler := func(w http.ResponseWriter, req *http.Request) {}
+	mux := fastroute.NewMux(fastroute.MuxTrailingSlash(fastroute.RedirectSlash))
+	mux.Handle("GET", "/:a/:b/", handler)
+
+	cases := map[string]string{
+		"/users/5":    "/users/5/",
+		"//evil.com":  "",
+		"/\\evil.com": "",
+	}
+	for path, location := range cases {
+		req, _ := http.NewRequest("GET", "http://localhost", nil)
+		req.URL.Path = path
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		if w.Header().Get("Location") != location {
+			t.Fatalf("expected %s to be redirected to %q, but got: %d %q", path, location, w.Code, w.Header().Get("Location"))
+		}
+	}
+}
+
+func TestMuxOptionalSlash(t *testing.T) {
+	t.Parallel()
+	handler := func(w http.ResponseWriter, req *http.Request) {}
+	mux := fastroute.NewMux(fastroute.MuxTrailingSlash(fastroute.OptionalSlash))
+	mux.Handle("GET", "/users/:id", handler)
+
+	for _, path := range []string{"/users/5", "/users/5/"} {
+		if actual := routed(mux, path); actual != "/users/:id id=5" {
+			t.Fatalf("expected %s to be routed regardless of trailing slash, but got: %s", path, actual)
+		}
+	}
+}
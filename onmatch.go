@@ -0,0 +1,57 @@
+package fastroute
+
+import "net/http"
+
+// OnMatch wraps router in order to call fn when a request
+// is matched, with its pattern and parameters, right before
+// the handler is served, for example to start a tracing span:
+//
+//	fastroute.OnMatch(routes, func(req *http.Request, pattern string, ps fastroute.Params) func() {
+//		span := tracer.Start(req.Context(), pattern)
+//		return span.End
+//	})
+//
+// The function fn returns, if not nil, is called after the
+// handler is served, even if it panics, and before parameters
+// are recycled, so ps may be read until then, but must not
+// be retained. The hook is not called for requests, which are
+// not served, like the ones claimed by a following router.
+// Serving the hook costs an allocation per request.
+func OnMatch(router Router, fn func(req *http.Request, pattern string, ps Params) func()) Router {
+	return wrapper{RouterFunc(func(req *http.Request) http.Handler {
+		h := router.Route(req)
+		if h == nil {
+			return nil
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			done := fn(req, Pattern(req), Parameters(req))
+			ps, _ := req.Body.(*parameters)
+			if ps == nil {
+				if done != nil {
+					defer done()
+				}
+				h.ServeHTTP(w, req)
+				return
+			}
+
+			// parameters recycled by route, once handler
+			// returns, are kept until the hook is done
+			alloc := ps.alloc
+			ps.alloc = nil
+			defer func() {
+				released := req.Body == ps.ReadCloser
+				if released {
+					req.Body = ps
+				}
+				if done != nil {
+					done()
+				}
+				ps.alloc = alloc
+				if released {
+					ps.reset(req)
+				}
+			}()
+			h.ServeHTTP(w, req)
+		})
+	}), router}
+}
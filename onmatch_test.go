@@ -0,0 +1,62 @@
+package fastroute_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+func TestOnMatch(t *testing.T) {
+	t.Parallel()
+
+	var events []string
+	router := fastroute.OnMatch(fastroute.Chain(
+		fastroute.New("/users/:id", func(w http.ResponseWriter, req *http.Request) {
+			events = append(events, "serve "+fastroute.Parameters(req).ByName("id"))
+		}),
+		fastroute.New("/panic", func(w http.ResponseWriter, req *http.Request) {
+			panic("failed")
+		}),
+		fastroute.New("/status", func(w http.ResponseWriter, req *http.Request) {
+			events = append(events, "serve status")
+		}),
+	), func(req *http.Request, pattern string, ps fastroute.Params) func() {
+		events = append(events, "match "+pattern+" "+ps.String())
+		return func() {
+			events = append(events, "done "+fastroute.Parameters(req).String())
+		}
+	})
+
+	cases := map[string]string{
+		"/users/5": `match /users/:id [id="5"], serve 5, done [id="5"]`,
+		"/status":  `match /status [], serve status, done []`,
+		"/panic":   `match /panic [], done []`,
+		"/other":   ``,
+	}
+	for path, expected := range cases {
+		events = nil
+		req, _ := http.NewRequest("GET", path, nil)
+		func() {
+			defer func() { recover() }()
+			router.ServeHTTP(httptest.NewRecorder(), req)
+		}()
+		if actual := strings.Join(events, ", "); actual != expected {
+			t.Fatalf("expected events of %s to be %q, but got %q", path, expected, actual)
+		}
+		if path != "/panic" && len(fastroute.Parameters(req)) != 0 {
+			t.Fatalf("expected parameters of %s to be recycled", path)
+		}
+	}
+
+	nested := fastroute.Host(":tenant.example.com", router)
+	req, _ := http.NewRequest("GET", "/users/7", nil)
+	req.Host = "acme.example.com"
+	events = nil
+	nested.ServeHTTP(httptest.NewRecorder(), req)
+	if actual := strings.Join(events, ", "); actual != `match /users/:id [tenant="acme" id="7"], serve 7, done [tenant="acme" id="7"]` {
+		t.Fatalf("unexpected events of nested router: %q", actual)
+	}
+}
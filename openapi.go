@@ -0,0 +1,239 @@
+package fastroute
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// OpenAPIOption configures FromOpenAPI.
+type OpenAPIOption func(*openAPI)
+
+type openAPI struct {
+	stub http.Handler
+}
+
+// OpenAPINotImplemented serves operations, which have
+// no handler given, with 501 Not Implemented, instead
+// of failing to build the router.
+func OpenAPINotImplemented() OpenAPIOption {
+	return func(o *openAPI) {
+		o.stub = http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			http.Error(w, "Not Implemented", http.StatusNotImplemented)
+		})
+	}
+}
+
+// FromOpenAPI builds router from OpenAPI 3 document in
+// JSON format. Every operation is routed by its path
+// template, converted to pattern, and method, to the
+// handler registered by its operationId:
+//
+//	/users/{id}   becomes   /users/:id
+//
+// Path parameters are constrained by their schema, where
+// it is trivial: integer type, uuid format and string
+// enums. Requests with parameters not satisfying their
+// schema are not matched.
+//
+// Paths are tried in lexical order, so literal segments
+// are tried before parameters at the same position, like
+// /users/me before /users/{id}.
+//
+// It returns an error if document cannot be parsed, a
+// path template cannot be converted, or an operation
+// has no handler, unless OpenAPINotImplemented is given,
+// or its handler is nil.
+func FromOpenAPI(spec []byte, handlers map[string]http.Handler, options ...OpenAPIOption) (Router, error) {
+	o := &openAPI{}
+	for _, option := range options {
+		option(o)
+	}
+
+	var doc struct {
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal(spec, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse openapi document: %s", err)
+	}
+
+	templates := make([]string, 0, len(doc.Paths))
+	for template := range doc.Paths {
+		templates = append(templates, template)
+	}
+	sort.Strings(templates)
+
+	var routes []Router
+	for _, template := range templates {
+		pattern, err := openAPIPattern(template)
+		if err != nil {
+			return nil, err
+		}
+
+		item := doc.Paths[template]
+		var shared []openAPIParam
+		if raw, ok := item["parameters"]; ok {
+			if err := json.Unmarshal(raw, &shared); err != nil {
+				return nil, fmt.Errorf("failed to parse parameters of path: %s - %s", template, err)
+			}
+		}
+
+		for _, method := range openAPIMethods {
+			raw, ok := item[strings.ToLower(method)]
+			if !ok {
+				continue
+			}
+			var op struct {
+				OperationID string         `json:"operationId"`
+				Parameters  []openAPIParam `json:"parameters"`
+			}
+			if err := json.Unmarshal(raw, &op); err != nil {
+				return nil, fmt.Errorf("failed to parse operation: %s %s - %s", method, template, err)
+			}
+
+			h, ok := handlers[op.OperationID]
+			if !ok && o.stub == nil {
+				return nil, fmt.Errorf("no handler for operation: %q - %s %s", op.OperationID, method, template)
+			} else if !ok {
+				h = o.stub
+			}
+
+			route, err := TryNew(pattern, h)
+			if err != nil {
+				return nil, fmt.Errorf("invalid operation: %q - %s", op.OperationID, err)
+			}
+			if checks := openAPIChecks(append(shared, op.Parameters...)); len(checks) > 0 {
+				route = constrain(route, checks)
+			}
+			routes = append(routes, MethodGroup(method, route))
+		}
+	}
+	return Chain(routes...), nil
+}
+
+var openAPIMethods = []string{"GET", "PUT", "POST", "DELETE", "OPTIONS", "HEAD", "PATCH", "TRACE"}
+
+type openAPIParam struct {
+	Name   string `json:"name"`
+	In     string `json:"in"`
+	Schema struct {
+		Type   string        `json:"type"`
+		Format string        `json:"format"`
+		Enum   []interface{} `json:"enum"`
+	} `json:"schema"`
+}
+
+// converts path template to pattern
+func openAPIPattern(template string) (string, error) {
+	segments := strings.Split(template, "/")
+	for i, seg := range segments {
+		open, end := strings.IndexByte(seg, '{'), strings.IndexByte(seg, '}')
+		if open == -1 && end == -1 {
+			segments[i] = escapeSigns(seg)
+			continue
+		}
+		if open != 0 || end < 2 || strings.IndexAny(seg[1:end], "{:*") != -1 || strings.IndexAny(seg[end+1:], "{}") != -1 {
+			return "", fmt.Errorf("path template cannot be converted to pattern: %s", template)
+		}
+		segments[i] = ":" + seg[1:end]
+		if suffix := seg[end+1:]; suffix != "" {
+			segments[i] += "\\" + suffix[:1] + escapeSigns(suffix[1:]) // like {id}:cancel
+		}
+	}
+	return strings.Join(segments, "/"), nil
+}
+
+// escapes signs in literal segment of path template,
+// including question mark, which starts query section
+func escapeSigns(seg string) string {
+	if strings.IndexAny(seg, ":*\\?") == -1 {
+		return seg
+	}
+	buf := make([]byte, 0, len(seg)+2)
+	for i := 0; i < len(seg); i++ {
+		if c := seg[i]; c == ':' || c == '*' || c == '\\' || c == '?' {
+			buf = append(buf, '\\')
+		}
+		buf = append(buf, seg[i])
+	}
+	return string(buf)
+}
+
+// checks of path parameters, derived from their schema
+func openAPIChecks(params []openAPIParam) map[string]func(string) bool {
+	checks := make(map[string]func(string) bool)
+	for _, param := range params {
+		if param.In != "path" {
+			continue
+		}
+		switch schema := param.Schema; {
+		case len(schema.Enum) > 0:
+			allowed := make(map[string]bool, len(schema.Enum))
+			for _, v := range schema.Enum {
+				allowed[fmt.Sprint(v)] = true
+			}
+			checks[param.Name] = func(s string) bool { return allowed[s] }
+		case schema.Type == "integer":
+			checks[param.Name] = isInteger
+		case schema.Format == "uuid":
+			checks[param.Name] = isUUID
+		}
+	}
+	return checks
+}
+
+// scopes router to requests, which parameters
+// satisfy the checks
+func constrain(router Router, checks map[string]func(string) bool) Router {
+	return wrapper{RouterFunc(func(req *http.Request) http.Handler {
+		h := router.Route(req)
+		if h == nil {
+			return nil
+		}
+		params := Parameters(req)
+		for name, check := range checks {
+			if !check(params.ByName(name)) {
+				Recycle(req)
+				return nil
+			}
+		}
+		return h
+	}), router}
+}
+
+func isInteger(s string) bool {
+	if len(s) > 0 && (s[0] == '-' || s[0] == '+') {
+		s = s[1:]
+	}
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func isUUID(s string) bool {
+	if len(s) != 36 {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch i {
+		case 8, 13, 18, 23:
+			if c != '-' {
+				return false
+			}
+		default:
+			if !('0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F') {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,154 @@
+package fastroute_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+const petstore = `{
+	"openapi": "3.0.0",
+	"paths": {
+		"/pets/{petId}": {
+			"parameters": [{"name": "petId", "in": "path", "schema": {"type": "integer"}}],
+			"get": {"operationId": "getPet"},
+			"delete": {"operationId": "deletePet"}
+		},
+		"/pets": {
+			"get": {"operationId": "listPets"},
+			"post": {"operationId": "createPet"}
+		},
+		"/pets/mine": {
+			"get": {"operationId": "myPets"}
+		},
+		"/owners/{ownerId}/pets/{kind}": {
+			"get": {
+				"operationId": "ownerPets",
+				"parameters": [
+					{"name": "ownerId", "in": "path", "schema": {"type": "string", "format": "uuid"}},
+					{"name": "kind", "in": "path", "schema": {"type": "string", "enum": ["cat", "dog"]}},
+					{"name": "limit", "in": "query", "schema": {"type": "integer"}}
+				]
+			}
+		}
+	}
+}`
+
+func TestFromOpenAPI(t *testing.T) {
+	t.Parallel()
+
+	handlers := make(map[string]http.Handler)
+	for _, id := range []string{"getPet", "deletePet", "listPets", "myPets", "ownerPets"} {
+		id := id
+		handlers[id] = http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			w.Write([]byte(id))
+		})
+	}
+
+	if _, err := fastroute.FromOpenAPI([]byte(petstore), handlers); err == nil || !strings.Contains(err.Error(), "createPet") {
+		t.Fatalf("expected an error for operation without handler, but got: %v", err)
+	}
+
+	router, err := fastroute.FromOpenAPI([]byte(petstore), handlers, fastroute.OpenAPINotImplemented())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		method, path string
+		code         int
+		body         string
+	}{
+		{"GET", "/pets", 200, "listPets"},
+		{"POST", "/pets", 501, "Not Implemented\n"},
+		{"GET", "/pets/mine", 200, "myPets"},
+		{"GET", "/pets/12", 200, "getPet"},
+		{"DELETE", "/pets/12", 200, "deletePet"},
+		{"GET", "/pets/twelve", 404, "404 page not found\n"},
+		{"PUT", "/pets/12", 404, "404 page not found\n"},
+		{"GET", "/owners/0b7a4c5e-4b7e-4a53-9c5e-3f2b8f6d9a10/pets/cat", 200, "ownerPets"},
+		{"GET", "/owners/0b7a4c5e-4b7e-4a53-9c5e-3f2b8f6d9a10/pets/bird", 404, "404 page not found\n"},
+		{"GET", "/owners/42/pets/dog", 404, "404 page not found\n"},
+	}
+
+	for i, c := range cases {
+		req, err := http.NewRequest(c.method, c.path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != c.code || w.Body.String() != c.body {
+			t.Fatalf("expected response: %d %q, but got: %d %q, case: %d", c.code, c.body, w.Code, w.Body.String(), i)
+		}
+		if fastroute.Parameters(req) != nil {
+			t.Fatalf("expected parameters to be recycled, case: %d", i)
+		}
+	}
+
+	if patterns, _ := fastroute.Patterns(router); len(patterns) != 6 || patterns[0] != "/owners/:ownerId/pets/:kind" {
+		t.Fatalf("expected patterns to be converted, but got: %v", patterns)
+	}
+}
+
+func TestFromOpenAPIEscapesSigns(t *testing.T) {
+	t.Parallel()
+	spec := `{"paths": {
+		"/jobs/{id}:cancel": {"post": {"operationId": "cancelJob"}},
+		"/files/{name}.json": {"get": {"operationId": "file"}},
+		"/search/*": {"get": {"operationId": "searchAll"}}
+	}}`
+	handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte(fastroute.Parameters(req).ByName("id") + fastroute.Parameters(req).ByName("name")))
+	})
+	router, err := fastroute.FromOpenAPI([]byte(spec), map[string]http.Handler{"cancelJob": handler, "file": handler, "searchAll": handler})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{`/files/:name\.json`, `/jobs/:id\:cancel`, `/search/\*`}
+	if patterns, _ := fastroute.Patterns(router); !reflect.DeepEqual(patterns, expected) {
+		t.Fatalf("expected patterns: %v, but got: %v", expected, patterns)
+	}
+	cases := []struct {
+		method, path, body string
+	}{
+		{"POST", "/jobs/42:cancel", "42"},
+		{"GET", "/files/a.json", "a"},
+		{"GET", "/search/*", ""},
+	}
+	for _, c := range cases {
+		req, _ := http.NewRequest(c.method, c.path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != 200 || w.Body.String() != c.body {
+			t.Fatalf("expected path: %s to respond %q, but got: %d %q", c.path, c.body, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestFromOpenAPIErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, spec := range []string{
+		`{"paths": `,
+		`{"paths": {"/files/{name}{ext}": {"get": {"operationId": "file"}}}}`,
+		`{"paths": {"/files/{}": {"get": {"operationId": "file"}}}}`,
+		`{"paths": {"/files/{na:me}": {"get": {"operationId": "file"}}}}`,
+		`{"paths": {"/files/x{name}": {"get": {"operationId": "file"}}}}`,
+	} {
+		if _, err := fastroute.FromOpenAPI([]byte(spec), nil, fastroute.OpenAPINotImplemented()); err == nil {
+			t.Fatalf("expected an error for spec: %s", spec)
+		}
+	}
+
+	spec := `{"paths": {"/files/{name}": {"get": {"operationId": "file"}}}}`
+	_, err := fastroute.FromOpenAPI([]byte(spec), map[string]http.Handler{"file": nil})
+	if err == nil || err.Error() != `invalid operation: "file" - given handler cannot be: nil` {
+		t.Fatalf("expected an error for nil handler, but got: %v", err)
+	}
+}
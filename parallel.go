@@ -0,0 +1,147 @@
+package fastroute
+
+import (
+	"net/http"
+	"sync"
+	"sync/atomic"
+)
+
+// ChainParallel chains routes into single Router, like
+// Chain, but splits them to the given number of groups,
+// which are matched concurrently. The first matching route,
+// in the order routes are given, still wins. Groups following
+// a matched one stop matching and parameters bound by their
+// routes are recycled.
+//
+// Groups are matched by a pool of workers, started along
+// with the router, which live as long as the process does.
+// A group is matched by the goroutine routing the request,
+// when all the workers are busy, so there are at most as
+// many goroutines matching as workers, plus the requests
+// being routed. Every group matches its own copy of request,
+// having URL and Header copied as well, so routers changing
+// them while routing, like StripPrefixRouter, do not race.
+//
+// It only pays off when matching itself is expensive, like
+// routes having regular expression constraints, and there
+// are idle CPUs to spare. For routes created by New, Chain
+// is faster by far. If workers is less than 2, it is the
+// same as Chain. Requests having parameters bound by an
+// outer router, like Host, are matched sequentially.
+func ChainParallel(workers int, routes ...Router) Router {
+	if workers < 2 || len(routes) < 2 {
+		return Chain(routes...)
+	}
+	if workers > len(routes) {
+		workers = len(routes)
+	}
+	size := (len(routes) + workers - 1) / workers
+	workers = (len(routes) + size - 1) / size
+	sequential := Chain(routes...)
+
+	groups := make([][]Router, workers)
+	for w := range groups {
+		end := (w + 1) * size
+		if end > len(routes) {
+			end = len(routes)
+		}
+		groups[w] = routes[w*size : end]
+	}
+
+	jobs := make(chan parallelJob)
+	for w := 1; w < workers; w++ {
+		go func() {
+			for job := range jobs {
+				job.run()
+			}
+		}()
+	}
+
+	return chain{RouterFunc(func(req *http.Request) http.Handler {
+		if boundParams(req) != nil {
+			return sequential.Route(req) // routes would share bound parameters
+		}
+		m := &parallelMatch{
+			req:      req,
+			matched:  make([]*http.Request, workers),
+			handlers: make([]http.Handler, workers),
+			first:    int32(workers),
+		}
+		m.wg.Add(workers)
+		w := 1
+		for ; w < workers; w++ {
+			select {
+			case jobs <- parallelJob{m, w, groups[w]}:
+				continue
+			default:
+			}
+			break // all workers are busy
+		}
+		parallelJob{m, 0, groups[0]}.run()
+		for ; w < workers; w++ {
+			parallelJob{m, w, groups[w]}.run()
+		}
+		return m.wait()
+	}), routes}
+}
+
+// state of request matched by groups concurrently
+type parallelMatch struct {
+	req      *http.Request
+	matched  []*http.Request
+	handlers []http.Handler
+	first    int32 // the first group, which matched
+	wg       sync.WaitGroup
+}
+
+type parallelJob struct {
+	m     *parallelMatch
+	w     int
+	group []Router
+}
+
+func (j parallelJob) run() {
+	m := j.m
+	defer m.wg.Done()
+
+	r := *m.req
+	u := *m.req.URL
+	r.URL = &u
+	r.Header = make(http.Header, len(m.req.Header))
+	for key, values := range m.req.Header {
+		r.Header[key] = append([]string(nil), values...)
+	}
+
+	for _, router := range j.group {
+		if atomic.LoadInt32(&m.first) < int32(j.w) {
+			return // preceding group matched
+		}
+		if h := router.Route(&r); h != nil {
+			m.matched[j.w], m.handlers[j.w] = &r, h
+			for f := atomic.LoadInt32(&m.first); f > int32(j.w); f = atomic.LoadInt32(&m.first) {
+				if atomic.CompareAndSwapInt32(&m.first, f, int32(j.w)) {
+					break
+				}
+			}
+			return
+		}
+	}
+}
+
+// waits for all the groups and takes the handler
+// of the first one matched, recycling the others
+func (m *parallelMatch) wait() http.Handler {
+	m.wg.Wait()
+	var handler http.Handler
+	for w, h := range m.handlers {
+		switch {
+		case h == nil:
+		case handler == nil:
+			m.req.Body = m.matched[w].Body // take parameters bound by the route
+			handler = h
+		default:
+			Recycle(m.matched[w])
+		}
+	}
+	return handler
+}
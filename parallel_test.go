@@ -0,0 +1,101 @@
+package fastroute_test
+
+import (
+	"fmt"
+	"net/http"
+	"path"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+func TestChainParallel(t *testing.T) {
+	t.Parallel()
+	handler := func(w http.ResponseWriter, req *http.Request) {}
+
+	routes := []fastroute.Router{
+		fastroute.New("/users/me", handler),
+		fastroute.New("/users/:id", handler),
+		fastroute.New("/users/:id/posts", handler),
+		fastroute.New("/:kind/:id", handler),
+		fastroute.New("/status", handler),
+		fastroute.New("/*any", handler),
+		fastroute.New("/files/*path", handler),
+	}
+	paths := []string{"/users/me", "/users/5", "/users/5/posts", "/orders/5", "/status", "/files/a", "/"}
+
+	sequential := fastroute.Chain(routes...)
+	for workers := 0; workers <= len(routes)+1; workers++ {
+		parallel := fastroute.ChainParallel(workers, routes...)
+		for _, path := range paths {
+			for i := 0; i < 10; i++ {
+				if expected, actual := routed(sequential, path), routed(parallel, path); expected != actual {
+					t.Fatalf("expected %s to be routed as %q, but got %q, workers: %d", path, expected, actual, workers)
+				}
+			}
+		}
+	}
+
+	// parameters bound by outer router
+	hosted := fastroute.Host(":tenant.example.com", fastroute.ChainParallel(3, routes...))
+	req, _ := http.NewRequest("GET", "http://acme.example.com/orders/5", nil)
+	if hosted.Route(req) == nil || fastroute.Pattern(req) != "/:kind/:id" || len(fastroute.Parameters(req)) != 3 {
+		t.Fatalf("expected hosted route to match, but got: %s %v", fastroute.Pattern(req), fastroute.Parameters(req))
+	}
+	fastroute.Recycle(req)
+
+	// groups change path of their own request copies
+	rewriting := fastroute.ChainParallel(3,
+		fastroute.StripPrefixRouter("/api", fastroute.New("/v1/:id", handler)),
+		fastroute.WithPathDecoding(fastroute.RawPath, fastroute.New("/api/raw/:id", handler)),
+		fastroute.StripPrefixRouter("/api", fastroute.New("/v2/:id", handler)),
+		fastroute.WithFormatSuffix(fastroute.New("/api/v3/:id", handler), "json"),
+	)
+	for _, path := range []string{"/api/v2/5", "/api/v3/5.json", "/api/v1/5", "/api/raw/5"} {
+		for i := 0; i < 10; i++ {
+			req, _ := http.NewRequest("GET", path, nil)
+			if rewriting.Route(req) == nil || req.URL.Path != path {
+				t.Fatalf("expected %s to be routed without changing request path, but got: %s", path, req.URL.Path)
+			}
+			fastroute.Recycle(req)
+		}
+	}
+}
+
+// routes constrained by regular expression, which are
+// expensive to match
+func constrainedRoutes(n int) []fastroute.Router {
+	handler := func(w http.ResponseWriter, req *http.Request) {}
+	routes := make([]fastroute.Router, n)
+	for i := range routes {
+		route := fastroute.New(fmt.Sprintf("/items%d/:sku", i), handler)
+		sku := regexp.MustCompile(`^[A-Z]{3}-[0-9]{4,8}-(x|y|z)+$`)
+		routes[i] = fastroute.RouterFunc(func(req *http.Request) http.Handler {
+			if sku.MatchString(path.Base(req.URL.Path)) {
+				return route.Route(req)
+			}
+			return nil
+		})
+	}
+	return routes
+}
+
+func Benchmark_100ConstrainedRoutes_Chain(b *testing.B) {
+	router := fastroute.Chain(constrainedRoutes(100)...)
+	req, _ := http.NewRequest("GET", "/items99/ABC-123456-xyz", nil)
+	benchmark(b, router, req)
+}
+
+func Benchmark_100ConstrainedRoutes_Parallel(b *testing.B) {
+	router := fastroute.ChainParallel(4, constrainedRoutes(100)...)
+	req, _ := http.NewRequest("GET", "/items99/ABC-123456-xyz", nil)
+	benchmark(b, router, req)
+}
+
+func Benchmark_100Routes_Parallel(b *testing.B) {
+	routes, pat := generateRoutes(100, 10)
+	router := fastroute.ChainParallel(4, routes...)
+	req, _ := http.NewRequest("GET", pat, nil)
+	benchmark(b, router, req)
+}
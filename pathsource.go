@@ -0,0 +1,95 @@
+package fastroute
+
+import (
+	"net/http"
+	"path"
+	"strings"
+)
+
+// URLPath is the path source of request URL path,
+// which routes match by default, see WithPathSources.
+func URLPath(req *http.Request) string {
+	return req.URL.Path
+}
+
+// HeaderPath creates a path source, which reads request
+// path from header, like "X-Forwarded-Path", set by a proxy,
+// see WithPathSources. The value is taken as a decoded path,
+// which is cleaned of dot segments and repeated slashes,
+// keeping the trailing slash. Values, which do not start
+// with a slash, are skipped.
+//
+// Clients may send the header themselves, in order to reach
+// routes, which a proxy protects by path. It must only be
+// trusted, when every request passes a proxy overwriting it.
+func HeaderPath(name string) func(*http.Request) string {
+	return func(req *http.Request) string {
+		p := req.Header.Get(name)
+		if !strings.HasPrefix(p, "/") {
+			return ""
+		}
+		cleaned := path.Clean(p)
+		if p[len(p)-1] == '/' && cleaned != "/" {
+			cleaned += "/"
+		}
+		return cleaned
+	}
+}
+
+// WithPathSources wraps router in order to match request
+// by the paths of sources, tried in the given order, until
+// one of them is matched, for example behind proxies, which
+// set different headers:
+//
+//	fastroute.WithPathSources(routes,
+//		fastroute.HeaderPath("X-Forwarded-Path"),
+//		fastroute.HeaderPath("X-Original-URI"),
+//		fastroute.URLPath,
+//	)
+//
+// Sources returning an empty path are skipped. Request URL
+// path is not tried, unless URLPath is listed. Each source
+// costs a match attempt of router, so a request, which none
+// of them match, is matched as many times as there are
+// sources. Without sources, router is returned as it is.
+//
+// Handler is served with URL path set to the matched one,
+// so it reads the same path as parameters and Pattern were
+// matched by. URL path and RawPath are restored once it is
+// served, as well as after the request is routed.
+func WithPathSources(router Router, sources ...func(*http.Request) string) Router {
+	if len(sources) == 0 {
+		return router
+	}
+	return wrapper{RouterFunc(func(req *http.Request) http.Handler {
+		path, rawPath := req.URL.Path, req.URL.RawPath
+		for _, source := range sources {
+			p := source(req)
+			if p == "" {
+				continue
+			}
+			if p == path {
+				if h := router.Route(req); h != nil {
+					return h
+				}
+				continue
+			}
+
+			req.URL.Path, req.URL.RawPath = p, ""
+			h := router.Route(req)
+			req.URL.Path, req.URL.RawPath = path, rawPath
+			if h == nil {
+				continue
+			}
+			return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				path, rawPath := req.URL.Path, req.URL.RawPath
+				req.URL.Path, req.URL.RawPath = p, ""
+				defer func() {
+					req.URL.Path, req.URL.RawPath = path, rawPath
+				}()
+				h.ServeHTTP(w, req)
+			})
+		}
+		return nil
+	}), router}
+}
@@ -0,0 +1,62 @@
+package fastroute_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+func TestWithPathSources(t *testing.T) {
+	t.Parallel()
+
+	handler := func(w http.ResponseWriter, req *http.Request) {
+		fmt.Fprintf(w, "%s %s %s", req.URL.Path, fastroute.Pattern(req), fastroute.Parameters(req))
+	}
+	router := fastroute.WithPathSources(fastroute.Chain(
+		fastroute.New("/users/:id", handler),
+		fastroute.New("/status", handler),
+	),
+		fastroute.HeaderPath("X-Forwarded-Path"),
+		fastroute.HeaderPath("X-Original-URI"),
+		fastroute.URLPath,
+	)
+
+	cases := []struct {
+		forwarded, original, path string
+		body                      string
+	}{
+		{"/users/5", "/status", "/other", `/users/5 /users/:id [id="5"]`},
+		{"/missing", "/status", "/users/5", `/status /status []`},
+		{"", "/users/7", "/status", `/users/7 /users/:id [id="7"]`},
+		{"/missing", "/other", "/users/9", `/users/9 /users/:id [id="9"]`},
+		{"/missing", "", "/other", "404 page not found\n"},
+		{"users/5", "/status", "/other", `/status /status []`},
+		{"/public/../users/5", "", "/other", `/users/5 /users/:id [id="5"]`},
+		{"//users//6", "", "/other", `/users/6 /users/:id [id="6"]`},
+	}
+	for i, c := range cases {
+		req, _ := http.NewRequest("GET", c.path, nil)
+		if c.forwarded != "" {
+			req.Header.Set("X-Forwarded-Path", c.forwarded)
+		}
+		if c.original != "" {
+			req.Header.Set("X-Original-URI", c.original)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if actual := w.Body.String(); actual != c.body {
+			t.Fatalf("expected response %q, but got %q, at case %d", c.body, actual, i)
+		}
+		if req.URL.Path != c.path {
+			t.Fatalf("expected request path to be restored, but got %s, at case %d", req.URL.Path, i)
+		}
+	}
+
+	headerOnly := fastroute.WithPathSources(fastroute.New("/status", handler), fastroute.HeaderPath("X-Forwarded-Path"))
+	if actual := routed(headerOnly, "/status"); actual != "no match" {
+		t.Fatalf("expected URL path not to be tried, unless listed, but got %q", actual)
+	}
+}
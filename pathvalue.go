@@ -0,0 +1,33 @@
+//go:build go1.22
+// +build go1.22
+
+package fastroute
+
+import "net/http"
+
+// WithPathValues wraps router in order to set parameters of
+// the matched request by SetPathValue, before the handler
+// is served, so it reads them the way handlers of Go 1.22
+// http.ServeMux do:
+//
+//	id := req.PathValue("id")
+//
+// Values stay set on request after parameters are recycled.
+// The first value of a repeated key is set, like ByName finds.
+// Setting values costs an allocation per request having
+// parameters.
+func WithPathValues(router Router) Router {
+	return wrapper{RouterFunc(func(req *http.Request) http.Handler {
+		h := router.Route(req)
+		if h == nil {
+			return nil
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			ps := Parameters(req)
+			for i := len(ps) - 1; i >= 0; i-- {
+				req.SetPathValue(ps[i].Key, ps[i].Value)
+			}
+			h.ServeHTTP(w, req)
+		})
+	}), router}
+}
@@ -0,0 +1,40 @@
+//go:build go1.22
+// +build go1.22
+
+package fastroute_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+func TestWithPathValues(t *testing.T) {
+	t.Parallel()
+
+	handler := func(w http.ResponseWriter, req *http.Request) {
+		fmt.Fprintf(w, "%q %q %q", req.PathValue("id"), req.PathValue("tag"), req.PathValue("missing"))
+	}
+	router := fastroute.WithPathValues(fastroute.Chain(
+		fastroute.New("/users/:id", handler),
+		fastroute.New("/search?tag=:tag", handler),
+		fastroute.New("/status", handler),
+	))
+
+	cases := map[string]string{
+		"/users/5":            `"5" "" ""`,
+		"/search?tag=a&tag=b": `"" "a" ""`,
+		"/status":             `"" "" ""`,
+	}
+	for path, expected := range cases {
+		req, _ := http.NewRequest("GET", path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if actual := w.Body.String(); actual != expected {
+			t.Fatalf("expected path values of %s to be %s, but got %s", path, expected, actual)
+		}
+	}
+}
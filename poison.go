@@ -0,0 +1,31 @@
+//go:build fastroutedebug
+// +build fastroutedebug
+
+package fastroute
+
+import (
+	"net/http"
+	"runtime/debug"
+)
+
+// Recycled prefixes values of parameters, which were put
+// back to the pool, followed by the stack recycling them.
+const Recycled = "†recycled†"
+
+// overwrites values of recycled parameters, so stale
+// reads are obvious and tell where they were recycled
+func poison(ps Params) {
+	if len(ps) == 0 {
+		return
+	}
+	value := Recycled + " at\n" + string(debug.Stack())
+	for i := range ps {
+		ps[i].Value = value
+	}
+}
+
+// a matched handler served twice, or for another request,
+// panics in debug builds, so the misuse is found in tests
+func unclaimed(w http.ResponseWriter, req *http.Request) {
+	panic("fastroute: matched handler served twice, its parameters were already recycled")
+}
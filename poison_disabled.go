@@ -0,0 +1,34 @@
+//go:build !fastroutedebug
+// +build !fastroutedebug
+
+package fastroute
+
+import "net/http"
+
+// Recycled prefixes values of parameters, which were put back
+// to the pool, when built with fastroutedebug tag:
+//
+//	go test -tags fastroutedebug ./...
+//
+// Handlers retaining Parameters(req) after being served, for
+// example a goroutine streaming events, read values of other
+// requests once the parameters are reused. In debug builds
+// recycled values are overwritten by Recycled followed by the
+// stack, which recycled them, so a stale ByName call returns
+// an obviously wrong value telling where it was recycled. Keys
+// are kept, so lookups by name still reach the poisoned value.
+//
+// Poisoning only exposes stale reads, which happen before the
+// parameters are drawn from the pool again. Without the tag,
+// nothing is overwritten and recycling costs nothing extra.
+const Recycled = "†recycled†"
+
+// recycled parameters are poisoned only in debug builds
+func poison(ps Params) {}
+
+// a matched handler served twice, or for another request,
+// cannot serve parameters, which may belong to another
+// request by now, so it is responded as a server error
+func unclaimed(w http.ResponseWriter, req *http.Request) {
+	http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+}
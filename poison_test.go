@@ -0,0 +1,50 @@
+//go:build fastroutedebug
+// +build fastroutedebug
+
+package fastroute_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+func init() {
+	poisoning = true
+}
+
+func TestPoisonsRecycledParameters(t *testing.T) {
+	t.Parallel()
+
+	// handler streams events from a goroutine, which outlives
+	// the request and keeps reading its parameters
+	stale := make(chan fastroute.Params, 1)
+	router := fastroute.New("/events/:channel", func(w http.ResponseWriter, req *http.Request) {
+		stale <- fastroute.Parameters(req)
+	})
+
+	req, err := http.NewRequest("GET", "/events/news", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	value := (<-stale).ByName("channel")
+	if !strings.HasPrefix(value, fastroute.Recycled) {
+		t.Fatalf("expected stale parameter to be poisoned, but got: %q", value)
+	}
+	if !strings.Contains(value, "fastroute.(*parameters).reset") {
+		t.Fatalf("expected poisoned value to tell where it was recycled, but got: %q", value)
+	}
+
+	// parameters attached by WithParams are not pooled
+	params := fastroute.Params{{"channel", "news"}}
+	req = fastroute.WithParams(req, params)
+	fastroute.Recycle(req)
+	if params.ByName("channel") != "news" {
+		t.Fatalf("expected attached parameters to be intact, but got: %v", params)
+	}
+}
@@ -0,0 +1,202 @@
+package fastroute
+
+import "sync"
+
+// ParamSet holds parameters bound to a request by a
+// route, which are drawn from ParamAllocator.
+type ParamSet parameters
+
+// NewParamSet creates ParamSet having capacity for n
+// parameters, for ParamAllocator implementations.
+func NewParamSet(n int) *ParamSet {
+	return &ParamSet{params: make(Params, 0, n)}
+}
+
+// ParamAllocator provides dynamic routes with sets of
+// parameters, see WithParamAllocator. By default, every
+// route draws them from its own sync.Pool.
+//
+// Get returns a set for the route of the given pattern,
+// which must be empty and have capacity for n parameters
+// the route binds at most, otherwise binding them would
+// allocate. Put takes back the set once the request was
+// served, or the route did not match, so it may be reused.
+// Both may be called concurrently.
+type ParamAllocator interface {
+	Get(pattern string, n int) *ParamSet
+	Put(*ParamSet)
+}
+
+// pool of parameters of the given capacity,
+// the default ParamAllocator. Keys, if any, are
+// written to the backing array of every set it
+// creates, in the order route binds them
+type paramsPool struct {
+	sync.Pool
+	size int
+	keys []string
+}
+
+func newParamsPool(size int, keys ...string) *paramsPool {
+	pp := &paramsPool{size: size, keys: keys}
+	pp.New = func() interface{} {
+		params := make(Params, len(pp.keys), pp.size)
+		for i, key := range pp.keys {
+			params[i].Key = key
+		}
+		return &parameters{params: params[:0], alloc: pp}
+	}
+	return pp
+}
+
+func (pp *paramsPool) Get(pattern string, n int) *ParamSet {
+	return (*ParamSet)(pp.get())
+}
+
+func (pp *paramsPool) Put(ps *ParamSet) {
+	pp.Pool.Put((*parameters)(ps))
+}
+
+func (pp *paramsPool) get() *parameters {
+	return pp.Pool.Get().(*parameters)
+}
+
+// NoReuseAllocator creates ParamAllocator, which never
+// reuses parameters, for debugging. Every set is newly
+// allocated and the recycled ones are left to garbage
+// collector, so handlers retaining Parameters(req) after
+// being served keep reading their own values. Comparing
+// behavior with the default allocator tells whether a bug
+// is caused by parameters reused too early.
+func NoReuseAllocator() ParamAllocator {
+	return noReuse{}
+}
+
+type noReuse struct{}
+
+func (noReuse) Get(pattern string, n int) *ParamSet {
+	return NewParamSet(n)
+}
+
+func (noReuse) Put(*ParamSet) {}
+
+// allocator of dynamic route, and the number
+// of parameters the route needs
+type poolSource struct {
+	alloc ParamAllocator
+	num   int
+}
+
+// SharePool makes all dynamic routes of router draw
+// parameters from a single pool, sized for the route
+// having most parameters, instead of a pool per route.
+//
+// With many dynamic routes, a request probing them
+// touches a single pool, which is warmed up once and
+// holds fewer idle parameters. Routes are reached the
+// same way Inspect enumerates them, opaque routers are
+// left intact. It must be called before serving requests
+// and returns the same router for convenience:
+//
+//	router := fastroute.SharePool(fastroute.Chain(routes...))
+func SharePool(router Router) Router {
+	var sources []*poolSource
+	var size int
+	walk(router, func(r route) {
+		if r.src == nil {
+			return
+		}
+		sources = append(sources, r.src)
+		if r.src.num > size {
+			size = r.src.num
+		}
+	})
+
+	shared := newParamsPool(size)
+	for _, src := range sources {
+		src.alloc = shared
+	}
+	return router
+}
+
+// WithParamAllocator makes all dynamic routes of router
+// draw parameters from alloc, instead of a pool per route,
+// for deployments needing another strategy, like
+// NoReuseAllocator for debugging. Routes are reached
+// the same way SharePool reaches them, parameters bound
+// by combinators, like Host, are still drawn from their
+// own pools. It must be called before serving requests
+// and returns the same router for convenience:
+//
+//	router := fastroute.WithParamAllocator(routes, fastroute.NoReuseAllocator())
+func WithParamAllocator(router Router, alloc ParamAllocator) Router {
+	walk(router, func(r route) {
+		if r.src != nil {
+			r.src.alloc = alloc
+		}
+	})
+	return router
+}
+
+// used internally by composite routers of this
+// package to reach the routers they consist of
+type composite interface {
+	children() []Router
+}
+
+func (w wrapper) children() []Router {
+	return []Router{w.router}
+}
+
+func (c chain) children() []Router {
+	return c.routes
+}
+
+func (s *Splitter) children() []Router {
+	return []Router{s.router}
+}
+
+// calls fn for every route reachable in router
+func walk(router Router, fn func(route)) {
+	switch t := router.(type) {
+	case route:
+		fn(t)
+	case templated:
+		fn(t.route)
+	case composite:
+		for _, child := range t.children() {
+			walk(child, fn)
+		}
+	}
+}
+
+// Preallocate primes parameter pools of all dynamic
+// routes of router with n parameters each, so the
+// first requests after start do not allocate them.
+// Shared pool is primed once, static routes are
+// skipped. Pools are not grown past n by repeated
+// calls.
+//
+// It should be called before serving requests, as
+// pools may still be emptied by garbage collection.
+func Preallocate(router Router, n int) {
+	primed := make(map[*paramsPool]bool)
+	walk(router, func(r route) {
+		if r.src == nil {
+			return
+		}
+		pool, ok := r.src.alloc.(*paramsPool)
+		if !ok || primed[pool] {
+			return // other allocators manage themselves
+		}
+		primed[pool] = true
+
+		ready := make([]*parameters, n)
+		for i := range ready {
+			ready[i] = pool.get()
+		}
+		for _, p := range ready {
+			pool.Pool.Put(p)
+		}
+	})
+}
@@ -0,0 +1,231 @@
+package fastroute_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+func TestSharePool(t *testing.T) {
+	t.Parallel()
+	handler := func(w http.ResponseWriter, req *http.Request) {
+		for _, p := range fastroute.Parameters(req) {
+			w.Write([]byte(p.Key + "=" + p.Value + ";"))
+		}
+	}
+
+	router := fastroute.SharePool(fastroute.Chain(
+		fastroute.New("/status", handler),
+		fastroute.New("/users/:id", handler),
+		fastroute.MethodGroup("GET", fastroute.New("/repos/:owner/:repo/issues/:number", handler)),
+		fastroute.Host("api.example.com", fastroute.New("/files/*path", handler)),
+		fastroute.NewTemplate("/v1/{name=shelves/*}", handler),
+	))
+
+	cases := map[string]string{
+		"/status":             "",
+		"/users/1":            "id=1;",
+		"/repos/a/b/issues/3": "owner=a;repo=b;number=3;",
+		"/files/docs/a.txt":   "path=/docs/a.txt;",
+		"/v1/shelves/s1":      "name=shelves/s1;",
+		"/users/1/none":       "404 page not found\n",
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for path, body := range cases {
+				req, _ := http.NewRequest("GET", "http://api.example.com"+path, nil)
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				if w.Body.String() != body {
+					t.Errorf("expected response: %q for: %s, but got: %q", body, path, w.Body.String())
+				}
+				if fastroute.Parameters(req) != nil {
+					t.Errorf("expected parameters to be recycled for: %s", path)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func Benchmark_100Routes_OwnPools(b *testing.B) {
+	benchmarkPools(b, 100, false)
+}
+
+func Benchmark_100Routes_SharedPool(b *testing.B) {
+	benchmarkPools(b, 100, true)
+}
+
+func Benchmark_1000Routes_OwnPools(b *testing.B) {
+	benchmarkPools(b, 1000, false)
+}
+
+func Benchmark_1000Routes_SharedPool(b *testing.B) {
+	benchmarkPools(b, 1000, true)
+}
+
+func benchmarkPools(b *testing.B, num int, shared bool) {
+	routes, pat := generateRoutes(num, 3)
+	pat = strings.Replace(pat, ":id", "param", 1)
+
+	router := fastroute.Chain(routes...)
+	if shared {
+		router = fastroute.SharePool(router)
+	}
+
+	req, err := http.NewRequest("GET", pat, nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	benchmark(b, router, req)
+}
+
+// not parallel, since it counts allocations
+func TestPreallocate(t *testing.T) {
+	if poisoning {
+		t.Skip("recycled parameters are poisoned in debug builds")
+	}
+	handler := func(w http.ResponseWriter, req *http.Request) {}
+	router := fastroute.Chain(
+		fastroute.New("/status", handler),
+		fastroute.New("/users/:id", handler),
+		fastroute.New("/users/:id/posts/:post", handler),
+	)
+	fastroute.Preallocate(router, 4)
+	fastroute.Preallocate(router, 4)
+
+	req, err := http.NewRequest("GET", "/users/1/posts/2", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := httptest.NewRecorder()
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	router.Route(req).ServeHTTP(w, req)
+	runtime.ReadMemStats(&after)
+
+	if allocs := after.Mallocs - before.Mallocs; allocs != 0 {
+		t.Fatalf("expected first request to perform no allocations, but got: %d", allocs)
+	}
+}
+
+// ring of preallocated parameter sets, for the
+// allocator of embedded targets
+type ringAllocator struct {
+	mu   sync.Mutex
+	sets chan *fastroute.ParamSet
+	gets map[string]int
+}
+
+func (r *ringAllocator) Get(pattern string, n int) *fastroute.ParamSet {
+	r.mu.Lock()
+	r.gets[pattern]++
+	r.mu.Unlock()
+	select {
+	case ps := <-r.sets:
+		return ps
+	default:
+		return fastroute.NewParamSet(n)
+	}
+}
+
+func (r *ringAllocator) Put(ps *fastroute.ParamSet) {
+	select {
+	case r.sets <- ps:
+	default:
+	}
+}
+
+func TestPrefilledKeys(t *testing.T) {
+	t.Parallel()
+	handler := func(w http.ResponseWriter, req *http.Request) {
+		for _, p := range fastroute.Parameters(req) {
+			w.Write([]byte(p.Key + "=" + p.Value + ";"))
+		}
+	}
+	routes := func() fastroute.Router {
+		return fastroute.Chain(
+			fastroute.New("/repos/:owner/:repo/files/*path", handler),
+			fastroute.Host(":tenant.example.com", fastroute.New("/orders/:id", handler)),
+		)
+	}
+
+	cases := []struct{ path, body string }{
+		{"/repos/a/b/files", "404 page not found\n"}, // binds part of parameters
+		{"/repos/a/b/files/c.go", "owner=a;repo=b;path=/c.go;"},
+		{"/orders/1", "tenant=acme;id=1;"},
+		{"/repos/c/d/files/e/f.go", "owner=c;repo=d;path=/e/f.go;"},
+	}
+	for _, router := range []fastroute.Router{
+		routes(), // own pools write keys once
+		fastroute.WithParamAllocator(routes(), fastroute.NoReuseAllocator()),
+	} {
+		for _, c := range cases {
+			req, _ := http.NewRequest("GET", "http://acme.example.com"+c.path, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			if w.Body.String() != c.body {
+				t.Fatalf("expected response: %q for: %s, but got: %q", c.body, c.path, w.Body.String())
+			}
+		}
+	}
+}
+
+func TestWithParamAllocator(t *testing.T) {
+	t.Parallel()
+	var retained fastroute.Params
+	handler := func(w http.ResponseWriter, req *http.Request) {
+		retained = fastroute.Parameters(req)
+		w.Write([]byte(fastroute.Pattern(req) + " " + retained.ByName("id")))
+	}
+	routes := func() fastroute.Router {
+		return fastroute.Chain(
+			fastroute.New("/status", handler),
+			fastroute.New("/users/:id", handler),
+			fastroute.Host(":tenant.example.com", fastroute.New("/orders/:id", handler)),
+		)
+	}
+
+	ring := &ringAllocator{sets: make(chan *fastroute.ParamSet, 2), gets: map[string]int{}}
+	router := fastroute.WithParamAllocator(routes(), ring)
+	for _, path := range []string{"/status", "/users/1", "/users/2", "/orders/3"} {
+		req, _ := http.NewRequest("GET", "http://acme.example.com"+path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if expected := path[:strings.LastIndex(path, "/")]; !strings.HasPrefix(w.Body.String(), expected) {
+			t.Fatalf("expected %s to be served, but got: %q", path, w.Body.String())
+		}
+		if fastroute.Parameters(req) != nil {
+			t.Fatalf("expected parameters to be recycled for: %s", path)
+		}
+	}
+	// hosted route binds to parameters of Host pool
+	if ring.gets["/users/:id"] != 3 || ring.gets["/orders/:id"] != 0 || len(ring.sets) != 1 {
+		t.Fatalf("expected sets to be drawn from allocator and put back, but got: %v, %d", ring.gets, len(ring.sets))
+	}
+
+	// parameters are never reused, so retained ones stay intact
+	if poisoning {
+		return // unless poisoned when recycled
+	}
+	router = fastroute.WithParamAllocator(routes(), fastroute.NoReuseAllocator())
+	req, _ := http.NewRequest("GET", "/users/1", nil)
+	router.ServeHTTP(httptest.NewRecorder(), req)
+	first := retained
+	req, _ = http.NewRequest("GET", "/users/2", nil)
+	router.ServeHTTP(httptest.NewRecorder(), req)
+	if len(first) != 1 || first.ByName("id") != "1" {
+		t.Fatalf("expected retained parameters not to be reused, but got: %v", first)
+	}
+}
@@ -0,0 +1,82 @@
+package fastroute
+
+import "sort"
+
+// Prioritized assigns priority to router, which is
+// used by ChainByPriority to order routes. Routers
+// not prioritized have priority 0.
+func Prioritized(priority int, router Router) Router {
+	return prioritized{wrapper{router.Route, router}, priority}
+}
+
+// ChainByPriority chains routes into single Router,
+// like Chain, but ordered by priority given to them
+// with Prioritized, highest first. Routes of the same
+// priority keep the order they were given in.
+//
+// It lets independent modules register their routes,
+// while a static route may still be given a priority
+// to be tried before a catch-all one:
+//
+//	fastroute.ChainByPriority(
+//		fastroute.New("/files/*path", serveFiles),
+//		fastroute.Prioritized(10, fastroute.New("/files/index.html", index)),
+//	)
+func ChainByPriority(routes ...Router) Router {
+	sorted := make(byPriority, len(routes))
+	copy(sorted, routes)
+	sort.Stable(sorted)
+	return Chain(sorted...)
+}
+
+type prioritized struct {
+	wrapper
+	priority int
+}
+
+type byPriority []Router
+
+func (p byPriority) Len() int           { return len(p) }
+func (p byPriority) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
+func (p byPriority) Less(i, j int) bool { return priority(p[i]) > priority(p[j]) }
+
+func priority(router Router) int {
+	if p, ok := router.(prioritized); ok {
+		return p.priority
+	}
+	return 0
+}
+
+// ChainStaticFirst chains routes into single Router,
+// like Chain, but tries all static routes before any
+// dynamic one, so /users/me is matched before
+// /users/:id, whatever the order they are given in.
+//
+// Routers are told apart by Inspect, a router is only
+// static if all its routes are. Opaque routers are
+// tried together with dynamic ones. Otherwise routes
+// keep the order they were given in.
+func ChainStaticFirst(routes ...Router) Router {
+	var static, dynamic []Router
+	for _, router := range routes {
+		if isStatic(router) {
+			static = append(static, router)
+		} else {
+			dynamic = append(dynamic, router)
+		}
+	}
+	return Chain(append(static, dynamic...)...)
+}
+
+func isStatic(router Router) bool {
+	routes, ok := inspect(router)
+	if !ok || len(routes) == 0 {
+		return false
+	}
+	for _, info := range routes {
+		if !info.Static {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,102 @@
+package fastroute_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+func TestChainByPriority(t *testing.T) {
+	t.Parallel()
+	handler := func(name string) http.HandlerFunc {
+		return func(w http.ResponseWriter, req *http.Request) {
+			w.Write([]byte(name))
+		}
+	}
+
+	router := fastroute.ChainByPriority(
+		fastroute.New("/files/*path", handler("files")),
+		fastroute.Prioritized(-1, fastroute.New("/*path", handler("fallback"))),
+		fastroute.New("/files/:name", handler("file")),
+		fastroute.Prioritized(10, fastroute.New("/files/index.html", handler("index"))),
+		fastroute.Prioritized(10, fastroute.New("/files/:name/", handler("dir"))),
+	)
+
+	expected := []string{"/files/index.html", "/files/:name/", "/files/*path", "/files/:name", "/*path"}
+	if patterns, _ := fastroute.Patterns(router); !reflect.DeepEqual(patterns, expected) {
+		t.Fatalf("expected routes ordered as: %v, but got: %v", expected, patterns)
+	}
+
+	cases := map[string]string{
+		"/files/index.html": "index",
+		"/files/docs/":      "dir",
+		"/files/a.txt":      "files",
+		"/other":            "fallback",
+	}
+	for path, body := range cases {
+		req, _ := http.NewRequest("GET", path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Body.String() != body {
+			t.Fatalf("expected %s to be served by: %s, but got: %s", path, body, w.Body.String())
+		}
+	}
+}
+
+func TestChainStaticFirst(t *testing.T) {
+	t.Parallel()
+	handler := func(w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte(fastroute.Pattern(req)))
+	}
+	routes := []fastroute.Router{
+		fastroute.New("/users/:id", handler),
+		fastroute.New("/files/*path", handler),
+		fastroute.RouterFunc(func(req *http.Request) http.Handler {
+			if req.URL.Path == "/opaque" {
+				return http.HandlerFunc(handler)
+			}
+			return nil
+		}),
+		fastroute.New("/users/me", handler),
+		fastroute.MethodGroup("GET", fastroute.New("/files/index.html", handler), fastroute.New("/", handler)),
+	}
+
+	router := fastroute.ChainStaticFirst(routes...)
+	expected := []string{"/users/me", "/files/index.html", "/", "/users/:id", "/files/*path"}
+	if patterns, _ := fastroute.Patterns(router); !reflect.DeepEqual(patterns, expected) {
+		t.Fatalf("expected routes ordered as: %v, but got: %v", expected, patterns)
+	}
+
+	// routes, which do not overlap, are matched as by Chain
+	chain := fastroute.Chain(routes...)
+	cases := map[string]string{
+		"/users/me":         "/users/me",
+		"/files/index.html": "/files/index.html",
+		"/users/5":          "/users/:id",
+		"/files/a.txt":      "/files/*path",
+		"/opaque":           "/opaque",
+		"/":                 "/",
+		"/none":             "404 page not found\n",
+	}
+	for path, body := range cases {
+		req, _ := http.NewRequest("GET", path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Body.String() != body {
+			t.Fatalf("expected %s to be served by: %q, but got: %q", path, body, w.Body.String())
+		}
+
+		if path == "/users/me" || path == "/files/index.html" {
+			continue // chain matches these by dynamic routes given first
+		}
+		req, _ = http.NewRequest("GET", path, nil)
+		w = httptest.NewRecorder()
+		chain.ServeHTTP(w, req)
+		if w.Body.String() != body {
+			t.Fatalf("expected chain to serve %s the same, but got: %q", path, w.Body.String())
+		}
+	}
+}
@@ -0,0 +1,215 @@
+//go:build go1.8
+// +build go1.8
+
+package fastroute
+
+import (
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+)
+
+// ProxyOption configures the reverse proxy created by
+// Proxy, for example to set its Transport.
+type ProxyOption func(*httputil.ReverseProxy)
+
+// Proxy creates a route for the pattern, which proxies
+// matched requests to upstream URL. Upstream is a URL
+// template, where parameters of the pattern are
+// substituted:
+//
+//	fastroute.Proxy("/tenants/:tenant/api/*rest", "http://:tenant.internal:8080/*rest")
+//
+// Parameters may be placed in the host, where their values
+// must consist of letters, digits, hyphens and dots, or
+// the request is rejected with 400 Bad Request. In the
+// path, named parameters are escaped as a single segment,
+// while catch-all parameters keep their slashes. Query of
+// the request is appended to the query of upstream.
+//
+// Besides X-Forwarded-For, set by httputil.ReverseProxy,
+// X-Forwarded-Host and X-Forwarded-Proto headers tell the
+// upstream the host and scheme of the original request.
+// Host header is set to the upstream host.
+//
+// It panics if upstream is not an absolute URL, or refers
+// to parameters not in pattern.
+func Proxy(pattern, upstream string, options ...ProxyOption) Router {
+	target := parseUpstream(pattern, upstream)
+
+	proxy := &httputil.ReverseProxy{Director: func(out *http.Request) {
+		proto := "http"
+		if out.TLS != nil {
+			proto = "https"
+		}
+		out.Header.Set("X-Forwarded-Host", out.Host)
+		out.Header.Set("X-Forwarded-Proto", proto)
+		out.Host = out.URL.Host
+	}}
+	for _, option := range options {
+		option(proxy)
+	}
+
+	return New(pattern, func(w http.ResponseWriter, req *http.Request) {
+		u, ok := target.expand(Parameters(req), req.URL.RawQuery)
+		if !ok {
+			http.Error(w, "Bad Request", http.StatusBadRequest)
+			return
+		}
+
+		// proxy the plain body, addressing upstream
+		body, original := req.Body, req.URL
+		req.Body, req.URL = requestBody(req), u
+		defer func() {
+			req.Body, req.URL = body, original
+		}()
+		proxy.ServeHTTP(w, req)
+	})
+}
+
+type upstreamTemplate struct {
+	scheme, host, query string
+	path                []string // segments, starting with a slash
+}
+
+func parseUpstream(pattern, upstream string) upstreamTemplate {
+	pos := strings.Index(upstream, "://")
+	if pos <= 0 {
+		panic("upstream must be an absolute URL: " + upstream)
+	}
+	t := upstreamTemplate{scheme: upstream[:pos]}
+	rest := upstream[pos+3:]
+	if q := strings.IndexByte(rest, '?'); q != -1 {
+		rest, t.query = rest[:q], rest[q+1:]
+	}
+	if p := strings.IndexByte(rest, '/'); p != -1 {
+		t.path = strings.Split(rest[p+1:], "/")
+		for i, seg := range t.path {
+			t.path[i] = "/" + seg
+		}
+		rest = rest[:p]
+	}
+	t.host = rest
+
+	names := describe(pattern).Params
+	known := func(name string) bool {
+		for _, n := range names {
+			if n == name {
+				return true
+			}
+		}
+		return false
+	}
+	for _, name := range t.params() {
+		if !known(name) {
+			panic("upstream refers to unknown parameter " + name + ": " + upstream)
+		}
+	}
+	return t
+}
+
+// parameter names referred by template
+func (t upstreamTemplate) params() []string {
+	var names []string
+	host := t.host
+	for {
+		pos := strings.IndexByte(host, ':')
+		if pos == -1 {
+			break
+		}
+		end := pos + 1
+		for end < len(host) && host[end] != '.' && host[end] != ':' {
+			end++
+		}
+		if name := host[pos+1 : end]; name != "" && !isPort(name) {
+			names = append(names, name)
+		}
+		host = host[end:]
+	}
+	for _, seg := range t.path {
+		if len(seg) > 2 && (seg[1] == ':' || seg[1] == '*') {
+			names = append(names, seg[2:])
+		}
+	}
+	return names
+}
+
+func (t upstreamTemplate) expand(params Params, query string) (*url.URL, bool) {
+	u := &url.URL{Scheme: t.scheme, RawQuery: t.query}
+	if query != "" {
+		if u.RawQuery != "" {
+			u.RawQuery += "&"
+		}
+		u.RawQuery += query
+	}
+
+	host := t.host
+	for {
+		pos := strings.IndexByte(host, ':')
+		if pos == -1 {
+			u.Host += host
+			break
+		}
+		end := pos + 1
+		for end < len(host) && host[end] != '.' && host[end] != ':' {
+			end++
+		}
+		name := host[pos+1 : end]
+		if name == "" || isPort(name) {
+			u.Host += host[:end]
+		} else {
+			value := params.ByName(name)
+			if !validHostLabel(value) {
+				return nil, false
+			}
+			u.Host += host[:pos] + value
+		}
+		host = host[end:]
+	}
+
+	var path, raw []string
+	for _, seg := range t.path {
+		switch {
+		case len(seg) > 2 && seg[1] == ':':
+			value := params.ByName(seg[2:])
+			path = append(path, "/"+value)
+			raw = append(raw, "/"+url.PathEscape(value))
+		case len(seg) > 2 && seg[1] == '*':
+			value := strings.TrimPrefix(params.ByName(seg[2:]), "/")
+			path = append(path, "/"+value)
+			parts := strings.Split(value, "/")
+			for i, part := range parts {
+				parts[i] = url.PathEscape(part)
+			}
+			raw = append(raw, "/"+strings.Join(parts, "/"))
+		default:
+			path = append(path, seg)
+			raw = append(raw, seg)
+		}
+	}
+	u.Path, u.RawPath = strings.Join(path, ""), strings.Join(raw, "")
+	return u, true
+}
+
+func isPort(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func validHostLabel(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '.') {
+			return false
+		}
+	}
+	return true
+}
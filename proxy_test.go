@@ -0,0 +1,127 @@
+//go:build go1.8
+// +build go1.8
+
+package fastroute_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+func TestProxy(t *testing.T) {
+	t.Parallel()
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := ioutil.ReadAll(req.Body)
+		fmt.Fprintf(w, "%s %s %s %s %s %s %s",
+			req.Method,
+			req.URL.EscapedPath(),
+			req.URL.RawQuery,
+			body,
+			req.Header.Get("X-Forwarded-Host"),
+			req.Header.Get("X-Forwarded-Proto"),
+			req.Header.Get("X-Forwarded-For"),
+		)
+	}))
+	defer upstream.Close()
+
+	router := fastroute.Chain(
+		fastroute.Proxy("/api/:version/*rest", upstream.URL+"/internal/:version/*rest?from=proxy"),
+		fastroute.Proxy("/users/:name", upstream.URL+"/people/:name/"),
+	)
+	front := httptest.NewServer(router)
+	defer front.Close()
+
+	cases := []struct {
+		method, path, body, expected string
+	}{
+		{"GET", "/api/v1/files/a/b.txt?x=1", "", "GET /internal/v1/files/a/b.txt from=proxy&x=1"},
+		{"POST", "/api/v2/a%20b/c%3Fd/", "payload", "POST /internal/v2/a%20b/c%3Fd/ from=proxy"},
+		{"GET", "/users/john%20doe%3F", "", "GET /people/john%20doe%3F/ "},
+	}
+
+	for i, c := range cases {
+		req, err := http.NewRequest(c.method, front.URL+c.path, strings.NewReader(c.body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := ioutil.ReadAll(res.Body)
+		res.Body.Close()
+
+		expected := fmt.Sprintf("%s %s %s http 127.0.0.1", c.expected, c.body, strings.TrimPrefix(front.URL, "http://"))
+		if res.StatusCode != 200 || string(body) != expected {
+			t.Fatalf("expected response: %q, but got: %d %q, case: %d", expected, res.StatusCode, body, i)
+		}
+	}
+}
+
+type recordingTransport struct {
+	hosts []string
+}
+
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.hosts = append(t.hosts, req.URL.Host+" "+req.Host)
+	return &http.Response{StatusCode: 200, Body: ioutil.NopCloser(strings.NewReader("")), Header: http.Header{}}, nil
+}
+
+func TestProxyHostParameters(t *testing.T) {
+	t.Parallel()
+
+	transport := &recordingTransport{}
+	router := fastroute.Proxy("/tenants/:tenant/api/*rest", "http://:tenant.internal:8080/*rest", func(p *httputil.ReverseProxy) {
+		p.Transport = transport
+	})
+
+	for _, tenant := range []string{"acme", "bad%20tenant", "evil.com:80"} {
+		req, err := http.NewRequest("GET", "/tenants/"+tenant+"/api/orders", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if tenant == "acme" && w.Code != 200 {
+			t.Fatalf("expected request to be proxied, but got: %d", w.Code)
+		}
+		if tenant != "acme" && w.Code != 400 {
+			t.Fatalf("expected invalid host parameter: %s to be rejected, but got: %d", tenant, w.Code)
+		}
+		if req.URL.Path != "/tenants/"+strings.Replace(tenant, "%20", " ", 1)+"/api/orders" || fastroute.Parameters(req) != nil {
+			t.Fatal("expected request to be restored")
+		}
+	}
+
+	if len(transport.hosts) != 1 || transport.hosts[0] != "acme.internal:8080 acme.internal:8080" {
+		t.Fatalf("unexpected upstream hosts: %v", transport.hosts)
+	}
+}
+
+func TestProxyValidation(t *testing.T) {
+	t.Parallel()
+
+	for upstream, expected := range map[string]string{
+		"internal/*rest":            "upstream must be an absolute URL: internal/*rest",
+		"http://internal/:other":    "upstream refers to unknown parameter other: http://internal/:other",
+		"http://:tenant.internal/x": "upstream refers to unknown parameter tenant: http://:tenant.internal/x",
+	} {
+		func() {
+			defer func() {
+				if err := recover(); fmt.Sprint(err) != expected {
+					t.Fatalf("expected panic: %q, but got: %v", expected, err)
+				}
+			}()
+			fastroute.Proxy("/api/*rest", upstream)
+		}()
+	}
+}
@@ -0,0 +1,192 @@
+package fastroute
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+type queryParam struct {
+	key, name string
+	optional  bool
+}
+
+// creates route for pattern having a query section, like
+// "/search?q=:query&page=:page?", which matches the path
+// part first and then binds values of the listed query keys
+func queried(p string, q int, h http.Handler) (Router, error) {
+	query, err := parseQuery(p[q+1:])
+	if err != nil {
+		return nil, errors.New(err.Error() + ": " + p)
+	}
+
+	path := p[:q]
+	matches := func(url string, ps *Params) bool {
+		return url == path
+	}
+	if strings.IndexAny(path, ":*\\") != -1 {
+		segments, err := parse(path)
+		if err != nil {
+			return nil, err
+		}
+		matches = matcher(segments, path[len(path)-1] == '/', false)
+	}
+
+	num := strings.Count(p, ":") + strings.Count(path, "*")
+	r := dynamic(p, num, h, matches)
+	return route{RouterFunc(func(req *http.Request) http.Handler {
+		ps := boundParams(req)
+		bound := ps != nil
+		var n int
+		if bound {
+			n = len(ps.params)
+		}
+		handler := r.Route(req)
+		if handler == nil {
+			return nil
+		}
+		ps = req.Body.(*parameters)
+		if matchQuery(query, req.URL.RawQuery, num, &ps.params) {
+			return handler
+		}
+		if bound {
+			ps.params = ps.params[:n]
+		} else {
+			ps.reset(req)
+		}
+		return nil
+	}), p, h, r.src}, nil
+}
+
+// index of the question mark starting query section
+// of pattern, or -1, escaped ones are taken literally
+func queryStart(p string) int {
+	for i := 0; i < len(p); i++ {
+		switch p[i] {
+		case '\\':
+			i++
+		case '?':
+			return i
+		}
+	}
+	return -1
+}
+
+// parses query section of pattern
+func parseQuery(raw string) ([]queryParam, error) {
+	var query []queryParam
+	for _, pair := range strings.Split(raw, "&") {
+		pos := strings.IndexByte(pair, '=')
+		if pos < 1 || len(pair) < pos+3 || pair[pos+1] != ':' {
+			return nil, errors.New("query parameter must be given as key=:name")
+		}
+		param := queryParam{key: pair[:pos], name: pair[pos+2:]}
+		if last := len(param.name) - 1; param.name[last] == '?' {
+			param.name, param.optional = param.name[:last], true
+		}
+		if param.name == "" || strings.IndexAny(param.name, ":*?=") != -1 {
+			return nil, errors.New("query parameter must be given as key=:name")
+		}
+		query = append(query, param)
+	}
+	return query, nil
+}
+
+// pushes values of query keys to ps, reading raw query
+// as is, unless there are escaped characters. At most max
+// values of a repeated key are pushed, so a request cannot
+// grow ps. Reports whether all the required keys are present
+func matchQuery(query []queryParam, raw string, max int, ps *Params) bool {
+	for _, param := range query {
+		found := 0
+		for rest := raw; rest != "" && found < max; {
+			pair := rest
+			if pos := strings.IndexByte(rest, '&'); pos != -1 {
+				pair, rest = rest[:pos], rest[pos+1:]
+			} else {
+				rest = ""
+			}
+			key, value := pair, ""
+			if pos := strings.IndexByte(pair, '='); pos != -1 {
+				key, value = pair[:pos], pair[pos+1:]
+			}
+			if unescapeQuery(key) != param.key {
+				continue
+			}
+			found++
+			ps.Append(param.name, unescapeQuery(value))
+		}
+		if found == 0 && !param.optional {
+			return false
+		}
+	}
+	return true
+}
+
+// decodes query component, if it has escaped characters,
+// malformed escapes are left as is
+func unescapeQuery(s string) string {
+	if strings.IndexAny(s, "%+") == -1 {
+		return s
+	}
+	if unescaped, err := url.QueryUnescape(s); err == nil {
+		return unescaped
+	}
+	return s
+}
+
+// RequireQuery wraps router in order to match requests
+// only, when all the given query keys have a non-empty
+// value, like "?api_key=". Otherwise request falls through,
+// so a following route may answer it with 400 Bad Request:
+//
+//	fastroute.Chain(
+//		fastroute.RequireQuery(routes, "api_key"),
+//		fastroute.New("/*path", badRequest),
+//	)
+//
+// Query is scanned as is, without parsing it to url.Values.
+// See RequireQueryPresent in order to accept empty values.
+func RequireQuery(router Router, keys ...string) Router {
+	return requireQuery(router, keys, false)
+}
+
+// RequireQueryPresent wraps router the same way RequireQuery
+// does, but accepts empty values, like "?debug" or "?debug=",
+// so it only tells missing keys apart.
+func RequireQueryPresent(router Router, keys ...string) Router {
+	return requireQuery(router, keys, true)
+}
+
+func requireQuery(router Router, keys []string, empty bool) Router {
+	return wrapper{RouterFunc(func(req *http.Request) http.Handler {
+		for _, key := range keys {
+			if value, ok := queryValue(req.URL.RawQuery, key); !ok || (value == "" && !empty) {
+				return nil
+			}
+		}
+		return router.Route(req)
+	}), router}
+}
+
+// the first value of query key in raw query
+// and whether the key is present
+func queryValue(raw, key string) (string, bool) {
+	for raw != "" {
+		pair := raw
+		if pos := strings.IndexByte(raw, '&'); pos != -1 {
+			pair, raw = raw[:pos], raw[pos+1:]
+		} else {
+			raw = ""
+		}
+		name, value := pair, ""
+		if pos := strings.IndexByte(pair, '='); pos != -1 {
+			name, value = pair[:pos], pair[pos+1:]
+		}
+		if unescapeQuery(name) == key {
+			return unescapeQuery(value), true
+		}
+	}
+	return "", false
+}
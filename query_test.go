@@ -0,0 +1,121 @@
+package fastroute_test
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+func TestQueryPattern(t *testing.T) {
+	t.Parallel()
+	handler := func(w http.ResponseWriter, req *http.Request) {}
+	router := fastroute.Chain(
+		fastroute.New("/search?q=:query&page=:page?", handler),
+		fastroute.New("/users/:id?fields=:fields", handler),
+		fastroute.New("/search", handler),
+	)
+
+	cases := map[string]string{
+		"/search?q=go&page=2":         "/search?q=:query&page=:page? query=go page=2",
+		"/search?page=2&q=go+routers": "/search?q=:query&page=:page? query=go routers page=2",
+		"/search?q=go":                "/search?q=:query&page=:page? query=go",
+		"/search?q=":                  "/search?q=:query&page=:page? query=",
+		"/search?q=a&q=b%26c":         "/search?q=:query&page=:page? query=a query=b&c",
+		"/search?page=2":              "/search",
+		"/search":                     "/search",
+		"/users/5?fields=name&x=1":    "/users/:id?fields=:fields id=5 fields=name",
+		"/users/5":                    "no match",
+		"/users/5?field=name":         "no match",
+		"/search/x?q=go":              "no match",
+	}
+	for target, expected := range cases {
+		req, _ := http.NewRequest("GET", target, nil)
+		actual := "no match"
+		if router.Route(req) != nil {
+			actual = fastroute.Pattern(req)
+			for _, p := range fastroute.Parameters(req) {
+				actual += " " + p.Key + "=" + p.Value
+			}
+			fastroute.Recycle(req)
+		}
+		if actual != expected {
+			t.Fatalf("expected: %s to be routed as %q, but got %q", target, expected, actual)
+		}
+	}
+
+	// values of a repeated key are bound up to the number of pattern parameters
+	req, _ := http.NewRequest("GET", "/search?q=a&q=b&q=c&q=d&page=1&page=2&page=3", nil)
+	router.Route(req)
+	if values := fastroute.Parameters(req).ByNameAll("query"); !reflect.DeepEqual(values, []string{"a", "b"}) {
+		t.Fatalf("expected capped values of repeated key, but got: %v", values)
+	}
+	if params := fastroute.Parameters(req); len(params) != 4 {
+		t.Fatalf("expected capped parameters, but got: %v", params)
+	}
+	fastroute.Recycle(req)
+
+	// escaped question mark is a part of the path
+	faq := fastroute.New("/faq\\?", handler)
+	req, _ = http.NewRequest("GET", "/faq%3F", nil)
+	if faq.Route(req) == nil {
+		t.Fatal("expected escaped question mark to be matched literally")
+	}
+	if info := fastroute.Inspect(faq); info[0].Params != nil {
+		t.Fatalf("expected no query parameters, but got: %+v", info[0])
+	}
+
+	if info := fastroute.Inspect(router); !reflect.DeepEqual(info[1].Params, []string{"id", "fields"}) {
+		t.Fatalf("expected query parameters to be described, but got: %+v", info[1])
+	}
+
+	for _, pattern := range []string{"/search?q", "/search?q=query", "/search?q=:", "/search?=:q", "/search?q=:a&"} {
+		if _, err := fastroute.TryNew(pattern, handler); err == nil {
+			t.Fatalf("expected an error for pattern: %s", pattern)
+		}
+	}
+}
+
+func Benchmark_QueryPattern(b *testing.B) {
+	router := fastroute.New("/search?q=:query&page=:page?", func(w http.ResponseWriter, req *http.Request) {})
+	req, err := http.NewRequest("GET", "/search?q=go&page=2&sort=desc", nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	benchmark(b, router, req)
+}
+
+func TestRequireQuery(t *testing.T) {
+	t.Parallel()
+	handler := func(w http.ResponseWriter, req *http.Request) {}
+	routes := fastroute.New("/feed", handler)
+	required := fastroute.RequireQuery(routes, "api_key", "v")
+	present := fastroute.RequireQueryPresent(routes, "api_key", "v")
+
+	cases := []struct {
+		target            string
+		required, present bool
+	}{
+		{"/feed?api_key=abc&v=2", true, true},
+		{"/feed?v=2&api%5Fkey=abc", true, true},
+		{"/feed?api_key=&v=2", false, true},
+		{"/feed?api_key&v=2", false, true},
+		{"/feed?v=2", false, false},
+		{"/feed", false, false},
+		{"/other?api_key=abc&v=2", false, false},
+	}
+	matches := func(router fastroute.Router, target string) bool {
+		req, _ := http.NewRequest("GET", target, nil)
+		return router.Route(req) != nil
+	}
+	for _, c := range cases {
+		if matches(required, c.target) != c.required {
+			t.Fatalf("expected %s match to be %t, when values are required", c.target, c.required)
+		}
+		if matches(present, c.target) != c.present {
+			t.Fatalf("expected %s match to be %t, when keys are required", c.target, c.present)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package fastroute
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RateLimitByParam wraps router in order to throttle
+// matched requests by the value of named path parameter.
+//
+// Once router matches the request, limiter is consulted
+// with the parameter value, for example a tenant from
+// "/tenants/:tenant/orders". If it returns false, request
+// is denied with 429 Too Many Requests response and the
+// matched handler is not invoked.
+//
+// Parameters of denied requests are recycled before
+// the 429 handler is returned, so Parameters(req) and
+// Pattern(req) are no longer available when it is served.
+func RateLimitByParam(router Router, param string, limiter func(key string) bool) Router {
+	return wrapper{RouterFunc(func(req *http.Request) http.Handler {
+		h := router.Route(req)
+		if h == nil || limiter(Parameters(req).ByName(param)) {
+			return h
+		}
+		Recycle(req)
+		return tooManyRequests
+	}), router}
+}
+
+var tooManyRequests = http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+	http.Error(w, "Too Many Requests", 429)
+})
+
+// RateLimit wraps router in order to throttle matched
+// requests, limit per second with the given burst, by
+// the key identifying the client, see RateLimiter.
+func RateLimit(router Router, limit float64, burst int, key func(*http.Request) string) Router {
+	return RateLimiter{}.RateLimit(router, limit, burst, key)
+}
+
+// RateLimiter throttles requests by token buckets kept
+// per key. Zero value uses system time and keeps up to
+// 10000 buckets, tests may inject a clock instead.
+type RateLimiter struct {
+	Clock Clock
+
+	// MaxKeys bounds the number of buckets kept at a
+	// time. Buckets, which refilled, are dropped first,
+	// otherwise buckets of arbitrary keys are dropped,
+	// giving them the burst again.
+	MaxKeys int
+}
+
+// RateLimit wraps router in order to allow limit matched
+// requests per second for each key, with up to burst of
+// requests at once, like "100 requests per minute per API
+// key" on an export route:
+//
+//	fastroute.RateLimit(exports, 100.0/60, 100, fastroute.HeaderKey("X-API-Key"))
+//
+// Key is taken once router matches the request, so the
+// key function may read Parameters(req), client IP is the
+// key if it is nil. Requests over the limit are denied with
+// 429 Too Many Requests, having RateLimit-Limit,
+// RateLimit-Remaining, RateLimit-Reset and Retry-After
+// headers set, before the matched handler is invoked. Their
+// parameters are recycled before the 429 handler is served.
+//
+// It panics if limit or burst is not positive.
+func (l RateLimiter) RateLimit(router Router, limit float64, burst int, key func(*http.Request) string) Router {
+	if limit <= 0 || burst <= 0 {
+		panic("rate limit and burst must be positive")
+	}
+	if key == nil {
+		key = RemoteIP
+	}
+	if l.Clock == nil {
+		l.Clock = systemClock{}
+	}
+	if l.MaxKeys <= 0 {
+		l.MaxKeys = 10000
+	}
+	b := &buckets{RateLimiter: l, limit: limit, burst: float64(burst), all: make(map[string]*bucket)}
+	header := strconv.Itoa(burst)
+
+	return wrapper{RouterFunc(func(req *http.Request) http.Handler {
+		h := router.Route(req)
+		if h == nil {
+			return nil
+		}
+		wait, ok := b.take(key(req))
+		if ok {
+			return h
+		}
+		Recycle(req)
+		reset := strconv.FormatInt(int64(math.Ceil(wait.Seconds())), 10)
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			w.Header().Set("RateLimit-Limit", header)
+			w.Header().Set("RateLimit-Remaining", "0")
+			w.Header().Set("RateLimit-Reset", reset)
+			w.Header().Set("Retry-After", reset)
+			tooManyRequests(w, req)
+		})
+	}), router}
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+type buckets struct {
+	RateLimiter
+	limit, burst float64
+	mu           sync.Mutex
+	all          map[string]*bucket
+	swept        time.Time
+}
+
+// takes a token of the key bucket, or tells how long it
+// takes, until there is one
+func (b *buckets) take(key string) (time.Duration, bool) {
+	now := b.Clock.Now()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	k, ok := b.all[key]
+	if !ok {
+		if len(b.all) >= b.MaxKeys {
+			b.evict(now)
+		}
+		k = &bucket{tokens: b.burst, last: now}
+		b.all[key] = k
+	}
+	k.tokens = math.Min(b.burst, k.tokens+now.Sub(k.last).Seconds()*b.limit)
+	k.last = now
+	if k.tokens >= 1 {
+		k.tokens--
+		return 0, true
+	}
+	return time.Duration((1 - k.tokens) / b.limit * float64(time.Second)), false
+}
+
+// drops refilled buckets, at most once in the time
+// a bucket takes to refill, since it walks all of them,
+// or an arbitrary one, if none refilled
+func (b *buckets) evict(now time.Time) {
+	refill := time.Duration(b.burst / b.limit * float64(time.Second))
+	if now.Sub(b.swept) >= refill {
+		b.swept = now
+		for key, k := range b.all {
+			if k.tokens+now.Sub(k.last).Seconds()*b.limit >= b.burst {
+				delete(b.all, key)
+			}
+		}
+	}
+	for key := range b.all {
+		if len(b.all) < b.MaxKeys {
+			break
+		}
+		delete(b.all, key)
+	}
+}
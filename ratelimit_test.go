@@ -0,0 +1,167 @@
+package fastroute_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+func TestRateLimitByParam(t *testing.T) {
+	t.Parallel()
+
+	hits := map[string]int{}
+	router := fastroute.RateLimitByParam(
+		fastroute.New("/tenants/:tenant/orders", func(w http.ResponseWriter, req *http.Request) {
+			w.Write([]byte(fastroute.Parameters(req).ByName("tenant")))
+		}),
+		"tenant",
+		func(key string) bool {
+			hits[key]++
+			return hits[key] <= 2
+		},
+	)
+
+	cases := []struct {
+		path string
+		code int
+	}{
+		{"/tenants/a/orders", 200},
+		{"/tenants/a/orders", 200},
+		{"/tenants/b/orders", 200},
+		{"/tenants/a/orders", 429},
+		{"/tenants/b/orders", 200},
+		{"/tenants/b/orders", 429},
+		{"/tenants/b", 404},
+	}
+
+	for i, c := range cases {
+		req, err := http.NewRequest("GET", c.path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != c.code {
+			t.Fatalf("expected response code: %d, but got: %d, case: %d", c.code, w.Code, i)
+		}
+		if params := fastroute.Parameters(req); params != nil {
+			t.Fatalf("expected parameters to be recycled, case: %d", i)
+		}
+	}
+
+	if hits["a"] != 3 || hits["b"] != 3 {
+		t.Fatalf("limiter consulted unexpectedly: %v", hits)
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	t.Parallel()
+	clock := &fakeClock{now: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}
+	router := fastroute.RateLimiter{Clock: clock}.RateLimit(
+		fastroute.New("/export/:id", func(w http.ResponseWriter, req *http.Request) {}),
+		0.5, 2, // a request per two seconds, two at once
+		func(req *http.Request) string {
+			return req.Header.Get("X-API-Key") + "/" + fastroute.Parameters(req).ByName("id")
+		},
+	)
+
+	serve := func(key, path string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", path, nil)
+		req.Header.Set("X-API-Key", key)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if fastroute.Parameters(req) != nil {
+			t.Fatal("expected parameters to be recycled")
+		}
+		return w
+	}
+
+	for i, expected := range []int{200, 200, 429} {
+		if w := serve("a", "/export/1"); w.Code != expected {
+			t.Fatalf("expected request %d to respond %d, but got: %d", i, expected, w.Code)
+		}
+	}
+	w := serve("a", "/export/1")
+	if w.Header().Get("RateLimit-Limit") != "2" || w.Header().Get("RateLimit-Remaining") != "0" || w.Header().Get("RateLimit-Reset") != "2" || w.Header().Get("Retry-After") != "2" {
+		t.Fatalf("expected rate limit headers, but got: %v", w.Header())
+	}
+	if w := serve("b", "/export/1"); w.Code != 200 {
+		t.Fatalf("expected other key to have its own bucket, but got: %d", w.Code)
+	}
+	if w := serve("a", "/export/2"); w.Code != 200 {
+		t.Fatalf("expected key read from parameters, but got: %d", w.Code)
+	}
+	if w := serve("a", "/other"); w.Code != 404 {
+		t.Fatalf("expected unmatched request not to be limited, but got: %d", w.Code)
+	}
+
+	clock.now = clock.now.Add(1500 * time.Millisecond)
+	if w := serve("a", "/export/1"); w.Code != 429 || w.Header().Get("RateLimit-Reset") != "1" {
+		t.Fatalf("expected bucket not to be refilled yet, but got: %d %v", w.Code, w.Header())
+	}
+	clock.now = clock.now.Add(time.Second)
+	if w := serve("a", "/export/1"); w.Code != 200 {
+		t.Fatalf("expected a token to be refilled, but got: %d", w.Code)
+	}
+}
+
+func TestRateLimitEvictsBuckets(t *testing.T) {
+	t.Parallel()
+	clock := &fakeClock{}
+	router := fastroute.RateLimiter{Clock: clock, MaxKeys: 2}.RateLimit(
+		fastroute.New("/", func(w http.ResponseWriter, req *http.Request) {}),
+		0.001, 1, fastroute.HeaderKey("X-Key"),
+	)
+	allowed := func(key string) bool {
+		req, _ := http.NewRequest("GET", "/", nil)
+		req.Header.Set("X-Key", key)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code == 200
+	}
+
+	if !allowed("a") || !allowed("b") || allowed("a") || allowed("b") {
+		t.Fatal("expected buckets of a and b to be drained")
+	}
+	if !allowed("c") {
+		t.Fatal("expected a new key to get a bucket")
+	}
+	if !allowed("a") && !allowed("b") {
+		t.Fatal("expected a bucket to be dropped, so there are at most two")
+	}
+}
+
+func TestRateLimitConcurrency(t *testing.T) {
+	t.Parallel()
+	var served int64
+	router := fastroute.RateLimit(
+		fastroute.New("/", func(w http.ResponseWriter, req *http.Request) {
+			atomic.AddInt64(&served, 1)
+		}),
+		0.001, 10, fastroute.HeaderKey("X-Key"),
+	)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				req, _ := http.NewRequest("GET", "/", nil)
+				req.Header.Set("X-Key", strconv.Itoa(j%4))
+				router.ServeHTTP(httptest.NewRecorder(), req)
+			}
+		}(i)
+	}
+	wg.Wait()
+	if served != 40 {
+		t.Fatalf("expected burst of each key to be served, but got: %d", served)
+	}
+}
@@ -0,0 +1,156 @@
+package fastroute
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ReplayOption configures Replay.
+type ReplayOption func(*replay)
+
+type replay struct {
+	serve   bool
+	workers int
+}
+
+// ReplayServe serves matched handlers into a response
+// discarding anything written, so the cost of handlers
+// is included in the report.
+func ReplayServe() ReplayOption {
+	return func(r *replay) {
+		r.serve = true
+	}
+}
+
+// ReplayConcurrency routes requests by n goroutines,
+// simulating parallel load, one by default.
+func ReplayConcurrency(n int) ReplayOption {
+	return func(r *replay) {
+		if n > 0 {
+			r.workers = n
+		}
+	}
+}
+
+// ReplayReport is the outcome of Replay.
+type ReplayReport struct {
+	Requests  int            // number of replayed requests
+	Matched   map[string]int // number of matched requests by pattern
+	Unmatched int            // number of requests, which were not matched
+
+	// Duration is the wall time taken to route all
+	// the requests once.
+	Duration time.Duration
+
+	// AllocsPerRequest is the average number of heap
+	// allocations made per routed request.
+	AllocsPerRequest float64
+}
+
+// Replay routes requests read from r by router, in order to
+// measure routing of the real traffic shape, for example
+// taken from access log. Each line holds method and request
+// URI delimited by space, like "GET /users/5?tab=posts",
+// while blank lines and lines starting with # are skipped.
+//
+// Requests are constructed once, and then routed twice:
+// first to count matches by pattern, and then to measure
+// the duration and allocations, the way
+// testing.AllocsPerRun does after a warm up run. Matched
+// requests are recycled, or served if ReplayServe is given.
+// It may be used in a benchmark or a main func alike:
+//
+//	report, err := fastroute.Replay(router, logFile, fastroute.ReplayConcurrency(8))
+//
+// It returns an error if a line cannot be parsed, or
+// reading fails.
+func Replay(router Router, r io.Reader, options ...ReplayOption) (ReplayReport, error) {
+	rp := &replay{workers: 1}
+	for _, option := range options {
+		option(rp)
+	}
+
+	var reqs []*http.Request
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || text[0] == '#' {
+			continue
+		}
+		fields := strings.Fields(text)
+		if len(fields) < 2 {
+			return ReplayReport{}, fmt.Errorf("replay line %d is not \"METHOD path\": %s", line, text)
+		}
+		req, err := http.NewRequest(fields[0], fields[1], nil)
+		if err != nil {
+			return ReplayReport{}, fmt.Errorf("replay line %d: %s", line, err)
+		}
+		reqs = append(reqs, req)
+	}
+	if err := scanner.Err(); err != nil {
+		return ReplayReport{}, err
+	}
+
+	report := ReplayReport{Requests: len(reqs), Matched: map[string]int{}}
+	rp.run(router, reqs, func(pattern string) {
+		if pattern == "" {
+			report.Unmatched++
+		} else {
+			report.Matched[pattern]++
+		}
+	})
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+	rp.run(router, reqs, nil)
+	report.Duration = time.Since(start)
+	runtime.ReadMemStats(&after)
+	if len(reqs) > 0 {
+		report.AllocsPerRequest = float64(after.Mallocs-before.Mallocs) / float64(len(reqs))
+	}
+	return report, nil
+}
+
+// routes requests by workers, each taking every n-th
+// request, count is called with the matched pattern
+// under lock, or with empty string for a miss
+func (rp *replay) run(router Router, reqs []*http.Request, count func(pattern string)) {
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	wg.Add(rp.workers)
+	for worker := 0; worker < rp.workers; worker++ {
+		go func(worker int) {
+			defer wg.Done()
+			w := &discardResponse{header: make(http.Header)}
+			for i := worker; i < len(reqs); i += rp.workers {
+				req := reqs[i]
+				h := router.Route(req)
+				if count != nil {
+					var pattern string
+					if h != nil {
+						pattern = Pattern(req)
+					}
+					mu.Lock()
+					count(pattern)
+					mu.Unlock()
+				}
+				switch {
+				case h == nil:
+				case rp.serve:
+					h.ServeHTTP(w, req)
+					Recycle(req) // unless served by a route
+				default:
+					Recycle(req)
+				}
+			}
+		}(worker)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,62 @@
+package fastroute_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+const accessLog = `
+# method and request URI
+GET /users/5
+GET /users/6?tab=posts
+POST /users
+GET /status
+GET /missing
+DELETE /users/7
+`
+
+func TestReplay(t *testing.T) {
+	t.Parallel()
+	var served int
+	handler := func(w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte("discarded"))
+		served++
+	}
+	router := fastroute.Chain(
+		fastroute.MethodGroup("GET|DELETE", fastroute.New("/users/:id", handler)),
+		fastroute.New("/users", handler),
+		fastroute.New("/status", handler),
+	)
+
+	for _, workers := range []int{1, 4} {
+		report, err := fastroute.Replay(router, strings.NewReader(accessLog), fastroute.ReplayConcurrency(workers))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if report.Requests != 6 || report.Unmatched != 1 || report.Matched["/users/:id"] != 3 || report.Matched["/users"] != 1 || report.Matched["/status"] != 1 {
+			t.Fatalf("unexpected report: %+v, workers: %d", report, workers)
+		}
+		if report.Duration <= 0 || report.AllocsPerRequest < 0 {
+			t.Fatalf("expected duration and allocations to be measured, but got: %+v", report)
+		}
+	}
+	if served != 0 {
+		t.Fatalf("expected handlers not to be served, but served: %d", served)
+	}
+
+	if _, err := fastroute.Replay(router, strings.NewReader(accessLog), fastroute.ReplayServe()); err != nil {
+		t.Fatal(err)
+	}
+	if served != 10 {
+		t.Fatalf("expected matched handlers to be served twice, but served: %d", served)
+	}
+
+	for _, log := range []string{"GET", "GET /users\nbroken", "GET %zz"} {
+		if _, err := fastroute.Replay(router, strings.NewReader(log)); err == nil {
+			t.Fatalf("expected an error for log: %q", log)
+		}
+	}
+}
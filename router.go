@@ -77,7 +77,7 @@
 //
 // Catch-all parameters match anything until the path end, including the
 // directory index (the '/' before the catch-all). Since they match anything
-// until the end, catch-all parameters must always be the final path element.
+// until the end, catch-all parameters may only be followed by static segments.
 //  Path: /files/*filepath
 //
 //  Requests:
@@ -91,12 +91,66 @@
 //  Requests:
 //   /                                   match: any="/"
 //   /files/dir                          match: any="/files/dir"
+//
+// Catch-all parameter may be left unnamed, when its value is not needed. Then
+// it must be the last segment and nothing is bound, while Pattern(req) still
+// reports the pattern:
+//  Path: /assets/*
+//
+//  Requests:
+//   /assets/css/site.css                match
+//   /assets                             no match
+//
+// Static segments following a catch-all must be at the path end, so the
+// catch-all takes as many segments as it can, but at least one:
+//  Path: /proxy/*middle/status
+//
+//  Requests:
+//   /proxy/a/b/c/status                 match: middle="/a/b/c"
+//   /proxy/status/status                match: middle="/status"
+//   /proxy/a/status/status              match: middle="/a/status"
+//   /proxy/status                       no match
+//   /proxy/a/status/                    no match
+//
+// Signs are taken literally, when escaped by a backslash, so paths may contain
+// a colon or an asterisk, mind the backslash is doubled in interpreted Go string
+// literals, like "/search/\\*". A named parameter may be followed by a literal
+// suffix within its segment, starting with an escaped character, like custom
+// verbs:
+//  Path: /v1/jobs/:id\:cancel
+//
+//  Requests:
+//   /v1/jobs/42:cancel                  match: id="42"
+//   /v1/jobs/:cancel                    no match
+//   /v1/jobs/42                         no match
+//
+//  Path: /search/\*
+//
+//  Requests:
+//   /search/*                           match
+//   /search/go                          no match
+//
+// A pattern may have a query section, listing query keys, which values are bound
+// as named parameters following the path ones. Route does not match, unless all
+// the keys are present, while optional ones are marked by a question mark. Values
+// of a repeated key are bound, up to the number of parameters of the pattern, see
+// Params.ByNameAll. A question mark in the path is taken literally only when
+// escaped, like "/faq\\?", as it starts the query section otherwise:
+//  Path: /search?q=:query&page=:page?
+//
+//  Requests:
+//   /search?q=go&page=2                 match: query="go", page="2"
+//   /search?page=2&q=go+routers         match: query="go routers", page="2"
+//   /search?q=go                        match: query="go"
+//   /search?page=2                      no match
 package fastroute
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 	"strings"
 	"sync"
 )
@@ -106,9 +160,18 @@ import (
 //
 // If there were no parameters and route is static
 // then empty parameter slice is returned.
+//
+// The slice is capped at its length, so appending to it
+// always copies parameters to a new array, instead of
+// writing past them into the array reused by the route.
+// Keys stay in that array for the following requests, so
+// they must not be modified in place.
 func Parameters(req *http.Request) Params {
-	if p, _ := req.Body.(*parameters); p != nil {
-		return p.params
+	if p := carried(req); p != nil {
+		if p.lazy != nil {
+			p.once.Do(p.bind) // bound by NewLazy
+		}
+		return p.params[:len(p.params):len(p.params)]
 	}
 	return nil
 }
@@ -119,7 +182,7 @@ func Parameters(req *http.Request) Params {
 // If request parameters were already recycled,
 // or route is static - it will return req.URL.Path.
 func Pattern(req *http.Request) string {
-	if p, _ := req.Body.(*parameters); p != nil {
+	if p := carried(req); p != nil {
 		return p.pattern
 	}
 	return req.URL.Path // if matched will be same as url path
@@ -140,12 +203,41 @@ func Pattern(req *http.Request) string {
 // If the route is not matched and handler is nil,
 // then parameters will not be allocated, same
 // as for static paths.
+//
+// Parameters bound by a combinator, like Host, which
+// is still routing the request, are recycled by it,
+// so only the ones bound by inner routes are dropped.
 func Recycle(req *http.Request) {
 	if p, _ := req.Body.(*parameters); p != nil {
+		if p.binding {
+			// combinator, like Host, recycles the set
+			p.params = p.params[:p.kept]
+			return
+		}
 		p.reset(req)
 	}
 }
 
+// WithParams returns a shallow copy of request, having
+// the given params attached, as if it was routed by a
+// route having them bound. It is meant for testing
+// handlers in isolation:
+//
+//	req := fastroute.WithParams(req, fastroute.Params{{"id", "5"}})
+//	handler.ServeHTTP(w, req)
+//
+// Attached params are not pooled, recycling the request
+// only detaches them. Pattern(req) reports request path.
+func WithParams(req *http.Request, params Params) *http.Request {
+	r := *req
+	body := req.Body
+	if p, _ := body.(*parameters); p != nil {
+		body = p.ReadCloser
+	}
+	r.Body = &parameters{ReadCloser: body, params: params, pattern: req.URL.Path}
+	return &r
+}
+
 // Params is a slice of key value pairs, as extracted from
 // the http.Request served by Router.
 //
@@ -164,11 +256,120 @@ func (ps Params) ByName(name string) string {
 	return ""
 }
 
-// used internally to lazily append parameters
-func (ps *Params) push(key, val string) {
+// ByNameAll returns values of all the Params which key matches
+// the given name, like values of a repeated query key.
+func (ps Params) ByNameAll(name string) []string {
+	var values []string
+	for i := range ps {
+		if ps[i].Key == name {
+			values = append(values, ps[i].Value)
+		}
+	}
+	return values
+}
+
+// Slice returns the value of the first Param which key matches
+// the given name split into path segments, like the value of
+// catch-all parameter "/a/b/c" as "a", "b" and "c". The value is
+// split on each call, so binding it costs nothing extra. Empty
+// segments are kept, so joining them by slash gives the value
+// back. If no matching param is found, or its value is empty
+// or "/", nil is returned.
+func (ps Params) Slice(name string) []string {
+	value := strings.TrimPrefix(ps.ByName(name), "/")
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, "/")
+}
+
+// Set updates the value of the first Param which key matches
+// the given name and reports whether it was found. Params of
+// the request are updated in place, so middleware may normalize
+// them before the handler is served.
+func (ps Params) Set(name, value string) bool {
+	for i := range ps {
+		if ps[i].Key == name {
+			ps[i].Value = value
+			return true
+		}
+	}
+	return false
+}
+
+// Equal reports whether ps and other have the same params,
+// regardless of their order. Values of a repeated key, like
+// ones of a query key, must be in the same order though.
+func (ps Params) Equal(other Params) bool {
+	if len(ps) != len(other) {
+		return false
+	}
+	for i := range ps {
+		// the nth value of key in ps is the nth one in other
+		n := 0
+		for j := 0; j < i; j++ {
+			if ps[j].Key == ps[i].Key {
+				n++
+			}
+		}
+		found := false
+		for j := range other {
+			if other[j].Key != ps[i].Key {
+				continue
+			}
+			if n == 0 {
+				found = other[j].Value == ps[i].Value
+				break
+			}
+			n--
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// String formats params in their order, like
+// [id="5" name="john"], in order to read them
+// in test failures and logs.
+func (ps Params) String() string {
+	buf := make([]byte, 0, 16*len(ps))
+	buf = append(buf, '[')
+	for i := range ps {
+		if i > 0 {
+			buf = append(buf, ' ')
+		}
+		buf = append(buf, ps[i].Key...)
+		buf = append(buf, '=')
+		buf = strconv.AppendQuote(buf, ps[i].Value)
+	}
+	return string(append(buf, ']'))
+}
+
+// Append adds a Param to the end of the slice.
+//
+// Note, Params bound to the request are backed by an array
+// reused by the route for other requests, which is never
+// grown. Appending to Parameters(req) does not change the
+// parameters bound to request, see WithParams in order to
+// attach the appended ones. Since Parameters(req) has no
+// spare capacity, appending allocates a new array, which
+// is detached from the pooled one and safe to retain.
+func (ps *Params) Append(key, value string) {
+	*ps = append(*ps, struct{ Key, Value string }{key, value})
+}
+
+// appends a Param, or only sets the value of the next one,
+// if the key was already written to the backing array
+func (ps *Params) bind(key, value string, filled bool) {
+	if !filled {
+		ps.Append(key, value)
+		return
+	}
 	n := len(*ps)
 	*ps = (*ps)[:n+1]
-	(*ps)[n].Key, (*ps)[n].Value = key, val
+	(*ps)[n].Value = value
 }
 
 // Router interface extends http.Handler with one extra
@@ -228,14 +429,134 @@ func (f RouterFunc) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 // add hit counting sorting goroutine, which calculates order
 // based on hits.
 func Chain(routes ...Router) Router {
-	return RouterFunc(func(req *http.Request) http.Handler {
+	return firstMatch{chain{RouterFunc(func(req *http.Request) http.Handler {
 		for _, router := range routes {
 			if handler := router.Route(req); handler != nil {
 				return handler
 			}
 		}
 		return nil
-	})
+	}), routes}}
+}
+
+// MustChain chains routes the way Chain does, but panics
+// if there are none of them, including routes of nested
+// chains and other enumerable routers, in order to surface
+// a module, which registered no routes, when routes are
+// assembled, rather than as every request not found:
+//
+//	router := fastroute.MustChain(users.Routes(), orders.Routes())
+//
+// Routers, which cannot be enumerated, like a plain
+// RouterFunc, are expected to route some requests.
+func MustChain(routes ...Router) Router {
+	router := Chain(routes...)
+	if patterns, complete := Patterns(router); complete && len(patterns) == 0 {
+		panic("fastroute: there are no routes to chain")
+	}
+	return router
+}
+
+// Patterner is an optional interface, which may be
+// implemented by Router in order to enumerate all the
+// path patterns it is able to match.
+//
+// Routers created by New and Chain implement it. When
+// writing a combinator which wraps another Router,
+// return fastroute.Patterns of the wrapped router, so
+// the composition remains enumerable as far as the
+// wrapped router is, see Opaquer.
+type Patterner interface {
+	Patterns() []string
+}
+
+// Opaquer may be implemented along with Patterner by
+// routers, which cannot enumerate all the patterns they
+// are able to match, like a combinator wrapping a router
+// composed of opaque ones. Opaque reports whether the
+// enumeration is incomplete, which is the negation of
+// the boolean fastroute.Patterns gives for the wrapped
+// router. Patterns of routers, which do not implement
+// it, are taken as complete.
+type Opaquer interface {
+	Opaque() bool
+}
+
+// Patterns lists path patterns the given router
+// is able to match, in the order they are tried.
+//
+// The boolean reports whether the enumeration was
+// complete. It is false when router, or any of the
+// routers it is composed of, does not implement
+// Patterner, like a plain RouterFunc does.
+func Patterns(router Router) ([]string, bool) {
+	routes, ok := inspect(router)
+	patterns := make([]string, len(routes))
+	for i, info := range routes {
+		patterns[i] = info.Pattern
+	}
+	return patterns, ok
+}
+
+type chain struct {
+	RouterFunc
+	routes []Router
+}
+
+// chain trying routes in order, until one matches, as
+// opposed to other chains of this package, like ABTest,
+// which choose the route to try first
+type firstMatch struct {
+	chain
+}
+
+func (c chain) Patterns() []string {
+	patterns, _ := Patterns(c)
+	return patterns
+}
+
+func (c chain) inspect() ([]RouteInfo, bool) {
+	var all []RouteInfo
+	complete := true
+	for _, router := range c.routes {
+		routes, ok := inspect(router)
+		all = append(all, routes...)
+		complete = complete && ok
+	}
+	return all, complete
+}
+
+// wrapper routes by function, but remains as
+// enumerable as the router it wraps
+type wrapper struct {
+	RouterFunc
+	router Router
+}
+
+func (w wrapper) Patterns() []string {
+	patterns, _ := Patterns(w.router)
+	return patterns
+}
+
+func (w wrapper) inspect() ([]RouteInfo, bool) {
+	return inspect(w.router)
+}
+
+type route struct {
+	RouterFunc
+	pattern string
+	handler http.Handler
+	src     *poolSource // nil for static routes
+}
+
+func (r route) Patterns() []string {
+	return []string{r.pattern}
+}
+
+func (r route) inspect() ([]RouteInfo, bool) {
+	info := describe(r.pattern)
+	info.Handler = handlerName(r.handler)
+	return []RouteInfo{info}, true
 }
 
 // New creates Router which attempts
@@ -245,6 +566,10 @@ func Chain(routes ...Router) Router {
 // may be accepted in the following formats:
 //  http.Handler
 //  func(http.ResponseWriter, *http.Request)
+//  func(http.ResponseWriter, *http.Request) error
+//
+// Errors returned by the latter are responded
+// by DefaultErrorHandler, see HandleErrors.
 //
 // Static paths will be simply compared with
 // requested path. While paths having named
@@ -255,112 +580,646 @@ func Chain(routes ...Router) Router {
 // or recycled in order to salvage allocated named
 // parameters back to the sync.Pool, which dynamically
 // expands or shrinks based on concurrency.
+//
+// The matched handler of a dynamic route is served once,
+// for the request or its shallow copy. Served again, its
+// parameters may belong to another request by now, so it
+// responds 500 Internal Server Error, or panics in builds
+// with fastroutedebug tag.
+//
+// Every dynamic route has its own pool, holding params
+// of the exact capacity the pattern needs, so there is
+// nothing to size per request. Static routes do not
+// use the pool at all.
+//
+// New panics if path pattern or handler is invalid,
+// see TryNew for patterns given at runtime.
 func New(path string, handler interface{}) Router {
-	p := "/" + strings.TrimLeft(path, "/")
+	router, err := TryNew(path, handler)
+	if err != nil {
+		panic(err.Error())
+	}
+	return router
+}
 
-	var h http.Handler = nil
-	switch t := handler.(type) {
-	case http.HandlerFunc:
-		h = t
-	case func(http.ResponseWriter, *http.Request):
-		h = http.HandlerFunc(t)
-	case nil:
-		panic("given handler cannot be: nil")
-	default:
-		panic(fmt.Sprintf("not a handler given: %T - %+v", t, t))
+// TryNew creates Router the same way New does, but
+// returns an error instead of panicking, when path
+// pattern or handler is invalid. It is meant for routes
+// coming from configuration or user input, the error
+// message is the one New would panic with.
+func TryNew(path string, handler interface{}) (Router, error) {
+	p := "/" + strings.TrimLeft(path, "/")
+	h, err := handlerOf(handler)
+	if err != nil {
+		return nil, err
+	}
+	if q := queryStart(p); q != -1 {
+		return queried(p, q, h)
 	}
 
 	// maybe static route
-	if strings.IndexAny(p, ":*") == -1 {
-		return RouterFunc(func(req *http.Request) http.Handler {
+	if strings.IndexAny(p, ":*\\") == -1 {
+		return route{RouterFunc(func(req *http.Request) http.Handler {
 			if p == req.URL.Path {
 				return h
 			}
 			return nil
-		})
+		}), p, h, nil}, nil
 	}
 
 	// prepare and validate pattern segments to match
-	segments := strings.Split(strings.Trim(p, "/"), "/")
-	for i, seg := range segments {
-		segments[i] = "/" + seg
-		if pos := strings.IndexAny(seg, ":*"); pos == -1 {
-			continue
-		} else if pos != 0 {
-			panic("special param matching signs, must follow after slash: " + p)
-		} else if len(seg)-1 == pos {
-			panic("param must be named after sign: " + p)
-		} else if seg[0] == '*' && i+1 != len(segments) {
-			panic("match all, must be the last segment in pattern: " + p)
-		} else if strings.IndexAny(seg[1:], ":*") != -1 {
-			panic("only one param per segment: " + p)
-		}
+	segments, err := parse(p)
+	if err != nil {
+		return nil, err
 	}
 	ts := p[len(p)-1] == '/' // whether we need to match trailing slash
 
-	// pool for parameters
 	num := strings.Count(p, ":") + strings.Count(p, "*")
-	pool := sync.Pool{}
-	pool.New = func() interface{} {
-		return &parameters{params: make(Params, 0, num), pool: &pool, pattern: p}
+	if segments[len(segments)-1] == "/*" {
+		num-- // anonymous match all binds nothing
 	}
+	return prefilled(p, num, h, boundKeys(segments), matcher(segments, ts, false), matcher(segments, ts, true)), nil
+}
 
-	// extend handler in order to salvage parameters
-	handle := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
-		h.ServeHTTP(w, req)
-		if p, _ := req.Body.(*parameters); p != nil {
-			p.reset(req)
+// NewCompiled creates Router, which matches path by the
+// given match function, instead of interpreting pattern.
+// Match function must push parameters of the pattern to
+// ps in order, the way New would, and report whether
+// path matches. It is meant for generated matchers, see
+// GenerateMatcher.
+//
+// Pattern is only used to size parameters and to be
+// reported by Pattern(req) and introspection.
+func NewCompiled(pattern string, handler interface{}, match func(path string, ps *Params) bool) Router {
+	h := toHandler(handler)
+	if strings.IndexAny(pattern, ":*") == -1 {
+		return route{RouterFunc(func(req *http.Request) http.Handler {
+			if match(req.URL.Path, nil) {
+				return h
+			}
+			return nil
+		}), pattern, h, nil}
+	}
+	return dynamic(pattern, strings.Count(pattern, ":")+strings.Count(pattern, "*"), h, match)
+}
+
+// NewLazy creates Router, which matches path by pattern
+// the same way New does, but binds parameters only when
+// Parameters(req) is called for the first time, for
+// handlers which mostly do not read them, like proxies:
+//
+//	fastroute.NewLazy("/proxy/:service/*path", proxy)
+//
+// Matching only verifies the path, parameters are bound
+// by matching the path again on the first read. The path
+// is kept as it was matched, so the values are the same
+// even if handler rewrites URL.Path before reading them.
+// Parameters are bound once, even if they are first read
+// concurrently. Pattern(req) is available as usual.
+//
+// When parameters are already bound by an outer router,
+// like Host, or the pattern has a query section, they are
+// bound by matching, the way New binds them.
+func NewLazy(path string, handler interface{}) Router {
+	p := "/" + strings.TrimLeft(path, "/")
+	h := toHandler(handler)
+	if strings.IndexAny(p, ":*\\") == -1 || queryStart(p) != -1 {
+		return New(p, h)
+	}
+	segments, err := parse(p)
+	if err != nil {
+		panic(err.Error())
+	}
+	matches := matcher(segments, p[len(p)-1] == '/', false)
+	eager := New(p, h).(route)
+	src := eager.src
+
+	return route{RouterFunc(func(req *http.Request) http.Handler {
+		if boundParams(req) != nil {
+			return eager.RouterFunc(req)
+		}
+		if !matches(req.URL.Path, nil) {
+			return nil
+		}
+		ps := (*parameters)(src.alloc.Get(p, src.num))
+		ps.alloc = src.alloc
+		ps.pattern, ps.path, ps.lazy = p, req.URL.Path, matches
+		if ps.bind == nil {
+			ps.bind = ps.bindLazy // kept along with the set
+		}
+		ps.ReadCloser = req.Body
+		req.Body = ps
+		return ps.releasing(req, h)
+	}), p, h, src}
+}
+
+// NewNoParams creates Router, which matches path by
+// pattern the same way New does, but does not bind
+// parameters to request. It is meant for patterns,
+// which only shape the path, like "/:version/healthz",
+// where handler does not need parameter values.
+//
+// Such route is as cheap as a static one: there is
+// nothing to pool, bind or recycle. Parameters(req)
+// is empty within handler, so ByName returns "", and
+// Pattern(req) is the request path.
+func NewNoParams(path string, handler interface{}) Router {
+	p := "/" + strings.TrimLeft(path, "/")
+	h := toHandler(handler)
+	if strings.IndexAny(p, ":*\\") == -1 {
+		return New(p, h)
+	}
+	segments, err := parse(p)
+	if err != nil {
+		panic(err.Error())
+	}
+	matches := matcher(segments, p[len(p)-1] == '/', false)
+
+	return route{RouterFunc(func(req *http.Request) http.Handler {
+		if matches(req.URL.Path, nil) {
+			return h
 		}
+		return nil
+	}), p, h, nil}
+}
+
+// NewOptionalSlash creates Router, which matches path by
+// pattern the same way New does, but regardless of the
+// trailing slash. Both "/users/:id/" and "/users/:id"
+// patterns match "/users/5/" and "/users/5", instead of
+// registering a route for each. Parameter values never
+// include the trailing slash.
+//
+// Patterns ending with catch-all parameter match either
+// path anyway, so the route is the same as New creates.
+func NewOptionalSlash(path string, handler interface{}) Router {
+	p := "/" + strings.TrimLeft(path, "/")
+	h := toHandler(handler)
+	if strings.IndexAny(p, ":*\\") == -1 {
+		static := trimSlash(p)
+		return route{RouterFunc(func(req *http.Request) http.Handler {
+			if trimSlash(req.URL.Path) == static {
+				return h
+			}
+			return nil
+		}), p, h, nil}
+	}
+	segments, err := parse(p)
+	if err != nil {
+		panic(err.Error())
+	}
+	if segments[len(segments)-1][1] == '*' {
+		return New(p, h)
+	}
+
+	matches := matcher(segments, false, false)
+	num := strings.Count(p, ":") + strings.Count(p, "*")
+	return dynamic(p, num, h, func(path string, ps *Params) bool {
+		return matches(trimSlash(path), ps)
 	})
+}
+
+// trims trailing slash of path, unless it is the root
+// or slashes are repeated
+func trimSlash(path string) string {
+	if len(path) > 1 && path[len(path)-1] == '/' && path[len(path)-2] != '/' {
+		return path[:len(path)-1]
+	}
+	return path
+}
+
+func toHandler(handler interface{}) http.Handler {
+	h, err := handlerOf(handler)
+	if err != nil {
+		panic(err.Error())
+	}
+	return h
+}
+
+func handlerOf(handler interface{}) (http.Handler, error) {
+	switch t := handler.(type) {
+	case http.HandlerFunc:
+		return t, nil
+	case func(http.ResponseWriter, *http.Request):
+		return http.HandlerFunc(t), nil
+	case func(http.ResponseWriter, *http.Request) error:
+		return HandleErrors(t), nil
+	case http.Handler:
+		return t, nil
+	case nil:
+		return nil, errors.New("given handler cannot be: nil")
+	default:
+		return nil, fmt.Errorf("not a handler given: %T - %+v", t, t)
+	}
+}
+
+// splits pattern to segments, each starting with slash,
+// and validates parameters. Pattern is walked once and
+// segments are sliced from it, repeated slashes at both
+// ends are trimmed
+func parse(p string) ([]string, error) {
+	start, end := 0, len(p)
+	for end > 0 && p[end-1] == '/' {
+		end--
+	}
+	for start < end && p[start] == '/' {
+		start++
+	}
+	if start == end {
+		return []string{"/"}, nil
+	}
+
+	segments := make([]string, 0, strings.Count(p[start:end], "/")+1)
+	var catchAll bool
+	for start <= end {
+		next := start
+		for next < end && p[next] != '/' {
+			next++
+		}
+		seg := p[start:next]
+		if start == 0 || p[start-1] != '/' {
+			seg = "/" + seg // not rooted, like "a/:b"
+		} else {
+			seg = p[start-1 : next]
+		}
+		if strings.IndexByte(seg, '\\') != -1 {
+			var err error
+			if seg, err = unescape(seg); err != nil {
+				return nil, errors.New(err.Error() + ": " + p)
+			}
+		}
+		segments = append(segments, seg)
+		start = next + 1
+
+		pos := strings.IndexAny(seg, ":*")
+		if pos == -1 || seg[1] == '\\' {
+			continue // static segment
+		}
+		name := seg[pos+1:]
+		if at := strings.IndexByte(name, '\\'); at != -1 {
+			name = name[:at] // followed by literal suffix
+		}
+		switch {
+		case catchAll:
+			return nil, errors.New("match all, may only be followed by static segments in pattern: " + p)
+		case pos != 1:
+			return nil, errors.New("special param matching signs, must follow after slash: " + p)
+		case seg == "/*" && start > end:
+			// anonymous match all, binds nothing
+		case seg == "/*":
+			return nil, errors.New("anonymous match all, must be the last segment: " + p)
+		case name == "":
+			return nil, errors.New("param must be named after sign: " + p)
+		case strings.IndexAny(name, ":*") != -1:
+			return nil, errors.New("only one param per segment: " + p)
+		case seg[1] == '*' && len(name) != len(seg)-2:
+			return nil, errors.New("match all, may not be followed by literal suffix: " + p)
+		}
+		catchAll = seg[1] == '*'
+	}
+	return segments, nil
+}
+
+// resolves signs escaped by a backslash in segment. Literal
+// segment keeps a backslash following the slash, so it is not
+// taken for a parameter, while parameter segment keeps it in
+// between parameter name and literal suffix
+func unescape(seg string) (string, error) {
+	buf := make([]byte, 0, len(seg)+1)
+	from := 1
+	if seg[1] == ':' || seg[1] == '*' {
+		from = strings.IndexByte(seg, '\\')
+		buf = append(buf, seg[:from+1]...)
+	} else {
+		buf = append(buf, "/\\"...)
+	}
+	for i := from; i < len(seg); i++ {
+		switch c := seg[i]; {
+		case c == '\\' && i+1 == len(seg):
+			return "", errors.New("escape sign must be followed by a character")
+		case c == '\\':
+			i++
+			buf = append(buf, seg[i])
+		case c == ':' || c == '*':
+			return "", errors.New("special param matching signs, must follow after slash")
+		default:
+			buf = append(buf, c)
+		}
+	}
+	return string(buf), nil
+}
+
+// literal text of static segment
+func literal(segment string) string {
+	if len(segment) > 1 && segment[1] == '\\' {
+		return "/" + segment[2:]
+	}
+	return segment
+}
+
+// drops backslashes escaping signs in pattern
+func unquote(pattern string) string {
+	if strings.IndexByte(pattern, '\\') == -1 {
+		return pattern
+	}
+	buf := make([]byte, 0, len(pattern))
+	for i := 0; i < len(pattern); i++ {
+		if pattern[i] == '\\' && i+1 < len(pattern) {
+			i++
+		}
+		buf = append(buf, pattern[i])
+	}
+	return string(buf)
+}
+
+// creates route for pattern having at most num parameters
+func dynamic(p string, num int, h http.Handler, matches func(string, *Params) bool) route {
+	return prefilled(p, num, h, nil, matches, nil)
+}
+
+// creates route for pattern, which binds parameters of the given
+// keys in order. Its own pool writes the keys to every set it creates,
+// which keep them while recycled, so fills binds only values. Sets of
+// another allocator, or bound by outer router, are bound by matches
+func prefilled(p string, num int, h http.Handler, keys []string, matches, fills func(string, *Params) bool) route {
+	// pool for parameters, which may be shared
+	// or replaced by another allocator later
+	src := &poolSource{newParamsPool(num, keys...), num}
+	var own ParamAllocator
+	if fills != nil {
+		own = src.alloc
+	}
 
 	// dynamic route matcher
-	return RouterFunc(func(req *http.Request) http.Handler {
-		ps := pool.Get().(*parameters)
-		if match(segments, req.URL.Path, &ps.params, ts) {
+	// parameters may be already bound by outer router, like Host
+	return route{RouterFunc(func(req *http.Request) http.Handler {
+		ps := boundParams(req)
+		bound := ps != nil
+		bind := matches
+		if !bound {
+			ps = (*parameters)(src.alloc.Get(p, src.num))
+			ps.alloc = src.alloc
+			if ps.alloc == own {
+				bind = fills
+			}
+		}
+		n := len(ps.params)
+		if bind(req.URL.Path, &ps.params) {
+			ps.pattern = p
+			if bound {
+				return h // outer router recycles parameters
+			}
 			ps.ReadCloser = req.Body
 			req.Body = ps
-			return handle
+			return ps.releasing(req, h)
+		}
+		ps.params = ps.params[:n]
+		if !bound {
+			ps.alloc.Put((*ParamSet)(ps))
 		}
-		ps.params = ps.params[0:0]
-		pool.Put(ps)
 		return nil
-	})
+	}), p, h, src}
+}
+
+// creates matcher for pattern segments, which binds only
+// values of parameters, if keys were filled, see prefilled
+func matcher(segments []string, ts, filled bool) func(string, *Params) bool {
+	keys := paramKeys(segments)
+	if head, tail := splitTail(segments, ts); tail != "" {
+		return func(path string, ps *Params) bool {
+			return matchTail(head, keys, tail, path, ps, filled)
+		}
+	}
+	return func(path string, ps *Params) bool {
+		return match(segments, keys, path, ps, ts, filled)
+	}
 }
 
-// matches pattern segments to an url and pushes named parameters to ps
-func match(segments []string, url string, ps *Params, ts bool) bool {
-	for _, segment := range segments {
+// parameter keys of pattern segments, interned once, so matching
+// binds them as they are, without slicing segments or looking for
+// literal suffix. Keys of static segments are empty
+func paramKeys(segments []string) []string {
+	keys := make([]string, len(segments))
+	for i, segment := range segments {
+		if segment[1] != ':' && segment[1] != '*' {
+			continue
+		}
+		keys[i] = segment[2:]
+		if pos := strings.IndexByte(keys[i], '\\'); pos != -1 {
+			keys[i] = keys[i][:pos]
+		}
+	}
+	return keys
+}
+
+// keys of parameters pattern segments bind, in order
+func boundKeys(segments []string) []string {
+	var bound []string
+	for _, key := range paramKeys(segments) {
+		if key != "" {
+			bound = append(bound, key)
+		}
+	}
+	return bound
+}
+
+// splits segments having a catch-all followed by static segments,
+// to the ones ending with catch-all and the static tail, including
+// trailing slash. Tail is empty for other patterns
+func splitTail(segments []string, ts bool) ([]string, string) {
+	for i, segment := range segments[:len(segments)-1] {
+		if segment[1] != '*' {
+			continue
+		}
+		var tail string
+		for _, segment := range segments[i+1:] {
+			tail += literal(segment)
+		}
+		if ts {
+			tail += "/"
+		}
+		return segments[:i+1], tail
+	}
+	return segments, ""
+}
+
+// matches static tail to the path end first, the catch-all
+// ending head segments then takes whatever is left in between
+func matchTail(head, keys []string, tail, path string, ps *Params, filled bool) bool {
+	n := len(path) - len(tail)
+	return n > 0 && path[n:] == tail && match(head, keys, path[:n], ps, false, filled)
+}
+
+// matches pattern segments to an url and pushes named parameters to ps,
+// unless it is nil. The url is walked by index, so it is only sliced for
+// parameter values and literal comparison. Keys are the ones of segments
+// given by paramKeys. If ps was filled with the keys, only values are set
+func match(segments, keys []string, url string, ps *Params, ts, filled bool) bool {
+	var i int
+	for k, segment := range segments {
 		switch {
-		case len(url) == 0 || url[0] != '/':
+		case i == len(url) || url[i] != '/':
 			return false
-		case segment[1] == ':' && len(url) > 1:
-			end := 1
-			for end < len(url) && url[end] != '/' {
-				end++
+		case segment[1] == ':' && i+1 < len(url):
+			end := len(url)
+			if pos := strings.IndexByte(url[i+1:], '/'); pos != -1 {
+				end = i + 1 + pos
+			}
+			value := url[i+1 : end]
+			if len(segment) > len(keys[k])+2 {
+				suffix := segment[len(keys[k])+3:] // following escape sign
+				if len(value) <= len(suffix) || value[len(value)-len(suffix):] != suffix {
+					return false
+				}
+				value = value[:len(value)-len(suffix)]
 			}
-			ps.push(segment[2:], url[1:end])
-			url = url[end:]
+			if ps != nil {
+				ps.bind(keys[k], value, filled)
+			}
+			i = end
 		case segment[1] == '*':
-			ps.push(segment[2:], url)
+			if ps != nil && keys[k] != "" {
+				ps.bind(keys[k], url[i:], filled)
+			}
 			return true
-		case len(url) < len(segment) || url[:len(segment)] != segment:
+		case segment[1] == '\\':
+			if len(url)-i < len(segment)-1 || url[i+1:i+len(segment)-1] != segment[2:] {
+				return false
+			}
+			i += len(segment) - 1
+		case len(url)-i < len(segment) || url[i+1:i+len(segment)] != segment[1:]:
 			return false
 		default:
-			url = url[len(segment):]
+			i += len(segment)
 		}
 	}
-	return (!ts && url == "") || (ts && url == "/") // match trailing slash
+	// match trailing slash
+	if ts {
+		return len(url)-i == 1 && url[i] == '/'
+	}
+	return i == len(url)
 }
 
 type parameters struct {
 	io.ReadCloser
 	params  Params
 	pattern string
-	pool    *sync.Pool
+	alloc   ParamAllocator
+	next    http.Handler  // served by release
+	req     *http.Request // matched, next is served for it
+
+	// set by a combinator, like Host, while it routes the
+	// request, so routes append their params to the set.
+	// Kept is the number of params the combinator bound
+	binding bool
+	kept    int
+
+	// binds params of the path matched by NewLazy
+	// route, once they are read for the first time
+	lazy func(string, *Params) bool
+	path string
+	once sync.Once
+	bind func()
 }
 
 func (p *parameters) reset(req *http.Request) {
 	req.Body = p.ReadCloser
+	p.recycle()
+}
+
+func (p *parameters) bindLazy() {
+	p.lazy(p.path, &p.params)
+}
+
+// puts parameters back to the pool
+func (p *parameters) recycle() {
+	if p.alloc == nil {
+		return // attached by WithParams
+	}
+	poison(p.params)
 	p.params = p.params[0:0]
-	p.pool.Put(p)
+	p.next, p.req = nil, nil
+	p.binding, p.kept = false, 0
+	p.lazy, p.path = nil, ""
+	p.once = sync.Once{}
+	p.alloc.Put((*ParamSet)(p))
+}
+
+// parameters bound by a combinator, like Host, which is
+// routing the request, or nil. Parameters of a request,
+// which was already matched, like the one served by a
+// router mounted as a handler of another route, or the
+// ones attached by WithParams, are not bound, so routes
+// bind their own parameters on top of them
+func boundParams(req *http.Request) *parameters {
+	if p, _ := req.Body.(*parameters); p != nil && p.binding {
+		return p
+	}
+	return nil
+}
+
+// request body, unwrapped from parameters
+func requestBody(req *http.Request) io.ReadCloser {
+	if p, _ := req.Body.(*parameters); p != nil {
+		return p.ReadCloser
+	}
+	return req.Body
+}
+
+// serves h with request body replaced, placing it inside
+// parameters wrapper if any, so parameters remain bound,
+// and restores the original body afterwards
+func serveWithBody(w http.ResponseWriter, req *http.Request, body io.ReadCloser, h http.Handler) {
+	if p, _ := req.Body.(*parameters); p != nil {
+		original := p.ReadCloser
+		p.ReadCloser = body
+		h.ServeHTTP(w, req)
+		switch {
+		case req.Body == body:
+			req.Body = original // parameters were recycled
+		case req.Body == p:
+			p.ReadCloser = original
+		}
+		return
+	}
+
+	original := req.Body
+	req.Body = body
+	h.ServeHTTP(w, req)
+	req.Body = original
+}
+
+// release serves the next handler of parameters,
+// matched by a route or a combinator, like Host,
+// and recycles them once it returns
+type release parameters
+
+// makes parameters release h, matched for req
+func (p *parameters) releasing(req *http.Request, h http.Handler) http.Handler {
+	p.next, p.req = h, req
+	return (*release)(p)
+}
+
+func (p *release) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	h := (*parameters)(p).claim(req)
+	if h == nil {
+		unclaimed(w, req)
+		return
+	}
+	h.ServeHTTP(w, req)
+	Recycle(req)
+}
+
+// takes the next handler to serve, making sure parameters
+// are still the ones matched for the request, or a shallow
+// copy of it, sharing its URL, even if middleware replaced
+// its Body since, so a handler served twice cannot serve
+// parameters of another request. Returns nil otherwise
+func (p *parameters) claim(req *http.Request) http.Handler {
+	h := p.next
+	if h == nil || (p.req.URL != req.URL && carried(req) != p) {
+		return nil
+	}
+	p.next = nil
+	return h
 }
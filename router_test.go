@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"reflect"
 	"sort"
 	"strings"
 	"sync"
@@ -191,6 +192,338 @@ func TestShouldFallbackToNotFoundHandler(t *testing.T) {
 	}
 }
 
+type paramWriter string
+
+func (p paramWriter) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	fmt.Fprint(w, fastroute.Parameters(req).ByName(string(p)))
+}
+
+func TestShouldAcceptHandlerInterface(t *testing.T) {
+	t.Parallel()
+	router := fastroute.New("/x/:id", paramWriter("id"))
+
+	req, err := http.NewRequest("GET", "/x/5", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	if w.Body.String() != "5" {
+		t.Fatalf("expected id parameter to be available in handler, but got: %s", w.Body.String())
+	}
+
+	if params := fastroute.Parameters(req); params != nil {
+		t.Fatal("params should be reset after serving request")
+	}
+}
+
+func TestParamsSetAndAppend(t *testing.T) {
+	t.Parallel()
+
+	lowercase := func(router fastroute.Router) fastroute.Router {
+		return fastroute.RouterFunc(func(req *http.Request) http.Handler {
+			h := router.Route(req)
+			if h == nil {
+				return nil
+			}
+			params := fastroute.Parameters(req)
+			params.Set("slug", strings.ToLower(params.ByName("slug")))
+			if params.Set("missing", "x") {
+				t.Error("expected missing param not to be set")
+			}
+
+			params.Append("lang", "en")
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				h.ServeHTTP(w, fastroute.WithParams(r, params))
+			})
+		})
+	}
+
+	router := lowercase(fastroute.New("/posts/:slug", func(w http.ResponseWriter, req *http.Request) {
+		params := fastroute.Parameters(req)
+		fmt.Fprint(w, params.ByName("slug"), " ", params.ByName("lang"))
+	}))
+
+	req, err := http.NewRequest("GET", "/posts/Hello-World", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Body.String() != "hello-world en" {
+		t.Fatalf("expected normalized and appended params, but got: %s", w.Body.String())
+	}
+}
+
+func TestParamsSlice(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string][]string{
+		"/tree/a/b/c": {"a", "b", "c"},
+		"/tree/a":     {"a"},
+		"/tree/a/b/":  {"a", "b", ""},
+		"/tree/a//b":  {"a", "", "b"},
+		"/tree/":      nil,
+	}
+	router := fastroute.New("/tree/*segments", func(w http.ResponseWriter, req *http.Request) {})
+	for path, expected := range cases {
+		req, _ := http.NewRequest("GET", path, nil)
+		if router.Route(req) == nil {
+			t.Fatalf("expected %s to match", path)
+		}
+		if actual := fastroute.Parameters(req).Slice("segments"); !reflect.DeepEqual(actual, expected) {
+			t.Fatalf("expected segments of %s to be %q, but got: %q", path, expected, actual)
+		}
+		fastroute.Recycle(req)
+	}
+
+	if segments := (fastroute.Params{}).Slice("missing"); segments != nil {
+		t.Fatalf("expected no segments for missing param, but got: %q", segments)
+	}
+}
+
+func TestMustChain(t *testing.T) {
+	t.Parallel()
+
+	handler := func(w http.ResponseWriter, req *http.Request) {}
+	router := fastroute.MustChain(fastroute.Chain(), fastroute.New("/status", handler))
+	if actual := routed(router, "/status"); actual != "/status" {
+		t.Fatalf("expected chained route to match, but got %q", actual)
+	}
+	fastroute.MustChain(fastroute.RouterFunc(func(req *http.Request) http.Handler { return nil }))
+
+	defer func() {
+		if err := recover(); fmt.Sprint(err) != "fastroute: there are no routes to chain" {
+			t.Fatalf("expected empty chain to panic, but got: %v", err)
+		}
+	}()
+	fastroute.MustChain(fastroute.Chain(), fastroute.MethodGroup("GET", fastroute.Chain()))
+}
+
+func TestParamsEqual(t *testing.T) {
+	t.Parallel()
+
+	params := func(pairs ...string) fastroute.Params {
+		var ps fastroute.Params
+		for i := 0; i < len(pairs); i += 2 {
+			ps.Append(pairs[i], pairs[i+1])
+		}
+		return ps
+	}
+
+	cases := []struct {
+		a, b  fastroute.Params
+		equal bool
+	}{
+		{params(), nil, true},
+		{params("id", "5", "name", "john"), params("name", "john", "id", "5"), true},
+		{params("id", "5"), params("id", "6"), false},
+		{params("id", "5"), params("uid", "5"), false},
+		{params("id", "5"), params("id", "5", "id", "5"), false},
+		{params("tag", "a", "id", "5", "tag", "b"), params("tag", "a", "tag", "b", "id", "5"), true},
+		{params("tag", "a", "tag", "b"), params("tag", "b", "tag", "a"), false},
+		{params("tag", "a", "tag", "a"), params("tag", "a", "tag", "b"), false},
+		{params("tag", "a", "id", "5"), params("tag", "a", "tag", "a"), false},
+	}
+	for i, c := range cases {
+		if c.a.Equal(c.b) != c.equal || c.b.Equal(c.a) != c.equal {
+			t.Fatalf("expected %s and %s to be equal: %v, at case %d", c.a, c.b, c.equal, i)
+		}
+	}
+}
+
+func TestParamsString(t *testing.T) {
+	t.Parallel()
+
+	router := fastroute.New("/users/:id/*path", func(w http.ResponseWriter, req *http.Request) {})
+	req, _ := http.NewRequest("GET", "/users/5/a b", nil)
+	if router.Route(req) == nil {
+		t.Fatal("expected request to match")
+	}
+	defer fastroute.Recycle(req)
+
+	if s := fastroute.Parameters(req).String(); s != `[id="5" path="/a b"]` {
+		t.Fatalf("unexpected params formatted: %s", s)
+	}
+	if s := fmt.Sprint(fastroute.Params{}); s != "[]" {
+		t.Fatalf("unexpected empty params formatted: %s", s)
+	}
+}
+
+func TestAppendedParamsAreDetached(t *testing.T) {
+	t.Parallel()
+
+	// shared pool leaves spare capacity to the one param route
+	router := fastroute.SharePool(fastroute.Chain(
+		fastroute.New("/users/:id", func(w http.ResponseWriter, req *http.Request) {}),
+		fastroute.New("/:a/:b/:c", http.NotFoundHandler()),
+	))
+
+	// middleware extends params for the access log,
+	// which is written after the route is served
+	type retention struct {
+		path   string
+		params fastroute.Params
+	}
+	retained := make(chan retention, 200)
+	extend := fastroute.RouterFunc(func(req *http.Request) http.Handler {
+		h := router.Route(req)
+		if h == nil {
+			return nil
+		}
+		params := fastroute.Parameters(req)
+		id := params.ByName("id")
+		params.Append("role", "role-"+id)
+		params.Append("tenant", "tenant-"+id)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			h.ServeHTTP(w, r)
+			retained <- retention{r.URL.Path, params}
+		})
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < cap(retained); i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			req, _ := http.NewRequest("GET", fmt.Sprintf("/users/%d", i), nil)
+			extend.ServeHTTP(httptest.NewRecorder(), req)
+		}(i)
+	}
+	wg.Wait()
+	close(retained)
+
+	for r := range retained {
+		id := strings.TrimPrefix(r.path, "/users/")
+		expected := fastroute.Params{{"id", id}, {"role", "role-" + id}, {"tenant", "tenant-" + id}}
+		if !reflect.DeepEqual(r.params, expected) {
+			t.Fatalf("expected retained params: %v, but got: %v", expected, r.params)
+		}
+	}
+}
+
+func TestNewNoParams(t *testing.T) {
+	t.Parallel()
+	router := fastroute.Chain(
+		fastroute.NewNoParams("/:version/healthz", func(w http.ResponseWriter, req *http.Request) {
+			fmt.Fprintf(w, "%q %d %s", fastroute.Parameters(req).ByName("version"), len(fastroute.Parameters(req)), fastroute.Pattern(req))
+		}),
+		fastroute.NewNoParams("/", func(w http.ResponseWriter, req *http.Request) {
+			fmt.Fprint(w, "root")
+		}),
+	)
+
+	cases := map[string]string{
+		"/v1/healthz":  `"" 0 /v1/healthz`,
+		"/":            "root",
+		"/v1/healthz/": "404 page not found\n",
+		"//healthz":    "404 page not found\n",
+	}
+	for path, body := range cases {
+		req, err := http.NewRequest("GET", path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Body.String() != body {
+			t.Fatalf("expected response: %q for: %s, but got: %q", body, path, w.Body.String())
+		}
+	}
+}
+
+func TestNewOptionalSlash(t *testing.T) {
+	t.Parallel()
+	handler := func(w http.ResponseWriter, req *http.Request) {}
+
+	cases := []struct {
+		pattern string
+		paths   map[string]string
+	}{
+		{"/users/:id/", map[string]string{
+			"/users/5/":  "/users/:id/ id=5",
+			"/users/5":   "/users/:id/ id=5",
+			"/users/5//": "no match",
+			"/users/":    "no match",
+			"/users":     "no match",
+		}},
+		{"/users/:id", map[string]string{
+			"/users/5/": "/users/:id id=5",
+			"/users/5":  "/users/:id id=5",
+		}},
+		{"/proxy/*middle/status/", map[string]string{
+			"/proxy/a/b/status":  "/proxy/*middle/status/ middle=/a/b",
+			"/proxy/a/b/status/": "/proxy/*middle/status/ middle=/a/b",
+			"/proxy/status/":     "no match",
+		}},
+		{"/files/*path", map[string]string{
+			"/files/a/": "/files/*path path=/a/",
+			"/files/a":  "/files/*path path=/a",
+			"/files":    "no match",
+		}},
+		{"/docs/", map[string]string{
+			"/docs/":  "/docs/",
+			"/docs":   "/docs",
+			"/docs//": "no match",
+		}},
+		{"/", map[string]string{
+			"/":  "/",
+			"//": "no match",
+		}},
+	}
+
+	for _, c := range cases {
+		router := fastroute.NewOptionalSlash(c.pattern, handler)
+		for path, expected := range c.paths {
+			if actual := routed(router, path); actual != expected {
+				t.Fatalf("expected path: %s to be routed by: %s as %q, but got %q", path, c.pattern, expected, actual)
+			}
+		}
+	}
+
+	// strict by default
+	if actual := routed(fastroute.New("/users/:id/", handler), "/users/5"); actual != "no match" {
+		t.Fatalf("expected trailing slash to be required, but got: %q", actual)
+	}
+}
+
+func TestWithParams(t *testing.T) {
+	t.Parallel()
+	handler := paramWriter("id")
+
+	req, err := http.NewRequest("GET", "/users/5", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	routed := fastroute.WithParams(req, fastroute.Params{{"id", "5"}})
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, routed)
+
+	if w.Body.String() != "5" {
+		t.Fatalf("expected attached param to be available in handler, but got: %s", w.Body.String())
+	}
+	if fastroute.Parameters(req) != nil {
+		t.Fatal("expected original request to remain without params")
+	}
+	if fastroute.Pattern(routed) != "/users/5" {
+		t.Fatalf("unexpected pattern: %s", fastroute.Pattern(routed))
+	}
+
+	replaced := fastroute.WithParams(routed, fastroute.Params{{"id", "6"}})
+	if fastroute.Parameters(replaced).ByName("id") != "6" || len(fastroute.Parameters(replaced)) != 1 {
+		t.Fatalf("expected params to be replaced, but got: %v", fastroute.Parameters(replaced))
+	}
+
+	fastroute.Recycle(replaced)
+	if fastroute.Parameters(replaced) != nil || replaced.Body != req.Body {
+		t.Fatal("expected recycle to detach params")
+	}
+}
+
 func TestShouldParseForm(t *testing.T) {
 	t.Parallel()
 
@@ -284,6 +617,42 @@ func TestShouldRouteRouterAsHandler(t *testing.T) {
 	}
 }
 
+func TestShouldMountDynamicRouterUnderDynamicRoute(t *testing.T) {
+	t.Parallel()
+
+	sub := fastroute.Chain(fastroute.New("/users/:id/posts/:post", func(w http.ResponseWriter, req *http.Request) {
+		fmt.Fprintf(w, "%s %s", fastroute.Pattern(req), fastroute.Parameters(req))
+	}))
+	router := fastroute.New("/users/:id/*rest", func(w http.ResponseWriter, req *http.Request) {
+		sub.ServeHTTP(w, req)
+		fmt.Fprintf(w, " | %s %s", fastroute.Pattern(req), fastroute.Parameters(req))
+	})
+
+	for i := 0; i < 3; i++ { // reuses pooled parameters
+		req, _ := http.NewRequest("GET", "/users/5/posts/7", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		expected := `/users/:id/posts/:post [id="5" post="7"] | /users/:id/*rest [id="5" rest="/posts/7"]`
+		if w.Body.String() != expected {
+			t.Fatalf("expected mounted router to bind its own parameters: %q, but got: %q", expected, w.Body.String())
+		}
+		if len(fastroute.Parameters(req)) != 0 {
+			t.Fatalf("expected parameters to be recycled, but got: %s", fastroute.Parameters(req))
+		}
+	}
+
+	req, _ := http.NewRequest("GET", "/users/5/posts/7", nil)
+	req = fastroute.WithParams(req, fastroute.Params{{"tenant", "acme"}})
+	w := httptest.NewRecorder()
+	sub.ServeHTTP(w, req)
+	if w.Body.String() != `/users/:id/posts/:post [id="5" post="7"]` {
+		t.Fatalf("expected attached parameters not to be appended to, but got: %q", w.Body.String())
+	}
+	if ps := fastroute.Parameters(req); !ps.Equal(fastroute.Params{{"tenant", "acme"}}) {
+		t.Fatalf("expected attached parameters to remain, but got: %s", ps)
+	}
+}
+
 func TestEmptyRequestParameters(t *testing.T) {
 	t.Parallel()
 	req, err := http.NewRequest("GET", "/any", nil)
@@ -304,8 +673,8 @@ func TestEmptyRequestParameters(t *testing.T) {
 func TestRoutePatternValidation(t *testing.T) {
 	t.Parallel()
 	recoverOrFail(
-		"/path/*",
-		"param must be named after sign: /path/*",
+		"/path/*/a",
+		"anonymous match all, must be the last segment: /path/*/a",
 		http.NotFoundHandler(),
 		t,
 	)
@@ -346,8 +715,15 @@ func TestRoutePatternValidation(t *testing.T) {
 	)
 
 	recoverOrFail(
-		"/path/*all/more",
-		"match all, must be the last segment in pattern: /path/*all/more",
+		"/path/*all/:more",
+		"match all, may only be followed by static segments in pattern: /path/*all/:more",
+		http.NotFoundHandler(),
+		t,
+	)
+
+	recoverOrFail(
+		"/path/*all/more/*rest",
+		"match all, may only be followed by static segments in pattern: /path/*all/more/*rest",
 		http.NotFoundHandler(),
 		t,
 	)
@@ -355,6 +731,45 @@ func TestRoutePatternValidation(t *testing.T) {
 	recoverOrFail("/path", "given handler cannot be: nil", nil, t)
 }
 
+func TestTryNew(t *testing.T) {
+	t.Parallel()
+	handler := func(w http.ResponseWriter, req *http.Request) {}
+
+	cases := []struct {
+		path    string
+		handler interface{}
+		err     string
+	}{
+		{"/path/:/a", handler, "param must be named after sign: /path/:/a"},
+		{"/pa:/a", handler, "special param matching signs, must follow after slash: /pa:/a"},
+		{"/:user:/id", handler, "only one param per segment: /:user:/id"},
+		{"/path/*all/:more", handler, "match all, may only be followed by static segments in pattern: /path/*all/:more"},
+		{`/jobs/:id\`, handler, `escape sign must be followed by a character: /jobs/:id\`},
+		{`/jobs/:\:cancel`, handler, `param must be named after sign: /jobs/:\:cancel`},
+		{`/jobs/:id\:cancel:now`, handler, `special param matching signs, must follow after slash: /jobs/:id\:cancel:now`},
+		{`/files/*path\:raw`, handler, `match all, may not be followed by literal suffix: /files/*path\:raw`},
+		{`/a:b\*c`, handler, `special param matching signs, must follow after slash: /a:b\*c`},
+		{"/path", nil, "given handler cannot be: nil"},
+		{"/path", "MyHandler", "not a handler given: string - MyHandler"},
+	}
+	for _, c := range cases {
+		router, err := fastroute.TryNew(c.path, c.handler)
+		if err == nil || err.Error() != c.err || router != nil {
+			t.Fatalf("expected error: %q for: %s, but got: %v", c.err, c.path, err)
+		}
+	}
+
+	for _, path := range []string{"/users/:id", "/status"} {
+		router, err := fastroute.TryNew(path, handler)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if patterns, _ := fastroute.Patterns(router); len(patterns) != 1 || patterns[0] != path {
+			t.Fatalf("expected router for: %s, but got: %v", path, patterns)
+		}
+	}
+}
+
 func TestStaticRouteMatcher(t *testing.T) {
 	t.Parallel()
 	cases := map[string]bool{
@@ -488,6 +903,204 @@ func TestDynamicRouteMatcher(t *testing.T) {
 	}
 }
 
+func TestCatchAllFollowedByStaticSegments(t *testing.T) {
+	t.Parallel()
+	handler := func(w http.ResponseWriter, req *http.Request) {}
+	router := fastroute.Chain(
+		fastroute.New("/proxy/*middle/status", handler),
+		fastroute.New("/mirror/:host/*path/a/b/", handler),
+		fastroute.New("/*any/index.html", handler),
+	)
+
+	cases := map[string]string{
+		"/proxy/a/b/c/status":          "/proxy/*middle/status middle=/a/b/c",
+		"/proxy/a/status":              "/proxy/*middle/status middle=/a",
+		"/proxy/status/status":         "/proxy/*middle/status middle=/status",
+		"/proxy/a/status/b/status":     "/proxy/*middle/status middle=/a/status/b",
+		"/proxy//status":               "/proxy/*middle/status middle=/",
+		"/proxy/status":                "no match",
+		"/proxy/a/status/":             "no match",
+		"/proxy/a/xstatus":             "no match",
+		"/proxy/a/status/more":         "no match",
+		"/proxystatus":                 "no match",
+		"/mirror/h/x/y/a/b/":           "/mirror/:host/*path/a/b/ host=h path=/x/y",
+		"/mirror/h/a/b/a/b/":           "/mirror/:host/*path/a/b/ host=h path=/a/b",
+		"/mirror/h/a/b/":               "no match",
+		"/mirror/h/x/a/b":              "no match",
+		"/mirror/h//a/b/":              "/mirror/:host/*path/a/b/ host=h path=/",
+		"/index.html/index.html":       "/*any/index.html any=/index.html",
+		"/docs/index.html":             "/*any/index.html any=/docs",
+		"/index.html":                  "no match",
+		"/docs/index.html/":            "no match",
+		"/proxy/a/status/index.html":   "/*any/index.html any=/proxy/a/status",
+		"/mirror/h/x/a/b/index.html":   "/*any/index.html any=/mirror/h/x/a/b",
+		"/mirror/h/x/a/b/index.html/":  "no match",
+		"/mirror/h/x/a/b/index.htmlx/": "no match",
+	}
+
+	for path, expected := range cases {
+		if actual := routed(router, path); actual != expected {
+			t.Fatalf("expected path: %s to be routed as %q, but got %q", path, expected, actual)
+		}
+	}
+
+	noParams := fastroute.NewNoParams("/proxy/*middle/status", handler)
+	for path, expected := range cases {
+		req, err := http.NewRequest("GET", path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		matched := noParams.Route(req) != nil
+		if matched != strings.HasPrefix(expected, "/proxy/") {
+			t.Fatalf("expected path: %s match to be %v, but it was not", path, !matched)
+		}
+	}
+}
+
+func TestAnonymousCatchAll(t *testing.T) {
+	t.Parallel()
+	handler := func(w http.ResponseWriter, req *http.Request) {}
+	router := fastroute.Chain(
+		fastroute.New("/assets/*", handler),
+		fastroute.New("/:lang/docs/*", handler),
+	)
+
+	cases := map[string]string{
+		"/assets/":             "/assets/*",
+		"/assets/css/site.css": "/assets/*",
+		"/assets":              "no match",
+		"/en/docs/intro":       "/:lang/docs/* lang=en",
+		"/en/docs":             "no match",
+	}
+	for path, expected := range cases {
+		if actual := routed(router, path); actual != expected {
+			t.Fatalf("expected path: %s to be routed as %q, but got %q", path, expected, actual)
+		}
+	}
+
+	info := fastroute.Inspect(router)
+	if info[0].Params != nil || !info[0].CatchAll || !reflect.DeepEqual(info[1].Params, []string{"lang"}) || !info[1].CatchAll {
+		t.Fatalf("expected anonymous catch-all to be described without parameter, but got: %+v", info)
+	}
+}
+
+func TestEscapedSigns(t *testing.T) {
+	t.Parallel()
+	handler := func(w http.ResponseWriter, req *http.Request) {}
+	patterns := []string{
+		`/v1/jobs/:id\:cancel`,
+		`/v1/jobs/:id\:retry/`,
+		`/search/\*`,
+		`/files/a\*b\\c`,
+		`/\:lang/*rest`,
+		`/proxy/*middle/x\:y`,
+	}
+	routes := make([]fastroute.Router, len(patterns))
+	for i, pattern := range patterns {
+		routes[i] = fastroute.New(pattern, handler)
+	}
+	router := fastroute.Chain(routes...)
+
+	cases := map[string]string{
+		"/v1/jobs/42:cancel":  `/v1/jobs/:id\:cancel id=42`,
+		"/v1/jobs/a:b:cancel": `/v1/jobs/:id\:cancel id=a:b`,
+		"/v1/jobs/:cancel":    "no match",
+		"/v1/jobs/42":         "no match",
+		"/v1/jobs/42:cancel/": "no match",
+		"/v1/jobs/42:retry/":  `/v1/jobs/:id\:retry/ id=42`,
+		"/v1/jobs/42:retry":   "no match",
+		"/search/*":           `/search/\*`,
+		"/search/go":          "no match",
+		`/files/a*b\c`:        `/files/a\*b\\c`,
+		"/files/a*bc":         "no match",
+		"/:lang/docs":         `/\:lang/*rest rest=/docs`,
+		"/en/docs":            "no match",
+		"/proxy/a/b/x:y":      `/proxy/*middle/x\:y middle=/a/b`,
+		"/proxy/a/b/x:z":      "no match",
+	}
+	for path, expected := range cases {
+		if actual := routed(router, path); actual != expected {
+			t.Fatalf("expected path: %s to be routed as %q, but got %q", path, expected, actual)
+		}
+	}
+
+	// patterns reported keep escapes, so they can be registered again
+	reported, _ := fastroute.Patterns(router)
+	if !reflect.DeepEqual(reported, patterns) {
+		t.Fatalf("expected patterns: %v, but got: %v", patterns, reported)
+	}
+	routes = routes[:0]
+	for _, pattern := range reported {
+		routes = append(routes, fastroute.New(pattern, handler))
+	}
+	again := fastroute.Chain(routes...)
+	for path, expected := range cases {
+		if actual := routed(again, path); actual != expected {
+			t.Fatalf("expected path: %s to be routed as %q by registered again patterns, but got %q", path, expected, actual)
+		}
+	}
+
+	info := fastroute.Inspect(router)
+	if !reflect.DeepEqual(info[0].Params, []string{"id"}) || !info[2].Static || info[4].Static {
+		t.Fatalf("expected escaped signs not to be described as parameters, but got: %+v", info)
+	}
+}
+
+func TestPatterns(t *testing.T) {
+	t.Parallel()
+	handler := http.NotFoundHandler()
+
+	router := fastroute.Chain(
+		fastroute.New("/", handler),
+		fastroute.Chain(
+			fastroute.New("users/:id", handler),
+			fastroute.New("/files/*filepath", handler),
+		),
+	)
+
+	patterns, ok := fastroute.Patterns(router)
+	if !ok {
+		t.Fatal("expected router to be fully enumerable")
+	}
+	if exp := "/,/users/:id,/files/*filepath"; strings.Join(patterns, ",") != exp {
+		t.Fatalf("expected patterns: %s, but got: %v", exp, patterns)
+	}
+
+	opaque := fastroute.RouterFunc(func(req *http.Request) http.Handler {
+		return nil
+	})
+	patterns, ok = fastroute.Patterns(fastroute.Chain(router, opaque))
+	if ok {
+		t.Fatal("expected enumeration to be incomplete because of opaque router")
+	}
+	if len(patterns) != 3 {
+		t.Fatalf("expected enumerable patterns to be listed, but got: %v", patterns)
+	}
+
+	forwarded := struct {
+		fastroute.Router
+		fastroute.Patterner
+	}{opaque, router.(fastroute.Patterner)}
+	if patterns, ok = fastroute.Patterns(forwarded); !ok || len(patterns) != 3 {
+		t.Fatalf("expected forwarded patterns to be enumerated, but got: %v", patterns)
+	}
+
+	partial := struct {
+		fastroute.Router
+		fastroute.Patterner
+		fastroute.Opaquer
+	}{opaque, fastroute.Chain(router, opaque).(fastroute.Patterner), opaqueness(true)}
+	if patterns, ok = fastroute.Patterns(partial); ok || len(patterns) != 3 {
+		t.Fatalf("expected forwarded enumeration to remain incomplete, but got: %v %v", patterns, ok)
+	}
+}
+
+type opaqueness bool
+
+func (o opaqueness) Opaque() bool {
+	return bool(o)
+}
+
 func TestGenerated(t *testing.T) {
 	routes, pat := generateRoutes(60, 5)
 	pat = strings.Replace(pat, ":id", "param", 1)
@@ -519,6 +1132,45 @@ func Benchmark_1Param(b *testing.B) {
 	benchmark(b, router, req)
 }
 
+func Benchmark_1Param_NoParams(b *testing.B) {
+	router := fastroute.NewNoParams("/v1/users/:id", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("OK"))
+	})
+
+	req, err := http.NewRequest("GET", "/v1/users/5", nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	benchmark(b, router, req)
+}
+
+func Benchmark_6Segments(b *testing.B) {
+	router := fastroute.New("/repositories/:owner/:repo/issues/:number/labels", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(fastroute.Parameters(r).ByName("number")))
+	})
+
+	req, err := http.NewRequest("GET", "/repositories/DATA-DOG/fastroute/issues/42/labels", nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	benchmark(b, router, req)
+}
+
+func Benchmark_4Params(b *testing.B) {
+	router := fastroute.New("/repos/:owner/:repo/issues/:number/labels/:name", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(fastroute.Parameters(r).ByName("name")))
+	})
+
+	req, err := http.NewRequest("GET", "/repos/DATA-DOG/fastroute/issues/42/labels/bug", nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	benchmark(b, router, req)
+}
+
 func Benchmark_Static(b *testing.B) {
 	router := fastroute.New("/static/path/pattern", func(w http.ResponseWriter, r *http.Request) {
 		w.Write([]byte("OK"))
@@ -659,6 +1311,62 @@ func generateRoutes(num, segments int) (routes []fastroute.Router, last string)
 	return
 }
 
+// whether recycled parameters are poisoned, see Recycled
+var poisoning bool
+
+// not parallel, since it counts allocations
+func TestZeroAllocations(t *testing.T) {
+	if poisoning {
+		t.Skip("recycled parameters are poisoned in debug builds")
+	}
+	var id string
+	router := fastroute.Chain(
+		fastroute.New("/status", func(w http.ResponseWriter, r *http.Request) {}),
+		fastroute.New("/v1/users/:id", func(w http.ResponseWriter, r *http.Request) {
+			id = fastroute.Parameters(r).ByName("id")
+		}),
+	)
+
+	req, err := http.NewRequest("GET", "/v1/users/5", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := new(discardWriter)
+	router.ServeHTTP(w, req) // warm up the pool
+
+	if allocs := testing.AllocsPerRun(100, func() {
+		if router.Route(req) == nil {
+			t.Fatal("expected request to be matched")
+		}
+		fastroute.Recycle(req)
+	}); allocs != 0 {
+		t.Fatalf("expected matching to perform no allocations, but got: %v", allocs)
+	}
+
+	if allocs := testing.AllocsPerRun(100, func() {
+		router.ServeHTTP(w, req)
+	}); allocs != 0 {
+		t.Fatalf("expected serving to perform no allocations, but got: %v", allocs)
+	}
+	if id != "5" || fastroute.Parameters(req) != nil {
+		t.Fatalf("expected parameter to be served and recycled, but got: %q", id)
+	}
+}
+
+type discardWriter struct {
+	header http.Header
+}
+
+func (w *discardWriter) Header() http.Header {
+	if w.header == nil {
+		w.header = make(http.Header)
+	}
+	return w.header
+}
+
+func (w *discardWriter) Write(b []byte) (int, error) { return len(b), nil }
+func (w *discardWriter) WriteHeader(int)             {}
+
 func benchmark(b *testing.B, router fastroute.Router, req *http.Request) {
 	b.ReportAllocs()
 	b.ResetTimer()
@@ -667,3 +1375,85 @@ func benchmark(b *testing.B, router fastroute.Router, req *http.Request) {
 		fastroute.Recycle(req)
 	}
 }
+
+func TestNewLazy(t *testing.T) {
+	t.Parallel()
+	var params fastroute.Params
+	handler := func(w http.ResponseWriter, req *http.Request) {
+		pattern := fastroute.Pattern(req)
+		req.URL.Path = "/rewritten/by/handler"
+		params = append(fastroute.Params(nil), fastroute.Parameters(req)...)
+		w.Write([]byte(pattern))
+	}
+	router := fastroute.Chain(
+		fastroute.NewLazy("/status", handler),
+		fastroute.NewLazy("/repos/:owner/:repo/files/*path", handler),
+		fastroute.Host(":tenant.example.com", fastroute.NewLazy("/orders/:id", handler)),
+	)
+
+	cases := []struct {
+		url, pattern string
+		params       fastroute.Params
+	}{
+		{"http://localhost/status", "/status", nil},
+		{"http://localhost/repos/a/b/files/c/d.go", "/repos/:owner/:repo/files/*path", fastroute.Params{{"owner", "a"}, {"repo", "b"}, {"path", "/c/d.go"}}},
+		{"http://acme.example.com/orders/5", "/orders/:id", fastroute.Params{{"tenant", "acme"}, {"id", "5"}}},
+	}
+	for _, c := range cases {
+		req, _ := http.NewRequest("GET", c.url, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Body.String() != c.pattern || !reflect.DeepEqual(params, c.params) {
+			t.Fatalf("expected %s to match %s with %v, but got: %s with %v", c.url, c.pattern, c.params, w.Body.String(), params)
+		}
+		if fastroute.Parameters(req) != nil {
+			t.Fatalf("expected parameters to be recycled for: %s", c.url)
+		}
+	}
+
+	req, _ := http.NewRequest("GET", "/repos/a/b", nil)
+	if routed(router, "/repos/a/b") != "no match" || router.Route(req) != nil {
+		t.Fatal("expected lazy route not to match")
+	}
+}
+
+func TestNewLazyConcurrentReads(t *testing.T) {
+	t.Parallel()
+	router := fastroute.NewLazy("/repos/:owner/:repo", func(w http.ResponseWriter, req *http.Request) {
+		var wg sync.WaitGroup
+		reads := make([]string, 4)
+		for i := range reads {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				reads[i] = fastroute.Parameters(req).String()
+			}(i)
+		}
+		wg.Wait()
+		for _, read := range reads {
+			if read != reads[0] || fastroute.Parameters(req).ByName("repo") != "fastroute" {
+				t.Errorf("expected parameters to be bound once, but got: %v", reads)
+				return
+			}
+		}
+	})
+
+	for i := 0; i < 10; i++ {
+		req, _ := http.NewRequest("GET", "/repos/DATA-DOG/fastroute", nil)
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+}
+
+func Benchmark_4Params_Lazy(b *testing.B) {
+	router := fastroute.NewLazy("/repos/:owner/:repo/issues/:number/labels/:name", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("OK"))
+	})
+
+	req, err := http.NewRequest("GET", "/repos/DATA-DOG/fastroute/issues/42/labels/bug", nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	benchmark(b, router, req)
+}
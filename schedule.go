@@ -0,0 +1,178 @@
+package fastroute
+
+import (
+	"net/http"
+	"time"
+)
+
+// Clock tells the current time to schedules.
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// Scheduler switches routers by time, told by Clock.
+// Zero value uses system time, tests may inject a
+// clock instead of waiting for the time to come.
+type Scheduler struct {
+	Clock Clock
+}
+
+// Schedule routes requests to before router, until the
+// time switchAt, from when requests are routed to after
+// router. Either router may be nil, to match nothing.
+// See Scheduler.Schedule.
+func Schedule(before Router, switchAt time.Time, after Router) Router {
+	return Scheduler{}.Schedule(before, switchAt, after)
+}
+
+// During routes requests to router while the time is
+// within window, otherwise to elseRouter, which may
+// be nil. See Scheduler.During.
+func During(window Window, router, elseRouter Router) Router {
+	return Scheduler{}.During(window, router, elseRouter)
+}
+
+// DuringWindow routes requests to router while schedule
+// reports the current time is within it, otherwise they
+// fall through, for schedules not fitting Window, like a
+// list of maintenance slots. See Scheduler.DuringWindow.
+func DuringWindow(router Router, schedule func(time.Time) bool) Router {
+	return Scheduler{}.DuringWindow(router, schedule)
+}
+
+// Schedule routes requests to before router until the
+// time switchAt, the exact instant already being routed
+// to after router. If switchAt was derived from time.Now,
+// times are compared by monotonic clock, so the switch
+// is not affected by wall clock adjustments. Otherwise
+// the wall clock is compared.
+//
+// Routes of the router, which is not active at the
+// moment, are reported as Disabled by Inspect.
+func (s Scheduler) Schedule(before Router, switchAt time.Time, after Router) Router {
+	return s.switched(func(now time.Time) bool {
+		return now.Before(switchAt)
+	}, before, after)
+}
+
+// During routes requests to router while the time is
+// within window, otherwise to elseRouter.
+//
+// Routes of the router, which is not active at the
+// moment, are reported as Disabled by Inspect.
+func (s Scheduler) During(window Window, router, elseRouter Router) Router {
+	return s.switched(window.Contains, router, elseRouter)
+}
+
+// DuringWindow routes requests to router while schedule
+// returns true for the time told by Clock, otherwise
+// nothing is matched, so the following routes may match.
+//
+// Routes of router are reported as Disabled by Inspect,
+// while schedule returns false.
+func (s Scheduler) DuringWindow(router Router, schedule func(time.Time) bool) Router {
+	return s.switched(schedule, router, nil)
+}
+
+func (s Scheduler) switched(first func(time.Time) bool, a, b Router) Router {
+	clock := s.Clock
+	if clock == nil {
+		clock = systemClock{}
+	}
+
+	routers := make([]Router, 0, 2)
+	for _, r := range []Router{a, b} {
+		if r != nil {
+			routers = append(routers, r)
+		}
+	}
+
+	return scheduled{chain{RouterFunc(func(req *http.Request) http.Handler {
+		active := b
+		if first(clock.Now()) {
+			active = a
+		}
+		if active == nil {
+			return nil
+		}
+		return active.Route(req)
+	}), routers}, clock, first, a, b}
+}
+
+type scheduled struct {
+	chain
+	clock Clock
+	first func(time.Time) bool
+	a, b  Router
+}
+
+func (s scheduled) inspect() ([]RouteInfo, bool) {
+	first := s.first(s.clock.Now())
+
+	var routes []RouteInfo
+	complete := true
+	for i, r := range []Router{s.a, s.b} {
+		if r == nil {
+			continue
+		}
+		infos, ok := inspect(r)
+		complete = complete && ok
+		for j := range infos {
+			infos[j].Disabled = infos[j].Disabled || first != (i == 0)
+		}
+		routes = append(routes, infos...)
+	}
+	return routes, complete
+}
+
+// Window is a recurring time window, like business
+// hours or a weekly maintenance window.
+type Window struct {
+	// Start and End are offsets from the midnight. If
+	// End is before Start, window spans over midnight,
+	// ending on the next day.
+	Start, End time.Duration
+
+	// Weekdays the window starts on, any day if empty.
+	Weekdays []time.Weekday
+
+	// Location of the window, UTC if nil.
+	Location *time.Location
+}
+
+// Contains reports whether the time is within window.
+func (w Window) Contains(t time.Time) bool {
+	loc := w.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	t = t.In(loc)
+
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+	offset := t.Sub(midnight)
+	if w.Start <= w.End {
+		return offset >= w.Start && offset < w.End && w.startsOn(t.Weekday())
+	}
+	if offset >= w.Start {
+		return w.startsOn(t.Weekday()) // before midnight
+	}
+	return offset < w.End && w.startsOn(midnight.AddDate(0, 0, -1).Weekday())
+}
+
+func (w Window) startsOn(day time.Weekday) bool {
+	if len(w.Weekdays) == 0 {
+		return true
+	}
+	for _, d := range w.Weekdays {
+		if d == day {
+			return true
+		}
+	}
+	return false
+}
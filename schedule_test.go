@@ -0,0 +1,147 @@
+package fastroute_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func TestSchedule(t *testing.T) {
+	t.Parallel()
+
+	respond := func(body string) fastroute.Router {
+		return fastroute.New("/checkout/:id", func(w http.ResponseWriter, req *http.Request) {
+			w.Write([]byte(body + " " + fastroute.Parameters(req).ByName("id")))
+		})
+	}
+
+	switchAt := time.Date(2017, 3, 1, 12, 0, 0, 0, time.UTC)
+	clock := &fakeClock{now: switchAt.Add(-time.Nanosecond)}
+	router := fastroute.Scheduler{Clock: clock}.Schedule(respond("old"), switchAt, respond("new"))
+
+	serve := func() string {
+		req, _ := http.NewRequest("GET", "/checkout/5", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Body.String()
+	}
+
+	if body := serve(); body != "old 5" {
+		t.Fatalf("expected old router to be active before switch, but got: %s", body)
+	}
+	if routes := fastroute.Inspect(router); routes[0].Disabled || !routes[1].Disabled {
+		t.Fatalf("expected new route to be reported disabled: %+v", routes)
+	}
+
+	clock.now = switchAt
+	if body := serve(); body != "new 5" {
+		t.Fatalf("expected new router to be active at switch time, but got: %s", body)
+	}
+	if routes := fastroute.Inspect(router); !routes[0].Disabled || routes[1].Disabled {
+		t.Fatalf("expected old route to be reported disabled: %+v", routes)
+	}
+
+	retired := fastroute.Scheduler{Clock: clock}.Schedule(respond("old"), switchAt, nil)
+	req, _ := http.NewRequest("GET", "/checkout/5", nil)
+	if retired.Route(req) != nil {
+		t.Fatal("expected retired route not to match after switch")
+	}
+}
+
+func TestDuring(t *testing.T) {
+	t.Parallel()
+
+	clock := &fakeClock{}
+	maintenance := fastroute.New("/", func(w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte("maintenance"))
+	})
+	app := fastroute.New("/", func(w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte("app"))
+	})
+
+	window := fastroute.Window{
+		Start:    23 * time.Hour,
+		End:      2 * time.Hour,
+		Weekdays: []time.Weekday{time.Sunday},
+	}
+	router := fastroute.Scheduler{Clock: clock}.During(window, maintenance, app)
+
+	cases := map[string]string{
+		"2017-03-05T22:59:59Z": "app",         // sunday
+		"2017-03-05T23:00:00Z": "maintenance", // sunday
+		"2017-03-06T01:59:59Z": "maintenance", // monday, started on sunday
+		"2017-03-06T02:00:00Z": "app",
+		"2017-03-06T23:30:00Z": "app", // monday
+		"2017-03-05T01:00:00Z": "app", // sunday, started on saturday
+	}
+
+	for at, expected := range cases {
+		now, err := time.Parse(time.RFC3339, at)
+		if err != nil {
+			t.Fatal(err)
+		}
+		clock.now = now
+
+		req, _ := http.NewRequest("GET", "/", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Body.String() != expected {
+			t.Fatalf("expected: %s to be served at: %s, but got: %s", expected, at, w.Body.String())
+		}
+	}
+
+	business := fastroute.Window{Start: 9 * time.Hour, End: 17 * time.Hour, Location: time.FixedZone("EET", 2*3600)}
+	if !business.Contains(time.Date(2017, 3, 6, 7, 0, 0, 0, time.UTC)) || business.Contains(time.Date(2017, 3, 6, 15, 0, 0, 0, time.UTC)) {
+		t.Fatal("expected window to be evaluated in its location")
+	}
+}
+
+func TestDuringWindow(t *testing.T) {
+	t.Parallel()
+	handler := func(w http.ResponseWriter, req *http.Request) {}
+	slots := [][2]time.Time{
+		{time.Date(2017, 3, 1, 22, 0, 0, 0, time.UTC), time.Date(2017, 3, 2, 2, 0, 0, 0, time.UTC)},
+		{time.Date(2017, 3, 8, 22, 0, 0, 0, time.UTC), time.Date(2017, 3, 9, 2, 0, 0, 0, time.UTC)},
+	}
+	maintenance := func(now time.Time) bool {
+		for _, slot := range slots {
+			if !now.Before(slot[0]) && now.Before(slot[1]) {
+				return true
+			}
+		}
+		return false
+	}
+
+	clock := &fakeClock{}
+	router := fastroute.Chain(
+		fastroute.Scheduler{Clock: clock}.DuringWindow(fastroute.New("/*path", handler), maintenance),
+		fastroute.New("/users/:id", handler),
+	)
+	cases := map[time.Time]string{
+		time.Date(2017, 3, 1, 21, 59, 0, 0, time.UTC): "/users/:id id=5",
+		time.Date(2017, 3, 1, 22, 0, 0, 0, time.UTC):  "/*path path=/users/5",
+		time.Date(2017, 3, 2, 1, 59, 0, 0, time.UTC):  "/*path path=/users/5",
+		time.Date(2017, 3, 2, 2, 0, 0, 0, time.UTC):   "/users/:id id=5",
+		time.Date(2017, 3, 8, 23, 0, 0, 0, time.UTC):  "/*path path=/users/5",
+	}
+	for now, expected := range cases {
+		clock.now = now
+		if actual := routed(router, "/users/5"); actual != expected {
+			t.Fatalf("expected request at %s to be routed as %q, but got: %q", now, expected, actual)
+		}
+		if disabled := fastroute.Inspect(router)[0].Disabled; disabled != (expected == "/users/:id id=5") {
+			t.Fatalf("expected route to be reported disabled: %v at %s", !disabled, now)
+		}
+	}
+}
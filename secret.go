@@ -0,0 +1,37 @@
+package fastroute
+
+import (
+	"crypto/subtle"
+	"net/http"
+)
+
+// SecretEquals creates Guard check, which compares the
+// value of named path parameter to expected secret in
+// constant time, for capability tokens embedded in URLs:
+//
+//	fastroute.Guard(
+//		fastroute.New("/hooks/:secret/github", hook),
+//		fastroute.SecretEquals("secret", token),
+//	)
+//
+// Static segments of patterns are compared as any other
+// string, with an early exit on the first differing byte,
+// so secrets must be matched as parameters and checked by
+// SecretEquals. The comparison takes the same time for any
+// value of the same length as expected, but it returns
+// early when lengths differ, so the length of a secret is
+// not hidden. Use secrets of a fixed length, like hex
+// encoded random tokens, where it is of no help.
+//
+// The check returns ErrForbidden if the value differs,
+// GuardResponder may answer 404 Not Found instead, not to
+// reveal the route.
+func SecretEquals(name, expected string) func(*http.Request) error {
+	secret := []byte(expected)
+	return func(req *http.Request) error {
+		if subtle.ConstantTimeCompare([]byte(Parameters(req).ByName(name)), secret) == 1 {
+			return nil
+		}
+		return ErrForbidden
+	}
+}
@@ -0,0 +1,56 @@
+package fastroute_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+func TestSecretEquals(t *testing.T) {
+	t.Parallel()
+	handler := func(w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte("hooked"))
+	}
+	router := fastroute.Guard(
+		fastroute.New("/hooks/:secret/github", handler),
+		fastroute.SecretEquals("secret", "3f2a9c"),
+	)
+
+	cases := []struct {
+		path string
+		code int
+	}{
+		{"/hooks/3f2a9c/github", 200},
+		{"/hooks/3f2a9d/github", 403},
+		{"/hooks/3f2a9/github", 403},
+		{"/hooks/3f2a9c0/github", 403},
+		{"/hooks/3f2a9c/gitlab", 404},
+	}
+	for _, c := range cases {
+		req, _ := http.NewRequest("GET", c.path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != c.code {
+			t.Fatalf("expected %s to respond with %d, but got %d", c.path, c.code, w.Code)
+		}
+	}
+}
+
+func Benchmark_SecretEquals(b *testing.B) {
+	handler := func(w http.ResponseWriter, req *http.Request) {}
+	router := fastroute.Guard(
+		fastroute.New("/hooks/:secret/github", handler),
+		fastroute.SecretEquals("secret", "6b86b273ff34fce19d6b804eff5a3f57"),
+	)
+	req, _ := http.NewRequest("GET", "/hooks/6b86b273ff34fce19d6b804eff5a3f57/github", nil)
+	benchmark(b, router, req)
+}
+
+func Benchmark_SecretParam(b *testing.B) {
+	handler := func(w http.ResponseWriter, req *http.Request) {}
+	router := fastroute.New("/hooks/:secret/github", handler)
+	req, _ := http.NewRequest("GET", "/hooks/6b86b273ff34fce19d6b804eff5a3f57/github", nil)
+	benchmark(b, router, req)
+}
@@ -0,0 +1,106 @@
+package fastroute
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// FromServeMuxPattern creates Router for a pattern of
+// http.ServeMux, easing migration from it:
+//
+//	[METHOD ][HOST]/[PATH]
+//
+// Wildcards of Go 1.22 are converted to parameters, while
+// method and host scope the route the way MethodGroup and
+// Host do:
+//
+//	GET /items/{id}         becomes   MethodGroup("GET|HEAD", New("/items/:id"))
+//	/files/{path...}        becomes   New("/files/*path")
+//	example.com/static/     becomes   Host("example.com", New("/static/*"))
+//	/posts/{$}              becomes   New("/posts/")
+//
+// Like ServeMux, GET matches HEAD requests too, and a path
+// ending with a slash matches the whole subtree, unless it
+// ends with {$}. Colons and asterisks of literal segments
+// are escaped.
+//
+// Not supported are ServeMux features, which do not fit a
+// chain of routes: patterns are not ordered by specificity,
+// so more specific routes must be chained first, conflicting
+// patterns are not detected, "/dir" is not redirected to
+// "/dir/", and paths are not cleaned. Wildcards must span
+// whole segments, as ServeMux requires. Percent encoded
+// pattern segments are matched as they are written.
+//
+// It returns an error, if pattern cannot be converted, or
+// handler is invalid.
+func FromServeMuxPattern(pattern string, handler interface{}) (Router, error) {
+	invalid := func(reason string) (Router, error) {
+		return nil, fmt.Errorf("servemux pattern %q cannot be converted: %s", pattern, reason)
+	}
+
+	rest, method := strings.TrimSpace(pattern), ""
+	if pos := strings.IndexAny(rest, " \t"); pos != -1 {
+		method, rest = rest[:pos], strings.TrimLeft(rest[pos:], " \t")
+		if !validMethod(method) {
+			return invalid("not a valid request method")
+		}
+	}
+	slash := strings.IndexByte(rest, '/')
+	if slash == -1 {
+		return invalid("path must be given")
+	}
+	host, path := rest[:slash], rest[slash:]
+
+	segments := strings.Split(path[1:], "/")
+	for i, seg := range segments {
+		last := i == len(segments)-1
+		switch {
+		case seg == "{$}" && last:
+			segments[i] = ""
+		case seg == "" && last:
+			segments[i] = "*" // subtree
+		case strings.IndexAny(seg, "{}") == -1:
+			segments[i] = escapeSigns(seg)
+		case seg[0] != '{' || seg[len(seg)-1] != '}':
+			return invalid("wildcard must be a whole segment")
+		default:
+			name, sign := seg[1:len(seg)-1], ":"
+			if strings.HasSuffix(name, "...") && last {
+				name, sign = name[:len(name)-3], "*"
+			}
+			if !identifier(name) {
+				return invalid("wildcard must be named by an identifier")
+			}
+			segments[i] = sign + name
+		}
+	}
+
+	router, err := TryNew("/"+strings.Join(segments, "/"), handler)
+	if err != nil {
+		return invalid(err.Error())
+	}
+	if host != "" {
+		router = Host(host, router)
+	}
+	switch method {
+	case "":
+		return router, nil
+	case "GET":
+		return MethodGroup("GET|HEAD", router), nil
+	default:
+		return MethodGroup(method, router), nil
+	}
+}
+
+// whether name is a Go identifier, as ServeMux
+// wildcard names must be
+func identifier(name string) bool {
+	for i, c := range name {
+		if c != '_' && !unicode.IsLetter(c) && (i == 0 || !unicode.IsDigit(c)) {
+			return false
+		}
+	}
+	return name != ""
+}
@@ -0,0 +1,63 @@
+package fastroute_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+func TestFromServeMuxPattern(t *testing.T) {
+	t.Parallel()
+	handler := func(w http.ResponseWriter, req *http.Request) {}
+
+	cases := []struct {
+		pattern, method, target, expected string
+	}{
+		{"GET /items/{id}", "GET", "http://localhost/items/5", "/items/:id id=5"},
+		{"GET /items/{id}", "HEAD", "http://localhost/items/5", "/items/:id id=5"},
+		{"GET /items/{id}", "POST", "http://localhost/items/5", "no match"},
+		{"POST /items", "POST", "http://localhost/items", "/items"},
+		{"/files/{path...}", "PUT", "http://localhost/files/a/b.txt", "/files/*path path=/a/b.txt"},
+		{"/static/", "GET", "http://localhost/static/css/a.css", "/static/*"},
+		{"/static/", "GET", "http://localhost/static/", "/static/*"},
+		{"/static/", "GET", "http://localhost/static", "no match"},
+		{"/posts/{$}", "GET", "http://localhost/posts/", "/posts/"},
+		{"/posts/{$}", "GET", "http://localhost/posts/1", "no match"},
+		{"/", "GET", "http://localhost/anything", "/*"},
+		{"/{$}", "GET", "http://localhost/", "/"},
+		{"/{$}", "GET", "http://localhost/a", "no match"},
+		{"example.com/admin/{page}", "GET", "http://example.com/admin/users", "/admin/:page page=users"},
+		{"example.com/admin/{page}", "GET", "http://other.com/admin/users", "no match"},
+		{"DELETE example.com/jobs/{id}", "DELETE", "http://example.com/jobs/1", "/jobs/:id id=1"},
+		{"/v1/jobs:batch", "POST", "http://localhost/v1/jobs:batch", `/v1/jobs\:batch`},
+		{"/faq?", "GET", "http://localhost/faq%3F", `/faq\?`},
+	}
+	for _, c := range cases {
+		router, err := fastroute.FromServeMuxPattern(c.pattern, handler)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req, _ := http.NewRequest(c.method, c.target, nil)
+		actual := "no match"
+		if router.Route(req) != nil {
+			actual = fastroute.Pattern(req)
+			for _, p := range fastroute.Parameters(req) {
+				actual += " " + p.Key + "=" + p.Value
+			}
+			fastroute.Recycle(req)
+		}
+		if actual != c.expected {
+			t.Fatalf("expected %s %s to be routed by %q as %q, but got %q", c.method, c.target, c.pattern, c.expected, actual)
+		}
+	}
+
+	for _, pattern := range []string{"GET", "G@T /items", "/items/{id", "/items/x{id}", "/items/{}", "/items/{1d}", "/{path...}/more", "/a/{$}/b", "/a/{x.y}"} {
+		if _, err := fastroute.FromServeMuxPattern(pattern, handler); err == nil {
+			t.Fatalf("expected an error for pattern: %s", pattern)
+		}
+	}
+	if _, err := fastroute.FromServeMuxPattern("/items", nil); err == nil {
+		t.Fatal("expected an error for nil handler")
+	}
+}
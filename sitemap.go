@@ -0,0 +1,188 @@
+package fastroute
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// SitemapLimit is the maximum number of URLs in a single
+// sitemap, as defined by the sitemaps protocol.
+const SitemapLimit = 50000
+
+// SitemapOption configures Sitemap.
+type SitemapOption func(*sitemap)
+
+type sitemap struct {
+	base    string
+	expand  func(pattern string) []Params
+	exclude map[string]bool
+}
+
+// SitemapExpand sets the function, which lists concrete
+// parameters of dynamic route pattern, for example slugs
+// of all blog posts for "/blog/:slug". Each of them is
+// listed in sitemap, with parameters put in place.
+func SitemapExpand(expand func(pattern string) []Params) SitemapOption {
+	return func(s *sitemap) {
+		s.expand = expand
+	}
+}
+
+// SitemapExclude excludes routes of the given
+// patterns from sitemap, for example private ones.
+func SitemapExclude(patterns ...string) SitemapOption {
+	return func(s *sitemap) {
+		for _, pattern := range patterns {
+			s.exclude[pattern] = true
+		}
+	}
+}
+
+// Sitemap creates a handler, serving sitemap.xml of
+// router routes, as described by Inspect, prefixed with
+// base URL, like "https://example.com".
+//
+// Static routes, accepting GET requests, are listed,
+// unless they are disabled or excluded. Dynamic routes
+// are only listed if SitemapExpand option is given.
+// The route table is read on every request, and URLs
+// are written out as they are listed.
+//
+// If there are more URLs than SitemapLimit, a sitemap
+// index is served instead, referring to the pages of
+// sitemap, served by the same handler with "page" query
+// parameter.
+//
+// It returns an error if base is not an absolute URL.
+func Sitemap(router Router, base string, options ...SitemapOption) (http.Handler, error) {
+	if u, err := url.Parse(base); err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("not a valid sitemap base url: %s", base)
+	}
+	s := &sitemap{base: strings.TrimRight(base, "/"), exclude: make(map[string]bool)}
+	for _, option := range options {
+		option(s)
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		routes := Inspect(router)
+
+		var total int
+		s.each(routes, func(string) bool {
+			total++
+			return true
+		})
+
+		page, err := strconv.Atoi(req.URL.Query().Get("page"))
+		pages := (total + SitemapLimit - 1) / SitemapLimit
+		switch {
+		case err != nil && total > SitemapLimit:
+			page = 0 // index
+		case err != nil:
+			page = 1
+		case page < 1 || page > pages:
+			http.NotFound(w, req)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+		out := bufio.NewWriter(w)
+		defer out.Flush()
+		io.WriteString(out, xml.Header)
+
+		if page == 0 {
+			io.WriteString(out, `<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">`+"\n")
+			loc := s.base + req.URL.Path + "?page="
+			for i := 1; i <= pages; i++ {
+				writeLoc(out, "sitemap", loc+strconv.Itoa(i))
+			}
+			io.WriteString(out, "</sitemapindex>\n")
+			return
+		}
+
+		io.WriteString(out, `<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">`+"\n")
+		var n int
+		s.each(routes, func(loc string) bool {
+			n++
+			if n > (page-1)*SitemapLimit {
+				writeLoc(out, "url", loc)
+			}
+			return n < page*SitemapLimit
+		})
+		io.WriteString(out, "</urlset>\n")
+	}), nil
+}
+
+// calls fn with every URL listed in sitemap,
+// until it returns false
+func (s *sitemap) each(routes []RouteInfo, fn func(loc string) bool) {
+	for _, info := range routes {
+		if info.Disabled || s.exclude[info.Pattern] || !acceptsGet(info.Methods) {
+			continue
+		}
+		if info.Static {
+			if !fn(s.base + escapePath(unquote(info.Pattern))) {
+				return
+			}
+			continue
+		}
+		if s.expand == nil {
+			continue
+		}
+		for _, params := range s.expand(info.Pattern) {
+			if !fn(s.base + escapePath(fill(info.Pattern, params))) {
+				return
+			}
+		}
+	}
+}
+
+func acceptsGet(methods []string) bool {
+	if methods == nil {
+		return true
+	}
+	for _, method := range methods {
+		if method == "GET" {
+			return true
+		}
+	}
+	return false
+}
+
+// puts parameters in place of pattern segments
+func fill(pattern string, params Params) string {
+	segments := strings.Split(pattern, "/")
+	for i, seg := range segments {
+		if len(seg) < 2 {
+			continue
+		}
+		switch seg[0] {
+		case ':':
+			if pos := strings.IndexByte(seg, '\\'); pos != -1 {
+				segments[i] = params.ByName(seg[1:pos]) + unquote(seg[pos:])
+			} else {
+				segments[i] = params.ByName(seg[1:])
+			}
+		case '*':
+			segments[i] = strings.TrimPrefix(params.ByName(seg[1:]), "/")
+		default:
+			segments[i] = unquote(seg)
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+func escapePath(path string) string {
+	return (&url.URL{Path: path}).String()
+}
+
+func writeLoc(w io.Writer, tag, loc string) {
+	io.WriteString(w, "<"+tag+"><loc>")
+	xml.EscapeText(w, []byte(loc))
+	io.WriteString(w, "</loc></"+tag+">\n")
+}
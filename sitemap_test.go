@@ -0,0 +1,107 @@
+package fastroute_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+func TestSitemap(t *testing.T) {
+	t.Parallel()
+	handler := func(w http.ResponseWriter, req *http.Request) {}
+
+	router := fastroute.Chain(
+		fastroute.New("/", handler),
+		fastroute.MethodGroup("GET|HEAD",
+			fastroute.New("/about", handler),
+			fastroute.New("/blog/:slug", handler),
+			fastroute.New("/files/*path", handler),
+		),
+		fastroute.MethodGroup("POST", fastroute.New("/contact", handler)),
+		fastroute.New("/admin", handler),
+		fastroute.New("/users/:id", handler),
+	)
+
+	sitemap, err := fastroute.Sitemap(router, "https://example.com/",
+		fastroute.SitemapExclude("/admin"),
+		fastroute.SitemapExpand(func(pattern string) []fastroute.Params {
+			switch pattern {
+			case "/blog/:slug":
+				return []fastroute.Params{{{"slug", "hello"}}, {{"slug", "a b&c"}}}
+			case "/files/*path":
+				return []fastroute.Params{{{"path", "/docs/a.pdf"}}}
+			}
+			return nil
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req, _ := http.NewRequest("GET", "/sitemap.xml", nil)
+	w := httptest.NewRecorder()
+	sitemap.ServeHTTP(w, req)
+
+	expected := `<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+<url><loc>https://example.com/</loc></url>
+<url><loc>https://example.com/about</loc></url>
+<url><loc>https://example.com/blog/hello</loc></url>
+<url><loc>https://example.com/blog/a%20b&amp;c</loc></url>
+<url><loc>https://example.com/files/docs/a.pdf</loc></url>
+</urlset>
+`
+	if w.Body.String() != expected {
+		t.Fatalf("expected sitemap:\n%s\nbut got:\n%s", expected, w.Body.String())
+	}
+
+	if _, err := fastroute.Sitemap(router, "/relative"); err == nil {
+		t.Fatal("expected an error for relative base url")
+	}
+}
+
+func TestSitemapIndex(t *testing.T) {
+	t.Parallel()
+
+	params := make([]fastroute.Params, fastroute.SitemapLimit+1)
+	for i := range params {
+		params[i] = fastroute.Params{{"id", strconv.Itoa(i)}}
+	}
+	sitemap, err := fastroute.Sitemap(
+		fastroute.New("/items/:id", func(w http.ResponseWriter, req *http.Request) {}),
+		"https://example.com",
+		fastroute.SitemapExpand(func(pattern string) []fastroute.Params {
+			return params
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	serve := func(query string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", "/sitemap.xml"+query, nil)
+		w := httptest.NewRecorder()
+		sitemap.ServeHTTP(w, req)
+		return w
+	}
+
+	index := serve("").Body.String()
+	if !strings.Contains(index, "<sitemap><loc>https://example.com/sitemap.xml?page=2</loc></sitemap>") || strings.Contains(index, "page=3") {
+		t.Fatalf("expected sitemap index of two pages, but got:\n%s", index)
+	}
+
+	if first := serve("?page=1").Body.String(); strings.Count(first, "<url>") != fastroute.SitemapLimit {
+		t.Fatalf("expected first page to be full, but got %d urls", strings.Count(first, "<url>"))
+	}
+	last := serve("?page=2").Body.String()
+	if strings.Count(last, "<url>") != 1 || !strings.Contains(last, "/items/50000<") {
+		t.Fatalf("expected last url on the second page, but got:\n%s", last)
+	}
+	if w := serve("?page=3"); w.Code != http.StatusNotFound {
+		t.Fatalf("expected not found for page out of range, but got: %d", w.Code)
+	}
+}
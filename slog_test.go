@@ -0,0 +1,74 @@
+//go:build go1.21
+// +build go1.21
+
+package fastroute_test
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+func TestWithSlog(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey && len(groups) == 0 {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+
+	router := fastroute.WithSlog(fastroute.Chain(
+		fastroute.New("/users/:id/*path", func(w http.ResponseWriter, req *http.Request) {
+			fastroute.Logger(req).Info("served")
+		}),
+		fastroute.New("/status", func(w http.ResponseWriter, req *http.Request) {
+			fastroute.Logger(req).Info("status")
+		}),
+	), logger)
+
+	for _, path := range []string{"/users/5/a/b", "/status"} {
+		req, _ := http.NewRequest("GET", path, nil)
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	expected := `level=INFO msg=served route=/users/:id/*path params.id=5 params.path=/a/b
+level=INFO msg=status route=/status
+`
+	if buf.String() != expected {
+		t.Fatalf("expected log:\n%s\nbut got:\n%s", expected, buf.String())
+	}
+
+	if fastroute.Logger(httptest.NewRequest("GET", "/", nil)) != slog.Default() {
+		t.Fatal("expected default logger for request not served by WithSlog")
+	}
+}
+
+func TestParamsLogValue(t *testing.T) {
+	t.Parallel()
+
+	router := fastroute.New("/users/:id", func(w http.ResponseWriter, req *http.Request) {})
+	req, _ := http.NewRequest("GET", "/users/5", nil)
+	if router.Route(req) == nil {
+		t.Fatal("expected request to match")
+	}
+	value := fastroute.Parameters(req).LogValue()
+	fastroute.Recycle(req)
+
+	// reuse pooled parameters by another request
+	other, _ := http.NewRequest("GET", "/users/7", nil)
+	router.Route(other)
+	defer fastroute.Recycle(other)
+
+	if s := value.String(); !strings.Contains(s, "id=5") || value.Kind() != slog.KindGroup {
+		t.Fatalf("expected params to be copied to log value, but got: %s", s)
+	}
+}
@@ -0,0 +1,81 @@
+package fastroute
+
+import (
+	"net/http"
+	"path"
+	"strings"
+)
+
+// SPA creates Router for a single page application, which
+// files are in fsys, served under prefix, like "/" or "/app":
+//
+//	fastroute.Chain(
+//		fastroute.New("/api/*path", apiNotFound),
+//		fastroute.SPA("/", http.Dir("dist"), "/index.html"),
+//	)
+//
+// GET and HEAD requests of files are served as they are,
+// with content type and caching validators, by
+// http.ServeContent. Navigation requests, which accept
+// text/html, of anything else are served the index
+// document, with Cache-Control set to no-cache, so the
+// application routes them on the client. Other requests,
+// like the ones of API clients, or of missing scripts,
+// are not matched, so a following router may answer them.
+//
+// Paths are cleaned before files are opened, so they cannot
+// refer to files outside fsys. Directories are not listed,
+// they are treated as missing files, so navigation to them
+// is served the index document.
+func SPA(prefix string, fsys http.FileSystem, index string) Router {
+	base := normalizeBase(prefix)
+	index = path.Clean("/" + index)
+
+	return RouterFunc(func(req *http.Request) http.Handler {
+		if req.Method != "GET" && req.Method != "HEAD" {
+			return nil
+		}
+		rest, ok := trimSegments(req.URL.Path, base)
+		if !ok {
+			return nil
+		}
+		if name := path.Clean("/" + rest); isFile(fsys, name) {
+			return serveFile(fsys, name, false)
+		}
+		if strings.Contains(req.Header.Get("Accept"), "text/html") {
+			return serveFile(fsys, index, true)
+		}
+		return nil
+	})
+}
+
+// whether name is a file, which is not a directory
+func isFile(fsys http.FileSystem, name string) bool {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	return err == nil && !info.IsDir()
+}
+
+func serveFile(fsys http.FileSystem, name string, revalidate bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		f, err := fsys.Open(name)
+		if err != nil {
+			http.NotFound(w, req)
+			return
+		}
+		defer f.Close()
+		info, err := f.Stat()
+		if err != nil || info.IsDir() {
+			http.NotFound(w, req)
+			return
+		}
+		if revalidate {
+			w.Header().Set("Cache-Control", "no-cache")
+		}
+		http.ServeContent(w, req, info.Name(), info.ModTime(), f)
+	})
+}
@@ -0,0 +1,100 @@
+package fastroute_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+func TestSPA(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "spa")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	files := map[string]string{
+		"index.html":      "<html>app</html>",
+		"assets/app.js":   "console.log(1)",
+		"assets/app.css":  "body{}",
+		"docs/readme.txt": "readme",
+	}
+	for name, content := range files {
+		file := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(file, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	secret := dir + "-secret.txt"
+	if err := ioutil.WriteFile(secret, []byte("secret"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(secret)
+
+	apiNotFound := func(w http.ResponseWriter, req *http.Request) {
+		http.Error(w, `{"error":"not found"}`, http.StatusNotFound)
+	}
+	router := fastroute.Chain(
+		fastroute.New("/app/api/*path", apiNotFound),
+		fastroute.SPA("/app/", http.Dir(dir), "index.html"),
+	)
+
+	const html = "text/html"
+	cases := []struct {
+		method, path, accept string
+		code                 int
+		body, contentType    string
+	}{
+		{"GET", "/app/assets/app.js", "*/*", 200, "console.log(1)", "text/javascript; charset=utf-8"},
+		{"GET", "/app/assets/app.css", "text/css", 200, "body{}", "text/css; charset=utf-8"},
+		{"HEAD", "/app/docs/readme.txt", "", 200, "", "text/plain; charset=utf-8"},
+		{"GET", "/app/users/5", html, 200, "<html>app</html>", "text/html; charset=utf-8"},
+		{"GET", "/app", html, 200, "<html>app</html>", "text/html; charset=utf-8"},
+		{"GET", "/app/docs/", html, 200, "<html>app</html>", "text/html; charset=utf-8"},
+		{"GET", "/app/docs", "*/*", 404, "404 page not found\n", "text/plain; charset=utf-8"},
+		{"GET", "/app/assets/missing.js", "*/*", 404, "404 page not found\n", "text/plain; charset=utf-8"},
+		{"GET", "/app/api/users", html, 404, "{\"error\":\"not found\"}\n", "text/plain; charset=utf-8"},
+		{"POST", "/app/users/5", html, 404, "404 page not found\n", "text/plain; charset=utf-8"},
+		{"GET", "/application", html, 404, "404 page not found\n", "text/plain; charset=utf-8"},
+		{"GET", "/app/../" + filepath.Base(secret), "*/*", 404, "404 page not found\n", "text/plain; charset=utf-8"},
+	}
+	for i, c := range cases {
+		req, _ := http.NewRequest(c.method, "http://localhost", nil)
+		req.URL.Path = c.path
+		if c.accept != "" {
+			req.Header.Set("Accept", c.accept)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != c.code || w.Body.String() != c.body || w.Header().Get("Content-Type") != c.contentType {
+			t.Fatalf("unexpected response %d %q %q, at case %d", w.Code, w.Body.String(), w.Header().Get("Content-Type"), i)
+		}
+	}
+
+	// index document is revalidated, files have validators
+	req, _ := http.NewRequest("GET", "/app/users/5", nil)
+	req.Header.Set("Accept", html)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Header().Get("Cache-Control") != "no-cache" || w.Header().Get("Last-Modified") == "" {
+		t.Fatalf("expected index to be revalidated, but got headers: %v", w.Header())
+	}
+	req, _ = http.NewRequest("GET", "/app/assets/app.js", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	req.Header.Set("If-Modified-Since", w.Header().Get("Last-Modified"))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusNotModified {
+		t.Fatalf("expected file to be validated, but got: %d", w.Code)
+	}
+}
@@ -0,0 +1,174 @@
+package fastroute
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"sync/atomic"
+)
+
+// Splitter is a Router, which splits matched requests
+// between canary and stable routers, see Split.
+type Splitter struct {
+	percent uint64 // float64 bits, accessed atomically
+	seq     uint64 // random sequence, when there is no key
+	router  Router
+	key     func(*http.Request) string
+	observe func(*http.Request, bool)
+}
+
+// SplitOption configures Splitter.
+type SplitOption func(*Splitter)
+
+// SplitKey makes Splitter to decide the branch by hash
+// of the key, so the same client, identified by key,
+// is consistently served by the same branch, as long
+// as the percentage does not change. Requests with an
+// empty key are split randomly.
+func SplitKey(key func(*http.Request) string) SplitOption {
+	return func(s *Splitter) {
+		s.key = key
+	}
+}
+
+// SplitObserver registers a function, which is called
+// for every matched request with the branch serving it,
+// for example to tag metrics or response headers.
+func SplitObserver(observe func(req *http.Request, canary bool)) SplitOption {
+	return func(s *Splitter) {
+		s.observe = observe
+	}
+}
+
+// Split routes approximately the given percentage of
+// requests to canary router and the rest to stable
+// router. If the chosen router does not match the
+// request, the other one is tried.
+//
+// Without SplitKey option, every request is split
+// randomly. Both routers bind parameters as usual,
+// the percentage may be changed at any time by
+// SetPercent.
+func Split(percent float64, canary, stable Router, options ...SplitOption) *Splitter {
+	s := &Splitter{}
+	s.SetPercent(percent)
+	for _, option := range options {
+		option(s)
+	}
+
+	s.router = chain{RouterFunc(func(req *http.Request) http.Handler {
+		first, second := stable, canary
+		toCanary := s.canary(req)
+		if toCanary {
+			first, second = canary, stable
+		}
+
+		h := first.Route(req)
+		if h == nil {
+			toCanary = !toCanary
+			h = second.Route(req)
+		}
+		if h != nil && s.observe != nil {
+			s.observe(req, toCanary)
+		}
+		return h
+	}), []Router{canary, stable}}
+	return s
+}
+
+// SetPercent sets the percentage of requests
+// routed to canary, in range from 0 to 100.
+func (s *Splitter) SetPercent(percent float64) {
+	atomic.StoreUint64(&s.percent, math.Float64bits(math.Max(0, math.Min(100, percent))))
+}
+
+// Percent returns the percentage of requests
+// routed to canary.
+func (s *Splitter) Percent() float64 {
+	return math.Float64frombits(atomic.LoadUint64(&s.percent))
+}
+
+// Route routes request to canary or stable router.
+func (s *Splitter) Route(req *http.Request) http.Handler {
+	return s.router.Route(req)
+}
+
+// ServeHTTP routes and serves request, or serves
+// http.NotFound if none of routers matches.
+func (s *Splitter) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	s.router.ServeHTTP(w, req)
+}
+
+// Patterns lists patterns of canary and stable routers.
+func (s *Splitter) Patterns() []string {
+	patterns, _ := Patterns(s.router)
+	return patterns
+}
+
+func (s *Splitter) inspect() ([]RouteInfo, bool) {
+	return inspect(s.router)
+}
+
+// whether request falls into canary bucket
+func (s *Splitter) canary(req *http.Request) bool {
+	var n uint64
+	if key := s.keyOf(req); key != "" {
+		n = mix(hash(key))
+	} else {
+		n = mix(atomic.AddUint64(&s.seq, 1))
+	}
+	return float64(n%10000) < s.Percent()*100
+}
+
+func (s *Splitter) keyOf(req *http.Request) string {
+	if s.key == nil {
+		return ""
+	}
+	return s.key(req)
+}
+
+// fnv-1a hash of the key
+func hash(key string) uint64 {
+	h := uint64(14695981039346656037)
+	for i := 0; i < len(key); i++ {
+		h ^= uint64(key[i])
+		h *= 1099511628211
+	}
+	return h
+}
+
+// splitmix64 finalizer, spreads sequence uniformly
+func mix(z uint64) uint64 {
+	z += 0x9e3779b97f4a7c15
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
+
+// RemoteIP is a key function, which identifies
+// client by IP address of the request.
+func RemoteIP(req *http.Request) string {
+	if host, _, err := net.SplitHostPort(req.RemoteAddr); err == nil {
+		return host
+	}
+	return req.RemoteAddr
+}
+
+// HeaderKey returns a key function, which identifies
+// client by the value of the given request header.
+func HeaderKey(name string) func(*http.Request) string {
+	return func(req *http.Request) string {
+		return req.Header.Get(name)
+	}
+}
+
+// CookieKey returns a key function, which identifies
+// client by the value of the given cookie.
+func CookieKey(name string) func(*http.Request) string {
+	return func(req *http.Request) string {
+		if c, err := req.Cookie(name); err == nil {
+			return c.Value
+		}
+		return ""
+	}
+}
@@ -0,0 +1,121 @@
+package fastroute_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+func TestSplit(t *testing.T) {
+	t.Parallel()
+
+	branch := func(name string) fastroute.Router {
+		return fastroute.New("/users/:id", func(w http.ResponseWriter, req *http.Request) {
+			fmt.Fprintf(w, "%s %s", name, fastroute.Parameters(req).ByName("id"))
+		})
+	}
+
+	var observed int
+	splitter := fastroute.Split(30, branch("canary"), branch("stable"), fastroute.SplitObserver(func(req *http.Request, canary bool) {
+		if canary {
+			observed++
+		}
+	}))
+
+	serve := func(router fastroute.Router, remoteAddr string) string {
+		req, err := http.NewRequest("GET", "/users/5", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.RemoteAddr = remoteAddr
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if fastroute.Parameters(req) != nil {
+			t.Fatal("expected parameters to be recycled")
+		}
+		return w.Body.String()
+	}
+
+	var canary int
+	for i := 0; i < 10000; i++ {
+		switch serve(splitter, "") {
+		case "canary 5":
+			canary++
+		case "stable 5":
+		default:
+			t.Fatal("unexpected response")
+		}
+	}
+	if canary < 2700 || canary > 3300 {
+		t.Fatalf("expected approximately 30%% of requests to be served by canary, but got: %d", canary)
+	}
+	if observed != canary {
+		t.Fatalf("expected observer to see %d canary requests, but got: %d", canary, observed)
+	}
+
+	splitter.SetPercent(0)
+	if splitter.Percent() != 0 || serve(splitter, "") != "stable 5" {
+		t.Fatal("expected all requests to be served by stable")
+	}
+
+	splitter.SetPercent(100)
+	if serve(splitter, "") != "canary 5" {
+		t.Fatal("expected all requests to be served by canary")
+	}
+
+	if patterns, _ := fastroute.Patterns(splitter); len(patterns) != 2 {
+		t.Fatalf("expected both branches to be enumerated, but got: %v", patterns)
+	}
+}
+
+func TestSplitByKey(t *testing.T) {
+	t.Parallel()
+
+	branch := func(name string) fastroute.Router {
+		return fastroute.New("/", func(w http.ResponseWriter, req *http.Request) {
+			fmt.Fprint(w, name)
+		})
+	}
+	splitter := fastroute.Split(50, branch("canary"), branch("stable"), fastroute.SplitKey(fastroute.RemoteIP))
+
+	var canary int
+	for i := 0; i < 100; i++ {
+		addr := fmt.Sprintf("10.0.0.%d:%d", i, 1000+i)
+
+		served := make(map[string]bool)
+		for j := 0; j < 5; j++ {
+			req, _ := http.NewRequest("GET", "/", nil)
+			req.RemoteAddr = addr
+			w := httptest.NewRecorder()
+			splitter.ServeHTTP(w, req)
+			served[w.Body.String()] = true
+		}
+		if len(served) != 1 {
+			t.Fatalf("expected client: %s to be pinned to a single branch, but got: %v", addr, served)
+		}
+		if served["canary"] {
+			canary++
+		}
+	}
+	if canary < 25 || canary > 75 {
+		t.Fatalf("expected approximately half of clients to be served by canary, but got: %d", canary)
+	}
+}
+
+func TestSplitFallsBackToOtherBranch(t *testing.T) {
+	t.Parallel()
+
+	canary := fastroute.New("/new", http.NotFoundHandler())
+	stable := fastroute.New("/old", func(w http.ResponseWriter, req *http.Request) {})
+	splitter := fastroute.Split(100, canary, stable)
+
+	req, _ := http.NewRequest("GET", "/old", nil)
+	w := httptest.NewRecorder()
+	splitter.ServeHTTP(w, req)
+	if w.Code != 200 {
+		t.Fatalf("expected stable to serve request not matched by canary, but got: %d", w.Code)
+	}
+}
@@ -0,0 +1,154 @@
+package fastroute
+
+import (
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// RouteStats holds match statistics of a single
+// route pattern.
+type RouteStats struct {
+	// Matches is the number of times the route
+	// was matched.
+	Matches int64 `json:"matches"`
+
+	// LastMatched is the time the route was last
+	// matched, zero if it was not matched yet.
+	LastMatched time.Time `json:"last_matched"`
+}
+
+// Statistics collects match counts for the router
+// returned by Stats.
+type Statistics struct {
+	misses   int64
+	counters atomic.Value // map[string]*counter, copied on write
+	mu       sync.Mutex   // serializes writes of counters
+}
+
+type counter struct {
+	matches int64
+	last    int64 // unix nanoseconds
+}
+
+// OtherRoutes is the pattern, which Statistics count
+// matches of opaque static routes under, along with
+// the ones exceeding the limit of counters.
+const OtherRoutes = "<other>"
+
+// at most as many counters are registered on match
+const maxCounters = 1024
+
+// Stats wraps router in order to count matches per
+// route pattern and requests which were not matched.
+//
+// Counters of enumerable patterns are prepared upfront,
+// so matching a request costs only a few atomic
+// operations. Patterns which could not be enumerated,
+// get their counters registered on the first match, up
+// to a limit. Pattern(req) of opaque static routes is
+// the request path, so their matches are counted under
+// OtherRoutes, as well as matches over the limit.
+//
+// Statistics of enumerable routes are also reported
+// by Inspect.
+func Stats(router Router) (Router, *Statistics) {
+	s := &Statistics{}
+	counters := make(map[string]*counter)
+	patterns, _ := Patterns(router)
+	for _, pattern := range patterns {
+		counters[pattern] = &counter{}
+	}
+	s.counters.Store(counters)
+
+	return counted{wrapper{RouterFunc(func(req *http.Request) http.Handler {
+		h := router.Route(req)
+		if h == nil {
+			atomic.AddInt64(&s.misses, 1)
+			return nil
+		}
+
+		// request path is not a pattern of static route
+		c := s.counter(Pattern(req), carried(req) == nil)
+		atomic.AddInt64(&c.matches, 1)
+		atomic.StoreInt64(&c.last, time.Now().UnixNano())
+		return h
+	}), router}, s}, s
+}
+
+type counted struct {
+	wrapper
+	s *Statistics
+}
+
+func (c counted) inspect() ([]RouteInfo, bool) {
+	routes, ok := c.wrapper.inspect()
+	snapshot := c.s.Snapshot()
+	for i := range routes {
+		if stats, found := snapshot[routes[i].Pattern]; found {
+			routes[i].Stats = &stats
+		}
+	}
+	return routes, ok
+}
+
+// Snapshot returns current statistics per route pattern.
+// It is safe to call concurrently while serving requests.
+func (s *Statistics) Snapshot() map[string]RouteStats {
+	counters := s.counters.Load().(map[string]*counter)
+	snapshot := make(map[string]RouteStats, len(counters))
+	for pattern, c := range counters {
+		stats := RouteStats{Matches: atomic.LoadInt64(&c.matches)}
+		if last := atomic.LoadInt64(&c.last); last != 0 {
+			stats.LastMatched = time.Unix(0, last)
+		}
+		snapshot[pattern] = stats
+	}
+	return snapshot
+}
+
+// Misses returns the number of requests, which
+// were not matched by router.
+func (s *Statistics) Misses() int64 {
+	return atomic.LoadInt64(&s.misses)
+}
+
+// Reset sets all counters back to zero.
+func (s *Statistics) Reset() {
+	atomic.StoreInt64(&s.misses, 0)
+	for _, c := range s.counters.Load().(map[string]*counter) {
+		atomic.StoreInt64(&c.matches, 0)
+		atomic.StoreInt64(&c.last, 0)
+	}
+}
+
+func (s *Statistics) counter(pattern string, static bool) *counter {
+	counters := s.counters.Load().(map[string]*counter)
+	if c, ok := counters[pattern]; ok {
+		return c
+	}
+	if static || len(counters) >= maxCounters {
+		pattern = OtherRoutes
+		if c, ok := counters[pattern]; ok {
+			return c
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	counters = s.counters.Load().(map[string]*counter)
+	if c, ok := counters[pattern]; ok {
+		return c // registered concurrently
+	}
+
+	next := make(map[string]*counter, len(counters)+1)
+	for p, c := range counters {
+		next[p] = c
+	}
+	c := &counter{}
+	next[pattern] = c
+	s.counters.Store(next)
+	return c
+}
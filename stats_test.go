@@ -0,0 +1,94 @@
+package fastroute_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+func TestStats(t *testing.T) {
+	t.Parallel()
+	handler := func(w http.ResponseWriter, req *http.Request) {}
+
+	router, stats := fastroute.Stats(fastroute.Chain(
+		fastroute.New("/users/:id", handler),
+		fastroute.New("/status", handler),
+		fastroute.RouterFunc(func(req *http.Request) http.Handler {
+			if req.URL.Path == "/opaque" {
+				return http.HandlerFunc(handler)
+			}
+			return nil
+		}),
+	))
+
+	if patterns, _ := fastroute.Patterns(router); len(patterns) != 2 {
+		t.Fatalf("expected stats router to remain enumerable, but got: %v", patterns)
+	}
+
+	snapshot := stats.Snapshot()
+	if len(snapshot) != 2 || snapshot["/users/:id"].Matches != 0 || !snapshot["/status"].LastMatched.IsZero() {
+		t.Fatalf("expected prepared empty counters, but got: %+v", snapshot)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, path := range []string{"/users/1", "/users/2", "/status", "/opaque", "/none"} {
+				req, _ := http.NewRequest("GET", path, nil)
+				router.ServeHTTP(httptest.NewRecorder(), req)
+			}
+			stats.Snapshot()
+		}()
+	}
+	wg.Wait()
+
+	snapshot = stats.Snapshot()
+	expected := map[string]int64{"/users/:id": 20, "/status": 10, fastroute.OtherRoutes: 10}
+	for pattern, matches := range expected {
+		if snapshot[pattern].Matches != matches {
+			t.Fatalf("expected %d matches for: %s, but got: %d", matches, pattern, snapshot[pattern].Matches)
+		}
+		if snapshot[pattern].LastMatched.IsZero() {
+			t.Fatalf("expected last matched time to be set for: %s", pattern)
+		}
+	}
+	if stats.Misses() != 10 {
+		t.Fatalf("expected 10 misses, but got: %d", stats.Misses())
+	}
+
+	stats.Reset()
+
+	snapshot = stats.Snapshot()
+	if snapshot["/users/:id"].Matches != 0 || !snapshot[fastroute.OtherRoutes].LastMatched.IsZero() || stats.Misses() != 0 {
+		t.Fatalf("expected counters to be reset, but got: %+v", snapshot)
+	}
+}
+
+func TestStatsOfOpaquePaths(t *testing.T) {
+	t.Parallel()
+	handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {})
+
+	router, stats := fastroute.Stats(fastroute.Chain(
+		fastroute.New("/status", handler),
+		fastroute.RouterFunc(func(req *http.Request) http.Handler {
+			return handler // catch-all opaque route
+		}),
+	))
+	for i := 0; i < 2000; i++ {
+		req, _ := http.NewRequest("GET", fmt.Sprintf("/pages/%d", i), nil)
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	req, _ := http.NewRequest("GET", "/status", nil)
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	snapshot := stats.Snapshot()
+	if len(snapshot) != 2 || snapshot[fastroute.OtherRoutes].Matches != 2000 || snapshot["/status"].Matches != 1 {
+		t.Fatalf("expected opaque paths to share a counter, but got: %d counters", len(snapshot))
+	}
+}
@@ -0,0 +1,15 @@
+//go:build go1.13
+// +build go1.13
+
+package fastroute
+
+import "errors"
+
+// code of StatusError in the chain of err
+func statusCode(err error) (int, bool) {
+	var se StatusError
+	if errors.As(err, &se) {
+		return se.Code, true
+	}
+	return 0, false
+}
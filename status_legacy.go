@@ -0,0 +1,13 @@
+//go:build !go1.13
+// +build !go1.13
+
+package fastroute
+
+// code of StatusError, unwrapped only by
+// errors.As, which is available since go1.13
+func statusCode(err error) (int, bool) {
+	if se, ok := err.(StatusError); ok {
+		return se.Code, true
+	}
+	return 0, false
+}
@@ -0,0 +1,51 @@
+package fastroute
+
+import (
+	"net/http"
+	"strings"
+)
+
+// StripPrefixRouter wraps router in order to match requests
+// by path with prefix removed, the way http.StripPrefix
+// does, but as a Router, which may be chained:
+//
+//	fastroute.Chain(
+//		fastroute.StripPrefixRouter("/api/v1", v1),
+//		fastroute.StripPrefixRouter("/api/v2", v2),
+//	)
+//
+// Prefix is removed from both URL Path and RawPath, if it
+// is set. Requests not having the prefix are not matched,
+// rather than answered with 404 Not Found, so the following
+// routers may match them. As with http.StripPrefix, prefix
+// is removed as is, "/api" strips "/apiary" to "ary", and
+// the path may become empty, when it equals the prefix.
+//
+// Handler is served with the stripped path, which is
+// restored once it is served, like the path is restored
+// after the request is routed.
+func StripPrefixRouter(prefix string, router Router) Router {
+	return wrapper{RouterFunc(func(req *http.Request) http.Handler {
+		path, rawPath := req.URL.Path, req.URL.RawPath
+		p := strings.TrimPrefix(path, prefix)
+		rp := strings.TrimPrefix(rawPath, prefix)
+		if len(p) == len(path) || (rawPath != "" && len(rp) == len(rawPath)) {
+			return nil
+		}
+
+		req.URL.Path, req.URL.RawPath = p, rp
+		h := router.Route(req)
+		req.URL.Path, req.URL.RawPath = path, rawPath
+		if h == nil {
+			return nil
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			path, rawPath := req.URL.Path, req.URL.RawPath
+			req.URL.Path, req.URL.RawPath = p, rp
+			defer func() {
+				req.URL.Path, req.URL.RawPath = path, rawPath
+			}()
+			h.ServeHTTP(w, req)
+		})
+	}), router}
+}
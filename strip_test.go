@@ -0,0 +1,62 @@
+package fastroute_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+func TestStripPrefixRouter(t *testing.T) {
+	t.Parallel()
+	handler := func(w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte(req.URL.Path + " " + req.URL.RawPath + " " + fastroute.Parameters(req).ByName("name")))
+	}
+	router := fastroute.Chain(
+		fastroute.StripPrefixRouter("/api", fastroute.Chain(
+			fastroute.New("/users/:name", handler),
+			fastroute.RouterFunc(func(req *http.Request) http.Handler {
+				if req.URL.Path == "" {
+					return http.HandlerFunc(handler)
+				}
+				return nil
+			}),
+		)),
+		fastroute.StripPrefixRouter("/static", fastroute.New("/*file", handler)),
+		fastroute.New("/*any", func(w http.ResponseWriter, req *http.Request) {
+			w.Write([]byte("fallback " + req.URL.Path))
+		}),
+	)
+
+	cases := []struct{ url, body string }{
+		{"/api/users/gopher", "/users/gopher  gopher"},
+		{"/api/users/go%20pher", "/users/go pher  go pher"},
+		{"/static/a%2Fb.css", "/a/b.css /a%2Fb.css "},
+		{"/api", "  "}, // exact prefix leaves empty path
+		{"/api/missing", "fallback /api/missing"}, // not matched after stripping
+		{"/apiary", "fallback /apiary"},           // stripped to "ary", which is not matched
+		{"/static/css/a.css", "/css/a.css  "},
+		{"/other", "fallback /other"}, // missing prefix
+	}
+	for _, c := range cases {
+		req, _ := http.NewRequest("GET", c.url, nil)
+		path := req.URL.Path
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Body.String() != c.body {
+			t.Fatalf("expected %s to respond %q, but got: %q", c.url, c.body, w.Body.String())
+		}
+		if req.URL.Path != path {
+			t.Fatalf("expected path of %s to be restored, but got: %s", c.url, req.URL.Path)
+		}
+	}
+
+	// mirrors http.StripPrefix for escaped prefix in RawPath
+	stripped := fastroute.StripPrefixRouter("/a%2Fb", fastroute.New("/*rest", handler))
+	req, _ := http.NewRequest("GET", "/a%2Fb/c", nil)
+	if stripped.Route(req) != nil {
+		t.Fatal("expected prefix not to match decoded path, as http.StripPrefix does not")
+	}
+}
@@ -0,0 +1,111 @@
+package fastroute
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// SuggestRoutes lists patterns of router, which are
+// similar to the requested path, the most similar first.
+// It is meant to be used by not found handler, in
+// order to hint which route was probably meant:
+//
+//	var router fastroute.Router
+//	router = fastroute.Chain(routes, fastroute.RouterFunc(func(req *http.Request) http.Handler {
+//		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+//			w.WriteHeader(http.StatusNotFound)
+//			fmt.Fprintln(w, "did you mean:", fastroute.SuggestRoutes(req, routes))
+//		})
+//	}))
+//
+// Similarity is the edit distance between the path and
+// the pattern, having its parameters substituted by the
+// corresponding path segments. Only patterns within the
+// distance of a third of the path length are suggested.
+func SuggestRoutes(req *http.Request, router Router) []string {
+	path := req.URL.Path
+	patterns, _ := Patterns(router)
+	limit := len(path) / 3
+	if limit < 2 {
+		limit = 2
+	}
+
+	var suggested suggestions
+	for _, pattern := range patterns {
+		if d := distance(substitute(pattern, path), path); d <= limit {
+			suggested = append(suggested, suggestion{pattern, d})
+		}
+	}
+	sort.Stable(suggested)
+
+	result := make([]string, len(suggested))
+	for i, s := range suggested {
+		result[i] = s.pattern
+	}
+	return result
+}
+
+// substitutes pattern parameters with path segments
+func substitute(pattern, path string) string {
+	if strings.IndexAny(pattern, ":*") == -1 {
+		return pattern
+	}
+
+	segments := strings.Split(strings.TrimPrefix(pattern, "/"), "/")
+	values := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	for i, seg := range segments {
+		switch {
+		case len(seg) == 0 || (seg[0] != ':' && seg[0] != '*'):
+		case i >= len(values):
+			segments[i] = ""
+		case seg[0] == '*':
+			segments[i] = strings.Join(values[i:], "/")
+		default:
+			segments[i] = values[i]
+		}
+	}
+	return "/" + strings.Join(segments, "/")
+}
+
+// levenshtein distance between two strings
+func distance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	next := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		next[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			next[j] = min3(prev[j]+1, next[j-1]+1, prev[j-1]+cost)
+		}
+		prev, next = next, prev
+	}
+	return prev[len(b)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
+
+type suggestion struct {
+	pattern  string
+	distance int
+}
+
+type suggestions []suggestion
+
+func (s suggestions) Len() int           { return len(s) }
+func (s suggestions) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s suggestions) Less(i, j int) bool { return s[i].distance < s[j].distance }
@@ -0,0 +1,45 @@
+package fastroute_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+func TestSuggestRoutes(t *testing.T) {
+	t.Parallel()
+	handler := http.NotFoundHandler()
+
+	router := fastroute.Chain(
+		fastroute.New("/users", handler),
+		fastroute.New("/users/:id", handler),
+		fastroute.New("/users/:id/settings", handler),
+		fastroute.New("/files/*filepath", handler),
+		fastroute.New("/status", handler),
+	)
+
+	cases := map[string]string{
+		"/user/5":               "/users/:id",
+		"/users/5/setings":      "/users/:id/settings",
+		"/usrs":                 "/users",
+		"/file/css/style.css":   "/files/*filepath",
+		"/completely/unrelated": "",
+		"/statuses":             "/status",
+	}
+
+	for path, expected := range cases {
+		req, err := http.NewRequest("GET", path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		suggested := fastroute.SuggestRoutes(req, router)
+		if len(suggested) > 1 {
+			suggested = suggested[:1]
+		}
+		if strings.Join(suggested, "") != expected {
+			t.Fatalf("expected first suggestion for: %s to be: %s, but got: %v", path, expected, suggested)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package fastroute
+
+import "net/http"
+
+// TagOption configures TagPattern.
+type TagOption func(*tagger)
+
+type tagger struct {
+	request bool
+	skip    map[string]bool
+}
+
+// TagRequest sets the pattern header on the request
+// as well, so it can be read by upstream servers the
+// request is proxied to.
+func TagRequest() TagOption {
+	return func(t *tagger) {
+		t.request = true
+	}
+}
+
+// TagSkip excludes routes of the given patterns from
+// being tagged, for example sensitive admin routes.
+func TagSkip(patterns ...string) TagOption {
+	return func(t *tagger) {
+		for _, pattern := range patterns {
+			t.skip[pattern] = true
+		}
+	}
+}
+
+// TagPattern wraps router in order to expose the pattern
+// of the matched route in the given response header, like
+// "X-Route-Pattern". The header is set before the handler
+// is served, so the handler may still override or delete
+// it. Unless TagRequest option is given, the request
+// headers are left intact.
+func TagPattern(router Router, header string, options ...TagOption) Router {
+	t := &tagger{skip: make(map[string]bool)}
+	for _, option := range options {
+		option(t)
+	}
+	header = http.CanonicalHeaderKey(header)
+
+	return wrapper{RouterFunc(func(req *http.Request) http.Handler {
+		h := router.Route(req)
+		if h == nil {
+			return nil
+		}
+		pattern := Pattern(req)
+		if t.skip[pattern] {
+			return h
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			w.Header().Set(header, pattern)
+			if t.request {
+				req.Header.Set(header, pattern)
+			}
+			h.ServeHTTP(w, req)
+		})
+	}), router}
+}
+
+// Tagged wraps router in order to tag its routes, as
+// reported by Inspect, for tools which treat routes by
+// their kind, like "generated" or "external" ones.
+// Routing is not affected at all.
+func Tagged(router Router, tags ...string) Router {
+	return tagged{wrapper{router.Route, router}, tags}
+}
+
+type tagged struct {
+	wrapper
+	tags []string
+}
+
+func (t tagged) inspect() ([]RouteInfo, bool) {
+	routes, ok := t.wrapper.inspect()
+	for i := range routes {
+		routes[i].Tags = append(routes[i].Tags, t.tags...)
+	}
+	return routes, ok
+}
@@ -0,0 +1,81 @@
+package fastroute_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+func TestTagPattern(t *testing.T) {
+	t.Parallel()
+
+	var upstream string
+	handler := func(w http.ResponseWriter, req *http.Request) {
+		upstream = req.Header.Get("X-Route-Pattern")
+	}
+	router := fastroute.Chain(
+		fastroute.New("/users/:id", handler),
+		fastroute.New("/admin/*path", handler),
+		fastroute.New("/custom", func(w http.ResponseWriter, req *http.Request) {
+			w.Header().Set("X-Route-Pattern", "custom")
+		}),
+	)
+
+	cases := []struct {
+		options  []fastroute.TagOption
+		path     string
+		response string
+		request  string
+	}{
+		{nil, "/users/5", "/users/:id", ""},
+		{[]fastroute.TagOption{fastroute.TagRequest()}, "/users/5", "/users/:id", "/users/:id"},
+		{[]fastroute.TagOption{fastroute.TagSkip("/admin/*path")}, "/admin/users", "", ""},
+		{[]fastroute.TagOption{fastroute.TagSkip("/admin/*path")}, "/users/5", "/users/:id", ""},
+		{nil, "/custom", "custom", ""},
+		{nil, "/none", "", ""},
+	}
+
+	for i, c := range cases {
+		upstream = ""
+		req, err := http.NewRequest("GET", c.path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w := httptest.NewRecorder()
+		fastroute.TagPattern(router, "x-route-pattern", c.options...).ServeHTTP(w, req)
+
+		if actual := w.Header().Get("X-Route-Pattern"); actual != c.response {
+			t.Fatalf("expected response header: %q, but got: %q, case: %d", c.response, actual, i)
+		}
+		if upstream != c.request {
+			t.Fatalf("expected request header: %q, but got: %q, case: %d", c.request, upstream, i)
+		}
+	}
+}
+
+func TestTagged(t *testing.T) {
+	t.Parallel()
+	handler := func(w http.ResponseWriter, req *http.Request) {}
+	router := fastroute.Chain(
+		fastroute.New("/users/:id", handler),
+		fastroute.Tagged(fastroute.Chain(
+			fastroute.New("/generated/a", handler),
+			fastroute.Tagged(fastroute.New("/generated/b", handler), "external"),
+		), "generated"),
+	)
+
+	var tags []string
+	for _, info := range fastroute.Inspect(router) {
+		tags = append(tags, info.Pattern+"="+strings.Join(info.Tags, ","))
+	}
+	expected := "/users/:id= /generated/a=generated /generated/b=external,generated"
+	if strings.Join(tags, " ") != expected {
+		t.Fatalf("expected tags %q, but got: %q", expected, strings.Join(tags, " "))
+	}
+	if actual := routed(router, "/generated/b"); actual != "/generated/b" {
+		t.Fatalf("expected tagged route to match, but got: %s", actual)
+	}
+}
@@ -0,0 +1,255 @@
+package fastroute
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// NewTemplate creates Router, which matches path by the
+// template of google.api.http annotation, as used for
+// gRPC transcoding:
+//
+//	Template = "/" Segments [ Verb ] ;
+//	Segments = Segment { "/" Segment } ;
+//	Segment  = "*" | "**" | LITERAL | Variable ;
+//	Variable = "{" FieldPath [ "=" Segments ] "}" ;
+//	Verb     = ":" LITERAL ;
+//
+// A "*" matches a single path segment, while "**" matches
+// the rest of the path and may only be the last segment.
+// A variable binds the part of path matched by its
+// segments, which default to "*", as parameter named
+// by field path:
+//
+//	Template: /v1/{name=projects/*/locations/*}/datasets
+//
+//	Paths:
+//	 /v1/projects/p1/locations/eu/datasets   match: name="projects/p1/locations/eu"
+//	 /v1/projects/p1/datasets                no match
+//
+//	Template: /v1/{name}:cancel
+//
+//	Paths:
+//	 /v1/operations:cancel                   match: name="operations"
+//	 /v1/operations                          no match
+//
+// Handler is accepted in the same formats as by New.
+// It panics if template is malformed.
+func NewTemplate(template string, handler interface{}) Router {
+	h := toHandler(handler)
+	t, err := parseTemplate(template)
+	if err != nil {
+		panic(err.Error())
+	}
+	return templated{dynamic(template, len(t.vars), h, t.match), t}
+}
+
+type segmentKind int
+
+const (
+	literalSegment segmentKind = iota
+	wildSegment
+	deepSegment
+)
+
+type templateSegment struct {
+	kind    segmentKind
+	literal string
+}
+
+// variable spans segments from start to end
+type templateVar struct {
+	name       string
+	start, end int
+}
+
+type pathTemplate struct {
+	segments []templateSegment
+	vars     []templateVar
+	verb     string
+}
+
+func parseTemplate(template string) (*pathTemplate, error) {
+	invalid := func(reason string) error {
+		return errors.New(reason + ": " + template)
+	}
+	if template == "" || template[0] != '/' {
+		return nil, invalid("template must begin with slash")
+	}
+
+	t := &pathTemplate{}
+	body := template[1:]
+	var parts []string
+	var depth, from int
+	for i := 0; i < len(body); i++ {
+		switch body[i] {
+		case '{':
+			if depth++; depth > 1 {
+				return nil, invalid("variables cannot be nested")
+			}
+		case '}':
+			if depth--; depth < 0 {
+				return nil, invalid("unbalanced braces in template")
+			}
+		case '/':
+			if depth == 0 {
+				parts = append(parts, body[from:i])
+				from = i + 1
+			}
+		case ':':
+			if depth == 0 {
+				t.verb = body[i+1:]
+				if t.verb == "" || strings.IndexAny(t.verb, "/{}*:") != -1 {
+					return nil, invalid("verb must be a literal")
+				}
+				body = body[:i]
+			}
+		}
+	}
+	if depth != 0 {
+		return nil, invalid("unbalanced braces in template")
+	}
+	parts = append(parts, body[from:])
+
+	add := func(seg string) error {
+		switch {
+		case seg == "*":
+			t.segments = append(t.segments, templateSegment{kind: wildSegment})
+		case seg == "**":
+			t.segments = append(t.segments, templateSegment{kind: deepSegment})
+		case seg == "":
+			return invalid("empty segment in template")
+		case strings.IndexAny(seg, "*{}=:") != -1:
+			return invalid("not a valid literal segment in template")
+		default:
+			t.segments = append(t.segments, templateSegment{kind: literalSegment, literal: seg})
+		}
+		return nil
+	}
+
+	for _, part := range parts {
+		if len(part) == 0 || part[0] != '{' {
+			if err := add(part); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if part[len(part)-1] != '}' {
+			return nil, invalid("variable must span whole segments")
+		}
+		name, sub := part[1:len(part)-1], "*"
+		if pos := strings.IndexByte(name, '='); pos != -1 {
+			name, sub = name[:pos], name[pos+1:]
+		}
+		if !validFieldPath(name) {
+			return nil, invalid("not a valid variable name in template")
+		}
+		v := templateVar{name: name, start: len(t.segments)}
+		for _, seg := range strings.Split(sub, "/") {
+			if err := add(seg); err != nil {
+				return nil, err
+			}
+		}
+		v.end = len(t.segments)
+		t.vars = append(t.vars, v)
+	}
+
+	for i, seg := range t.segments {
+		if seg.kind == deepSegment && i != len(t.segments)-1 {
+			return nil, invalid("** must be the last segment in template")
+		}
+	}
+	return t, nil
+}
+
+// whether name is a dot separated path of identifiers
+func validFieldPath(name string) bool {
+	for _, ident := range strings.Split(name, ".") {
+		if ident == "" || ('0' <= ident[0] && ident[0] <= '9') {
+			return false
+		}
+		for i := 0; i < len(ident); i++ {
+			c := ident[i]
+			if !(c == '_' || '0' <= c && c <= '9' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// matches template to an url and pushes variables to ps
+func (t *pathTemplate) match(url string, ps *Params) bool {
+	if t.verb != "" {
+		if len(url) <= len(t.verb) || url[len(url)-len(t.verb)-1:] != ":"+t.verb {
+			return false
+		}
+		url = url[:len(url)-len(t.verb)-1]
+	}
+	if len(url) == 0 || url[0] != '/' {
+		return false
+	}
+
+	pos, vi, start := 1, 0, 0
+	for k, seg := range t.segments {
+		if vi < len(t.vars) && t.vars[vi].start == k {
+			start = pos
+		}
+		if pos > len(url) { // no segments left
+			if seg.kind != deepSegment {
+				return false
+			}
+			pos, start = len(url), len(url)
+		}
+		end := pos
+		for end < len(url) && url[end] != '/' {
+			end++
+		}
+		switch seg.kind {
+		case literalSegment:
+			if url[pos:end] != seg.literal {
+				return false
+			}
+		case wildSegment:
+			if end == pos {
+				return false
+			}
+		case deepSegment:
+			end = len(url)
+		}
+		if vi < len(t.vars) && t.vars[vi].end == k+1 {
+			ps.Append(t.vars[vi].name, url[start:end])
+			vi++
+		}
+		pos = end + 1
+	}
+	return pos == len(url)+1
+}
+
+type templated struct {
+	route
+	t *pathTemplate
+}
+
+func (r templated) inspect() ([]RouteInfo, bool) {
+	info := RouteInfo{Pattern: r.pattern, Static: true, Handler: handlerName(r.handler)}
+	for _, seg := range r.t.segments {
+		info.Static = info.Static && seg.kind == literalSegment
+		info.CatchAll = seg.kind == deepSegment
+	}
+	for _, v := range r.t.vars {
+		info.Params = append(info.Params, v.name)
+	}
+	return []RouteInfo{info}, true
+}
+
+func (r templated) trace(req *http.Request, sink func(TraceEvent)) http.Handler {
+	h := r.Route(req)
+	event := TraceEvent{Route: r.pattern, Matched: h != nil}
+	if h == nil {
+		event.Reason = "template mismatch"
+	}
+	sink(event)
+	return h
+}
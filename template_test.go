@@ -0,0 +1,115 @@
+package fastroute_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+func TestNewTemplate(t *testing.T) {
+	t.Parallel()
+	handler := func(w http.ResponseWriter, req *http.Request) {}
+
+	type kv map[string]string // reduce clutter
+
+	cases := []struct {
+		template, path string
+		params         kv
+		match          bool
+	}{
+		{"/v1", "/v1", kv{}, true},
+		{"/v1", "/v1/", nil, false},
+		{"/v1/*", "/v1/abc", kv{}, true},
+		{"/v1/*", "/v1/", nil, false},
+		{"/v1/*", "/v1/abc/def", nil, false},
+		{"/v1/**", "/v1/abc/def", kv{}, true},
+		{"/v1/**", "/v1", kv{}, true},
+		{"/v1/*/books", "/v1/shelves/books", kv{}, true},
+		{"/v1/{name}", "/v1/shelves", kv{"name": "shelves"}, true},
+		{"/v1/{name}", "/v1/shelves/1", nil, false},
+		{"/v1/{name=*}", "/v1/shelves", kv{"name": "shelves"}, true},
+		{"/v1/{name=**}", "/v1/shelves/1/books/2", kv{"name": "shelves/1/books/2"}, true},
+		{"/v1/{name=**}", "/v1", kv{"name": ""}, true},
+		{"/v1/{name=shelves/*}", "/v1/shelves/1", kv{"name": "shelves/1"}, true},
+		{"/v1/{name=shelves/*}", "/v1/books/1", nil, false},
+		{"/v1/{name=projects/*/locations/*}/datasets", "/v1/projects/p1/locations/eu/datasets", kv{"name": "projects/p1/locations/eu"}, true},
+		{"/v1/{name=projects/*/locations/*}/datasets", "/v1/projects/p1/datasets", nil, false},
+		{"/v1/{shelf}/books/{book}", "/v1/s1/books/b2", kv{"shelf": "s1", "book": "b2"}, true},
+		{"/v1/{book.name=shelves/*/books/*}", "/v1/shelves/s1/books/b2", kv{"book.name": "shelves/s1/books/b2"}, true},
+		{"/v1/{parent=shelves/*}/books/{book=**}", "/v1/shelves/s1/books/a/b", kv{"parent": "shelves/s1", "book": "a/b"}, true},
+		{"/v1/{name}:cancel", "/v1/op1:cancel", kv{"name": "op1"}, true},
+		{"/v1/{name}:cancel", "/v1/op1", nil, false},
+		{"/v1/{name}:cancel", "/v1/op1:undelete", nil, false},
+		{"/v1/{name=operations/**}:cancel", "/v1/operations/a/b:cancel", kv{"name": "operations/a/b"}, true},
+		{"/v1/*:verb", "/v1/abc:verb", kv{}, true},
+		{"/v1/a:verb", "/v1/a:verb", kv{}, true},
+		{"/v1/a:verb", "/v1/a", nil, false},
+	}
+
+	for i, c := range cases {
+		router := fastroute.NewTemplate(c.template, handler)
+		req, err := http.NewRequest("GET", c.path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		h := router.Route(req)
+		if c.match != (h != nil) {
+			t.Fatalf("expected match to be %t for: %s by template: %s, case: %d", c.match, c.path, c.template, i)
+		}
+		if !c.match {
+			continue
+		}
+		params := fastroute.Parameters(req)
+		if len(params) != len(c.params) {
+			t.Fatalf("expected params: %v, but got: %v, case: %d", c.params, params, i)
+		}
+		for key, val := range c.params {
+			if params.ByName(key) != val {
+				t.Fatalf("expected param %s to be %q, but got: %q, case: %d", key, val, params.ByName(key), i)
+			}
+		}
+		if fastroute.Pattern(req) != c.template {
+			t.Fatalf("expected pattern: %s, but got: %s", c.template, fastroute.Pattern(req))
+		}
+		fastroute.Recycle(req)
+	}
+
+	routes := fastroute.Inspect(fastroute.Chain(
+		fastroute.NewTemplate("/v1/{parent=shelves/*}/books/{book=**}", handler),
+		fastroute.NewTemplate("/v1/shelves:clear", handler),
+	))
+	if fmt.Sprint(routes[0].Params) != "[parent book]" || !routes[0].CatchAll || routes[0].Static || !routes[1].Static {
+		t.Fatalf("expected templates to be described, but got: %+v", routes)
+	}
+}
+
+func TestNewTemplateValidation(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{
+		"v1":             "template must begin with slash: v1",
+		"/v1//a":         "empty segment in template: /v1//a",
+		"/v1/{a={b}}":    "variables cannot be nested: /v1/{a={b}}",
+		"/v1/{name":      "unbalanced braces in template: /v1/{name",
+		"/v1/x{name}":    "not a valid literal segment in template: /v1/x{name}",
+		"/v1/{name}x":    "variable must span whole segments: /v1/{name}x",
+		"/v1/{1a}":       "not a valid variable name in template: /v1/{1a}",
+		"/v1/**/a":       "** must be the last segment in template: /v1/**/a",
+		"/v1/{name}:":    "verb must be a literal: /v1/{name}:",
+		"/v1:a/b":        "verb must be a literal: /v1:a/b",
+		"/v1/{name=a**}": "not a valid literal segment in template: /v1/{name=a**}",
+	}
+
+	for template, msg := range cases {
+		func() {
+			defer func() {
+				if err := recover(); fmt.Sprint(err) != msg {
+					t.Fatalf("expected panic: %q, but got: %v", msg, err)
+				}
+			}()
+			fastroute.NewTemplate(template, func(w http.ResponseWriter, req *http.Request) {})
+		}()
+	}
+}
@@ -0,0 +1,99 @@
+package fastroute
+
+import (
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// Switch controls whether the router returned by
+// Toggle is in service. It is safe to use concurrently
+// while serving requests.
+type Switch struct {
+	disabled int32
+	handler  atomic.Value // holds disabledHandler
+}
+
+type disabledHandler struct {
+	http.Handler
+}
+
+// Toggle wraps router in order to take its routes out
+// of service at runtime, without rebuilding the router.
+//
+// Initially switch is enabled and router is matched as
+// usual. When disabled, requests matched by router are
+// served by switch handler, which by default responds
+// with 503 Service Unavailable. If switch handler is set
+// to nil, disabled router does not match anything, so
+// the request falls through to the following routes,
+// which is useful to hide beta routes behind a flag.
+//
+// Disabled routes are reported by Inspect.
+func Toggle(router Router) (*Switch, Router) {
+	s := &Switch{}
+	s.SetHandler(Unavailable(0))
+
+	return s, toggled{wrapper{RouterFunc(func(req *http.Request) http.Handler {
+		h := router.Route(req)
+		if h == nil || s.Enabled() {
+			return h
+		}
+		Recycle(req)
+		if dh := s.handler.Load().(disabledHandler); dh.Handler != nil {
+			return dh.Handler
+		}
+		return nil
+	}), router}, s}
+}
+
+// Enable puts router back in service.
+func (s *Switch) Enable() {
+	atomic.StoreInt32(&s.disabled, 0)
+}
+
+// Disable takes router out of service.
+func (s *Switch) Disable() {
+	atomic.StoreInt32(&s.disabled, 1)
+}
+
+// Enabled reports whether router is in service.
+func (s *Switch) Enabled() bool {
+	return atomic.LoadInt32(&s.disabled) == 0
+}
+
+// SetHandler sets the handler serving requests matched
+// while router is disabled. If h is nil, disabled router
+// does not match any request.
+func (s *Switch) SetHandler(h http.Handler) {
+	s.handler.Store(disabledHandler{h})
+}
+
+// Unavailable returns a handler, which responds with
+// 503 Service Unavailable. Retry-After header is set
+// in seconds, if retryAfter is positive.
+func Unavailable(retryAfter time.Duration) http.Handler {
+	seconds := strconv.Itoa(int((retryAfter + time.Second - 1) / time.Second))
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if retryAfter > 0 {
+			w.Header().Set("Retry-After", seconds)
+		}
+		http.Error(w, "Service Unavailable", http.StatusServiceUnavailable)
+	})
+}
+
+type toggled struct {
+	wrapper
+	s *Switch
+}
+
+func (t toggled) inspect() ([]RouteInfo, bool) {
+	routes, ok := t.wrapper.inspect()
+	if !t.s.Enabled() {
+		for i := range routes {
+			routes[i].Disabled = true
+		}
+	}
+	return routes, ok
+}
@@ -0,0 +1,93 @@
+package fastroute_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+func TestToggle(t *testing.T) {
+	t.Parallel()
+
+	handler := func(w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte(fastroute.Parameters(req).ByName("id")))
+	}
+	fallback := fastroute.New("/beta/:id", func(w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte("fallback"))
+	})
+
+	sw, beta := fastroute.Toggle(fastroute.New("/beta/:id", handler))
+	router := fastroute.Chain(beta, fallback)
+
+	serve := func() *httptest.ResponseRecorder {
+		req, err := http.NewRequest("GET", "/beta/5", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if params := fastroute.Parameters(req); params != nil {
+			t.Fatal("expected parameters to be recycled")
+		}
+		return w
+	}
+
+	if w := serve(); w.Code != 200 || w.Body.String() != "5" {
+		t.Fatalf("expected enabled route to be served, but got: %d %s", w.Code, w.Body.String())
+	}
+
+	sw.Disable()
+	if w := serve(); w.Code != 503 || w.Header().Get("Retry-After") != "" {
+		t.Fatalf("expected disabled route to be unavailable, but got: %d", w.Code)
+	}
+	if routes := fastroute.Inspect(router); !routes[0].Disabled || routes[1].Disabled {
+		t.Fatalf("expected only toggled route to be reported as disabled: %+v", routes)
+	}
+
+	sw.SetHandler(fastroute.Unavailable(90 * time.Second))
+	if w := serve(); w.Code != 503 || w.Header().Get("Retry-After") != "90" {
+		t.Fatalf("expected retry after header, but got: %d %v", w.Code, w.Header())
+	}
+
+	sw.SetHandler(nil)
+	if w := serve(); w.Code != 200 || w.Body.String() != "fallback" {
+		t.Fatalf("expected disabled route to fall through, but got: %d %s", w.Code, w.Body.String())
+	}
+
+	sw.Enable()
+	if w := serve(); w.Body.String() != "5" || fastroute.Inspect(router)[0].Disabled {
+		t.Fatalf("expected route to be enabled again, but got: %s", w.Body.String())
+	}
+}
+
+func TestToggleRace(t *testing.T) {
+	t.Parallel()
+
+	sw, router := fastroute.Toggle(fastroute.New("/users/:id", func(w http.ResponseWriter, req *http.Request) {}))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				switch {
+				case i == 0 && j%2 == 0:
+					sw.Disable()
+				case i == 0:
+					sw.Enable()
+				case i == 1:
+					sw.SetHandler(http.NotFoundHandler())
+				default:
+					req, _ := http.NewRequest("GET", "/users/1", nil)
+					router.ServeHTTP(httptest.NewRecorder(), req)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+}
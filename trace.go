@@ -0,0 +1,141 @@
+package fastroute
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// TraceEvent describes a single attempt to match
+// the request, reported by Trace.
+type TraceEvent struct {
+	// Route is the pattern of attempted route, or
+	// the description of a router, which cannot be
+	// traced any further.
+	Route string
+
+	// Matched is true if the route matched request.
+	Matched bool
+
+	// Reason explains why the route did not match,
+	// for example "segment 2 literal mismatch".
+	Reason string
+}
+
+// Trace wraps router in order to report every route
+// attempted while matching the request, in order,
+// to sink. It is meant for debugging composition,
+// when a request is matched by an unexpected route.
+//
+// Routes created by New, Chain and MethodGroup are
+// traced one by one, explaining why they did not
+// match. Any other router is reported as a whole, by
+// its patterns if it is enumerable. Routers, which
+// are not wrapped by Trace, are not affected at all.
+func Trace(router Router, sink func(TraceEvent)) Router {
+	return wrapper{RouterFunc(func(req *http.Request) http.Handler {
+		return trace(router, req, sink)
+	}), router}
+}
+
+// used internally by routers of this package to
+// explain how request is matched
+type tracer interface {
+	trace(*http.Request, func(TraceEvent)) http.Handler
+}
+
+func trace(router Router, req *http.Request, sink func(TraceEvent)) http.Handler {
+	if t, ok := router.(tracer); ok {
+		return t.trace(req, sink)
+	}
+	h := router.Route(req)
+	event := TraceEvent{Route: fmt.Sprintf("%T", router), Matched: h != nil}
+	if patterns, _ := Patterns(router); len(patterns) > 0 {
+		event.Route = strings.Join(patterns, ", ")
+	}
+	if h == nil {
+		event.Reason = "not matched"
+	}
+	sink(event)
+	return h
+}
+
+func (c firstMatch) trace(req *http.Request, sink func(TraceEvent)) http.Handler {
+	for _, router := range c.routes {
+		if h := trace(router, req, sink); h != nil {
+			return h
+		}
+	}
+	return nil
+}
+
+func (r route) trace(req *http.Request, sink func(TraceEvent)) http.Handler {
+	h := r.Route(req)
+	event := TraceEvent{Route: r.pattern, Matched: h != nil}
+	if h == nil {
+		event.Reason = explain(r.pattern, req.URL.Path)
+	}
+	sink(event)
+	return h
+}
+
+func (m methodScoped) trace(req *http.Request, sink func(TraceEvent)) http.Handler {
+	for _, method := range m.methods {
+		if method == req.Method {
+			return trace(m.router, req, sink)
+		}
+	}
+	patterns, _ := Patterns(m.router)
+	for _, pattern := range patterns {
+		sink(TraceEvent{Route: pattern, Reason: "method mismatch"})
+	}
+	return nil
+}
+
+// explains why path does not match pattern, the
+// same way match would fail
+func explain(pattern, path string) string {
+	if strings.IndexAny(pattern, ":*\\") == -1 {
+		return "static path mismatch"
+	}
+
+	segments, _ := parse(pattern)
+	for i, segment := range segments {
+		n := i + 1
+		switch {
+		case len(path) == 0:
+			return fmt.Sprintf("segment %d missing", n)
+		case segment[1] == ':' && len(path) < 2:
+			return fmt.Sprintf("segment %d parameter %s missing", n, segment[1:])
+		case segment[1] == ':':
+			end := 1
+			for end < len(path) && path[end] != '/' {
+				end++
+			}
+			if pos := strings.IndexByte(segment, '\\'); pos != -1 && (end <= len(segment)-pos || !strings.HasSuffix(path[:end], segment[pos+1:])) {
+				return fmt.Sprintf("segment %d parameter %s suffix %s missing", n, segment[1:pos], segment[pos+1:])
+			}
+			path = path[end:]
+		case segment[1] == '*':
+			_, tail := splitTail(segments, pattern[len(pattern)-1] == '/')
+			if len(path) <= len(tail) || !strings.HasSuffix(path, tail) {
+				return fmt.Sprintf("segment %d catch-all tail %s missing", n, tail)
+			}
+			return "" // matches anything left
+		default:
+			segment = literal(segment)
+			if !strings.HasPrefix(path, segment) || (len(path) > len(segment) && path[len(segment)] != '/') {
+				return fmt.Sprintf("segment %d literal mismatch", n)
+			}
+			path = path[len(segment):]
+		}
+	}
+
+	switch ts := pattern[len(pattern)-1] == '/'; {
+	case ts && path == "":
+		return "trailing slash missing"
+	case !ts && path == "/":
+		return "unexpected trailing slash"
+	}
+	return "path too long"
+}
@@ -0,0 +1,81 @@
+package fastroute_test
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+func TestTrace(t *testing.T) {
+	t.Parallel()
+	handler := func(w http.ResponseWriter, req *http.Request) {}
+
+	var events []fastroute.TraceEvent
+	router := fastroute.Trace(fastroute.Chain(
+		fastroute.New("/status", handler),
+		fastroute.MethodGroup("POST", fastroute.New("/users/:id", handler)),
+		fastroute.New("/users/:id/", handler),
+		fastroute.New("/users/:id/posts", handler),
+		fastroute.New("/usersx/:id", handler),
+		fastroute.New("/users/:id/:post", handler),
+		fastroute.New("/users/:id", handler),
+	), func(event fastroute.TraceEvent) {
+		events = append(events, event)
+	})
+
+	req, err := http.NewRequest("GET", "/users/5", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h := router.Route(req); h == nil {
+		t.Fatal("expected traced router to match request")
+	}
+	fastroute.Recycle(req)
+
+	expected := []fastroute.TraceEvent{
+		{"/status", false, "static path mismatch"},
+		{"/users/:id", false, "method mismatch"},
+		{"/users/:id/", false, "trailing slash missing"},
+		{"/users/:id/posts", false, "segment 3 missing"},
+		{"/usersx/:id", false, "segment 1 literal mismatch"},
+		{"/users/:id/:post", false, "segment 3 missing"},
+		{"/users/:id", true, ""},
+	}
+	if !reflect.DeepEqual(events, expected) {
+		t.Fatalf("expected trace events: %+v, but got: %+v", expected, events)
+	}
+
+	cases := map[string]string{
+		"/users/5/posts/": "path too long",
+		"/users/":         "segment 2 parameter :id missing",
+	}
+	for path, reason := range cases {
+		events = nil
+		req, _ := http.NewRequest("GET", path, nil)
+		router.Route(req)
+		fastroute.Recycle(req)
+		if events[len(events)-1].Reason != reason {
+			t.Fatalf("expected last route to fail for: %s, with reason: %s, but got: %+v", path, reason, events)
+		}
+	}
+
+	events = nil
+	req, _ = http.NewRequest("GET", "/users/5/", nil)
+	fastroute.Trace(fastroute.New("/users/:id", handler), func(event fastroute.TraceEvent) {
+		events = append(events, event)
+	}).Route(req)
+	if len(events) != 1 || events[0].Reason != "unexpected trailing slash" {
+		t.Fatalf("expected trailing slash to be reported, but got: %+v", events)
+	}
+
+	events = nil
+	req, _ = http.NewRequest("GET", "/users/5/posts/", nil)
+	fastroute.Trace(fastroute.New("/users/*path/posts", handler), func(event fastroute.TraceEvent) {
+		events = append(events, event)
+	}).Route(req)
+	if len(events) != 1 || events[0].Reason != "segment 2 catch-all tail /posts missing" {
+		t.Fatalf("expected catch-all tail to be reported, but got: %+v", events)
+	}
+}
@@ -0,0 +1,152 @@
+package fastroute
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// MatchVerbose routes the request like router.Route
+// does, and also reports the way through composition
+// of router to the matched route, for debugging deep
+// compositions, for example:
+//
+//	[chain[1] method(GET) chain[0] /users/:id]
+//
+// Chains of several routers are identified by the index
+// of the matched one, method groups and hosts by methods and host
+// pattern they are scoped by, and the route itself by
+// pattern. Other wrappers are passed through silently.
+// If the matched route is opaque, only the pattern of
+// request is reported.
+//
+// As with Route, the returned handler must be served
+// or request recycled.
+func MatchVerbose(router Router, req *http.Request) (http.Handler, []string) {
+	h := router.Route(req)
+	if h == nil {
+		return nil, nil
+	}
+	pattern := Pattern(req)
+	if way, ok := locate(router, req, pattern); ok {
+		return h, way
+	}
+	return h, []string{pattern}
+}
+
+// finds the way to route of the given pattern,
+// which could have matched request
+func locate(router Router, req *http.Request, pattern string) ([]string, bool) {
+	switch t := router.(type) {
+	case route:
+		return []string{t.pattern}, t.pattern == pattern
+	case templated:
+		return []string{t.pattern}, t.pattern == pattern
+	case firstMatch:
+		return locate(t.chain, req, pattern)
+	case chain:
+		for i, child := range t.routes {
+			if way, ok := locate(child, req, pattern); ok && len(t.routes) == 1 {
+				return way, true // like routes grouped by method
+			} else if ok {
+				return append([]string{fmt.Sprintf("chain[%d]", i)}, way...), true
+			}
+		}
+	case methodScoped:
+		for _, method := range t.methods {
+			if method == req.Method {
+				way, ok := locate(t.router, req, pattern)
+				return append([]string{"method(" + strings.Join(t.methods, "|") + ")"}, way...), ok
+			}
+		}
+	case hosted:
+		if t.matches(req) {
+			way, ok := locate(t.router, req, pattern)
+			return append([]string{"host(" + t.pattern + ")"}, way...), ok
+		}
+	case composite:
+		for _, child := range t.children() {
+			if way, ok := locate(child, req, pattern); ok {
+				return way, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// MatchIndex routes the request like router.Route does,
+// and also reports the index of the matched route among
+// the routes described by Inspect, so that routes of the
+// same pattern, scoped by different methods, hosts or
+// languages, are told apart.
+//
+// The index is -1, if the matched route is opaque, or
+// router cannot be enumerated. As with Route, the
+// returned handler must be served or request recycled.
+func MatchIndex(router Router, req *http.Request) (http.Handler, int) {
+	h := router.Route(req)
+	if h == nil {
+		return nil, -1
+	}
+	if i, ok := index(router, req, Pattern(req)); ok {
+		return h, i
+	}
+	return h, -1
+}
+
+// finds the index of route of the given pattern,
+// which could have matched request, the same way
+// as locate does
+func index(router Router, req *http.Request, pattern string) (int, bool) {
+	switch t := router.(type) {
+	case route:
+		return 0, t.pattern == pattern
+	case templated:
+		return 0, t.pattern == pattern
+	case methodScoped:
+		for _, method := range t.methods {
+			if method == req.Method {
+				return index(t.router, req, pattern)
+			}
+		}
+		return 0, false
+	case hosted:
+		if t.matches(req) {
+			return index(t.router, req, pattern)
+		}
+		return 0, false
+	case negotiated:
+		language := Parameters(req).ByName("language")
+		var offset int
+		for i, tag := range t.tags {
+			if tag == language {
+				n, ok := index(t.routers[i], req, pattern)
+				return offset + n, ok
+			}
+			routes, _ := inspect(t.routers[i])
+			offset += len(routes)
+		}
+		return 0, false
+	case composite:
+		var offset int
+		for _, child := range t.children() {
+			if n, ok := index(child, req, pattern); ok {
+				return offset + n, true
+			}
+			routes, _ := inspect(child)
+			offset += len(routes)
+		}
+	}
+	return 0, false
+}
+
+// whether request host matches, regardless of port
+func (h hosted) matches(req *http.Request) bool {
+	host, _ := splitHostPort(req.Host)
+	if h.labels == nil {
+		name, _ := splitHostPort(h.pattern)
+		return strings.EqualFold(host, name)
+	}
+	var discard Params
+	return matchHost(h.labels, host, &discard)
+}
@@ -0,0 +1,108 @@
+package fastroute_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+func TestMatchVerbose(t *testing.T) {
+	t.Parallel()
+	handler := func(w http.ResponseWriter, req *http.Request) {}
+
+	router := fastroute.Chain(
+		fastroute.New("/status", handler),
+		fastroute.MethodGroup("GET|HEAD", fastroute.Chain(
+			fastroute.New("/users", handler),
+			fastroute.New("/users/:id", handler),
+		)),
+		fastroute.MethodGroup("POST", fastroute.New("/users/:id", handler)),
+		fastroute.Host("tenant-:id.example.com", fastroute.New("/users/:id", handler)),
+		fastroute.Guard(fastroute.New("/admin", handler), func(*http.Request) error { return nil }),
+		fastroute.RouterFunc(func(req *http.Request) http.Handler {
+			if req.URL.Path == "/opaque" {
+				return http.HandlerFunc(handler)
+			}
+			return nil
+		}),
+	)
+
+	cases := []struct {
+		method, url string
+		way         string
+	}{
+		{"GET", "/status", "[chain[0] /status]"},
+		{"GET", "/users/1", "[chain[1] method(GET|HEAD) chain[1] /users/:id]"},
+		{"POST", "/users/1", "[chain[2] method(POST) /users/:id]"},
+		{"PUT", "http://tenant-1.example.com/users/1", "[chain[3] host(tenant-:id.example.com) /users/:id]"},
+		{"GET", "/admin", "[chain[4] /admin]"},
+		{"GET", "/opaque", "[/opaque]"},
+		{"GET", "/none", "[]"},
+	}
+
+	for _, c := range cases {
+		req, err := http.NewRequest(c.method, c.url, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		h, way := fastroute.MatchVerbose(router, req)
+		if fmt.Sprint(way) != c.way {
+			t.Fatalf("expected %s %s to be matched by way of: %s, but got: %v", c.method, c.url, c.way, way)
+		}
+		if (h != nil) != (way != nil) {
+			t.Fatalf("expected handler only if matched: %s %s", c.method, c.url)
+		}
+		fastroute.Recycle(req)
+	}
+}
+
+func TestMatchIndex(t *testing.T) {
+	t.Parallel()
+	handler := func(w http.ResponseWriter, req *http.Request) {}
+
+	router := fastroute.Chain(
+		fastroute.New("/status", handler),
+		fastroute.MethodGroup("GET", fastroute.New("/users/:id", handler)),
+		fastroute.MethodGroup("POST", fastroute.New("/users/:id", handler)),
+		fastroute.Host("a.example.com", fastroute.New("/users/:id", handler)),
+		fastroute.Languages(map[string]fastroute.Router{
+			"en": fastroute.Chain(fastroute.New("/about", handler), fastroute.New("/contact", handler)),
+			"de": fastroute.New("/about", handler),
+		}, "en"),
+		fastroute.RouterFunc(func(req *http.Request) http.Handler {
+			if req.URL.Path == "/opaque" {
+				return http.HandlerFunc(handler)
+			}
+			return nil
+		}),
+	)
+
+	cases := []struct {
+		method, url, language string
+		index                 int
+	}{
+		{"GET", "/status", "", 0},
+		{"GET", "/users/1", "", 1},
+		{"POST", "/users/1", "", 2},
+		{"PUT", "http://a.example.com/users/1", "", 3},
+		{"GET", "/about", "de", 4},
+		{"GET", "/about", "en", 5},
+		{"GET", "/contact", "", 6},
+		{"GET", "/opaque", "", -1},
+		{"GET", "/none", "", -1},
+	}
+	for _, c := range cases {
+		req, _ := http.NewRequest(c.method, c.url, nil)
+		req.Header.Set("Accept-Language", c.language)
+		h, i := fastroute.MatchIndex(router, req)
+		if i != c.index {
+			t.Fatalf("expected %s %s %s to be matched by route: %d, but got: %d", c.method, c.url, c.language, c.index, i)
+		}
+		if h == nil && c.url != "/none" {
+			t.Fatalf("expected handler for: %s %s", c.method, c.url)
+		}
+		fastroute.Recycle(req)
+	}
+}
@@ -0,0 +1,68 @@
+package fastroute
+
+import (
+	"net/http"
+	"sort"
+)
+
+// VersionOption configures Version.
+type VersionOption func(*versioner)
+
+type versioner struct {
+	fallback Router
+}
+
+// VersionDefault routes requests of unknown or missing
+// version by the given router, instead of falling through.
+func VersionDefault(router Router) VersionOption {
+	return func(v *versioner) {
+		v.fallback = router
+	}
+}
+
+// Version creates Router, which routes request by the
+// router of its API version, told by extract. Versions
+// may be taken from a header, media type parameter of
+// Accept header, or a path prefix:
+//
+//	fastroute.Version(map[string]fastroute.Router{
+//		"v1": v1routes,
+//		"v2": v2routes,
+//	}, func(req *http.Request) string {
+//		return req.Header.Get("X-API-Version")
+//	}, fastroute.VersionDefault(v2routes))
+//
+// So route trees of each version are kept apart. Requests
+// of unknown or missing version are not matched, unless
+// VersionDefault is given. Routes of all the versions are
+// enumerated in the order of versions, followed by routes
+// of the default router.
+func Version(versions map[string]Router, extract func(*http.Request) string, options ...VersionOption) Router {
+	v := &versioner{}
+	for _, option := range options {
+		option(v)
+	}
+
+	keys := make([]string, 0, len(versions))
+	for version := range versions {
+		keys = append(keys, version)
+	}
+	sort.Strings(keys)
+	routers := make([]Router, 0, len(keys)+1)
+	for _, version := range keys {
+		routers = append(routers, versions[version])
+	}
+	if v.fallback != nil {
+		routers = append(routers, v.fallback)
+	}
+
+	return wrapper{RouterFunc(func(req *http.Request) http.Handler {
+		if router, ok := versions[extract(req)]; ok {
+			return router.Route(req)
+		}
+		if v.fallback != nil {
+			return v.fallback.Route(req)
+		}
+		return nil
+	}), Chain(routers...)}
+}
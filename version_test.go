@@ -0,0 +1,72 @@
+package fastroute_test
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/fastroute"
+)
+
+func TestVersion(t *testing.T) {
+	t.Parallel()
+
+	versionedRoutes := func(version string) fastroute.Router {
+		return fastroute.New("/users/:id", func(w http.ResponseWriter, req *http.Request) {
+			fmt.Fprint(w, version, " ", fastroute.Parameters(req).ByName("id"))
+		})
+	}
+	v1, v2 := versionedRoutes("v1"), versionedRoutes("v2")
+
+	// version as media type parameter, like: application/json; version=v2
+	accepted := func(req *http.Request) string {
+		_, params, _ := mime.ParseMediaType(req.Header.Get("Accept"))
+		return params["version"]
+	}
+
+	cases := []struct {
+		router fastroute.Router
+		accept string
+		body   string
+	}{
+		{fastroute.Version(map[string]fastroute.Router{"v1": v1, "v2": v2}, accepted), "application/json; version=v1", "v1 5"},
+		{fastroute.Version(map[string]fastroute.Router{"v1": v1, "v2": v2}, accepted), "application/json; version=v2", "v2 5"},
+		{fastroute.Version(map[string]fastroute.Router{"v1": v1, "v2": v2}, accepted), "application/json; version=v3", "404 page not found\n"},
+		{fastroute.Version(map[string]fastroute.Router{"v1": v1, "v2": v2}, accepted), "application/json", "404 page not found\n"},
+		{fastroute.Version(map[string]fastroute.Router{"v1": v1}, accepted, fastroute.VersionDefault(v2)), "application/json; version=v3", "v2 5"},
+		{fastroute.Version(map[string]fastroute.Router{"v1": v1}, accepted, fastroute.VersionDefault(v2)), "", "v2 5"},
+		{fastroute.Version(map[string]fastroute.Router{"v1": v1}, accepted, fastroute.VersionDefault(v2)), "application/json; version=v1", "v1 5"},
+	}
+	for i, c := range cases {
+		req, _ := http.NewRequest("GET", "/users/5", nil)
+		req.Header.Set("Accept", c.accept)
+		w := httptest.NewRecorder()
+		c.router.ServeHTTP(w, req)
+		if w.Body.String() != c.body {
+			t.Fatalf("expected response: %q, but got: %q, case: %d", c.body, w.Body.String(), i)
+		}
+	}
+
+	// version as path prefix
+	prefixed := fastroute.Version(map[string]fastroute.Router{
+		"v1": fastroute.New("/v1/users/:id", func(w http.ResponseWriter, req *http.Request) {}),
+		"v2": fastroute.New("/v2/users/:id", func(w http.ResponseWriter, req *http.Request) {}),
+	}, func(req *http.Request) string {
+		if parts := strings.SplitN(req.URL.Path, "/", 3); len(parts) == 3 {
+			return parts[1]
+		}
+		return ""
+	})
+	if actual := routed(prefixed, "/v2/users/5"); actual != "/v2/users/:id id=5" {
+		t.Fatalf("expected version by path prefix, but got: %q", actual)
+	}
+	if actual := routed(prefixed, "/v3/users/5"); actual != "no match" {
+		t.Fatalf("expected unknown version not to match, but got: %q", actual)
+	}
+	if patterns, ok := fastroute.Patterns(prefixed); !ok || strings.Join(patterns, " ") != "/v1/users/:id /v2/users/:id" {
+		t.Fatalf("expected versioned routes to be enumerated, but got: %v", patterns)
+	}
+}